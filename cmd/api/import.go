@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/22Fariz22/musiclab/config"
+	lyricsProvider "github.com/22Fariz22/musiclab/internal/lyrics/provider"
+	lyricsRepository "github.com/22Fariz22/musiclab/internal/lyrics/repository"
+	lyricsUseCase "github.com/22Fariz22/musiclab/internal/lyrics/usecase"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+// runImport подкоманда import: импорт песен из файла без запуска сервера.
+// Использование: api import [-format csv|ndjson] [-enrich] [-overwrite] <file>
+func runImport(cfg *config.Config, db *sqlx.DB, redisClient *redis.Client, appLogger logger.Logger, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "формат файла: csv или ndjson, по умолчанию по расширению")
+	var opts models.ImportOptions
+	flags.BoolVar(&opts.Enrich, "enrich", false, "дозагрузить из источников недостающие текст, ссылку и дату выхода")
+	flags.BoolVar(&opts.Overwrite, "overwrite", false, "перезаписать существующие песни")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: import [-format csv|ndjson] [-enrich] [-overwrite] <file>")
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = models.DetectImportFormat(path, "")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	lyricsRepo := lyricsRepository.NewLyricsRepository(db, appLogger)
+	lyricsProviders, err := lyricsProvider.NewFromConfig(cfg, appLogger)
+	if err != nil {
+		return err
+	}
+	lyricsUC := lyricsUseCase.NewLyricsUseCase(cfg, lyricsRepo, lyricsProviders, redisClient, appLogger)
+
+	report, err := lyricsUC.ImportSongs(context.Background(), *format, file, opts)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/22Fariz22/musiclab/config"
 	"github.com/22Fariz22/musiclab/internal/server"
@@ -72,6 +73,14 @@ func main() {
 	defer redisClient.Close()
 	appLogger.Info("Redis connected")
 
+	// Подкоманда import: импорт песен из файла без запуска сервера
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(cfg, psqlDB, redisClient, appLogger, os.Args[2:]); err != nil {
+			appLogger.Fatalf("Import failed: %v", err)
+		}
+		return
+	}
+
 	s := server.NewServer(cfg, psqlDB, redisClient, appLogger)
 	if err = s.Run(); err != nil {
 		appLogger.Fatalf("Error in main NewServer(): ", err)
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -17,6 +18,14 @@ type Config struct {
 	Logger     Logger
 	Redis      RedisConfig
 	API        APIConfig
+	Search     SearchConfig
+	Providers  ProvidersConfig
+	Enrichment EnrichmentConfig
+	Refresh    RefreshConfig
+	Import     ImportConfig
+	Export     ExportConfig
+	Language   LanguageConfig
+	Groups     GroupsConfig
 }
 
 // Server config struct
@@ -75,10 +84,64 @@ type RedisConfig struct {
 }
 
 type APIConfig struct {
-	MaxRetries    int
-	RetryDelay    time.Duration
-	APIPath       string
-	APICtxTimeout time.Duration
+	MaxRetries       int
+	RetryDelay       time.Duration
+	MaxRetryDelay    time.Duration
+	RequestTimeout   time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	APIPath          string
+	APICtxTimeout    time.Duration
+}
+
+// Search config struct
+type SearchConfig struct {
+	SimilarityThreshold float64
+	SuggestLimit        int
+}
+
+// Lyrics providers config struct
+type ProvidersConfig struct {
+	Order   []string
+	FileDir string
+}
+
+// Background enrichment config struct
+type EnrichmentConfig struct {
+	Workers      int
+	PollInterval time.Duration
+	JobTimeout   time.Duration
+	MaxAttempts  int
+}
+
+// Background refresh config struct
+type RefreshConfig struct {
+	Interval      time.Duration
+	MaxAge        time.Duration
+	RetryAfter    time.Duration
+	BatchSize     int
+	RatePerSecond float64
+}
+
+// Bulk import config struct
+type ImportConfig struct {
+	BatchSize int
+}
+
+// Library export config struct
+type ExportConfig struct {
+	FetchSize int
+}
+
+// Language detection config struct
+type LanguageConfig struct {
+	MinConfidence float64
+}
+
+// Orphan groups cleanup config struct
+type GroupsConfig struct {
+	CleanupInterval time.Duration
+	CleanupMinAge   time.Duration
 }
 
 // LoadConfig reads environment variables into a Config struct
@@ -136,10 +199,48 @@ func LoadConfig() (*Config, error) {
 			SongTextCasheTTL: getEnvAsDuration("SONG_TEXT_CACHE_TTL", 6*time.Hour),
 		},
 		API: APIConfig{
-			MaxRetries:    getEnvAsInt("MAX_RETRIES", 3),
-			RetryDelay:    getEnvAsDuration("RETRY_DELAY", 1*time.Second),
-			APIPath:       getEnv("API_PATH", "/info"),
-			APICtxTimeout: getEnvAsDuration("API_CTX_TIMEOUT", 5*time.Second),
+			MaxRetries:       getEnvAsInt("MAX_RETRIES", 3),
+			RetryDelay:       getEnvAsDuration("RETRY_DELAY", 1*time.Second),
+			MaxRetryDelay:    getEnvAsDuration("MAX_RETRY_DELAY", 10*time.Second),
+			RequestTimeout:   getEnvAsDuration("API_REQUEST_TIMEOUT", 5*time.Second),
+			BreakerThreshold: getEnvAsInt("BREAKER_FAILURE_THRESHOLD", 5),
+			BreakerCooldown:  getEnvAsDuration("BREAKER_COOLDOWN", 30*time.Second),
+			APIPath:          getEnv("API_PATH", "/info"),
+			APICtxTimeout:    getEnvAsDuration("API_CTX_TIMEOUT", 5*time.Second),
+		},
+		Search: SearchConfig{
+			SimilarityThreshold: getEnvAsFloat("SEARCH_SIMILARITY_THRESHOLD", 0.3),
+			SuggestLimit:        getEnvAsInt("SEARCH_SUGGEST_LIMIT", 5),
+		},
+		Providers: ProvidersConfig{
+			Order:   getEnvAsSlice("LYRICS_PROVIDERS", []string{"http"}),
+			FileDir: getEnv("LYRICS_FILE_DIR", "./lyrics"),
+		},
+		Enrichment: EnrichmentConfig{
+			Workers:      getEnvAsInt("ENRICHMENT_WORKERS", 4),
+			PollInterval: getEnvAsDuration("ENRICHMENT_POLL_INTERVAL", time.Second),
+			JobTimeout:   getEnvAsDuration("ENRICHMENT_JOB_TIMEOUT", 2*time.Minute),
+			MaxAttempts:  getEnvAsInt("ENRICHMENT_MAX_ATTEMPTS", 3),
+		},
+		Refresh: RefreshConfig{
+			Interval:      getEnvAsDuration("REFRESH_INTERVAL", time.Hour),
+			MaxAge:        getEnvAsDuration("REFRESH_MAX_AGE", 30*24*time.Hour),
+			RetryAfter:    getEnvAsDuration("REFRESH_RETRY_AFTER", 24*time.Hour),
+			BatchSize:     getEnvAsInt("REFRESH_BATCH_SIZE", 100),
+			RatePerSecond: getEnvAsFloat("REFRESH_RATE", 2),
+		},
+		Import: ImportConfig{
+			BatchSize: getEnvAsInt("IMPORT_BATCH_SIZE", 500),
+		},
+		Export: ExportConfig{
+			FetchSize: getEnvAsInt("EXPORT_FETCH_SIZE", 1000),
+		},
+		Language: LanguageConfig{
+			MinConfidence: getEnvAsFloat("LANGUAGE_MIN_CONFIDENCE", 0.3),
+		},
+		Groups: GroupsConfig{
+			CleanupInterval: getEnvAsDuration("GROUP_CLEANUP_INTERVAL", 24*time.Hour),
+			CleanupMinAge:   getEnvAsDuration("GROUP_CLEANUP_MIN_AGE", 24*time.Hour),
 		},
 	}, nil
 }
@@ -175,3 +276,24 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valStr := getEnv(key, "")
+	if val, err := strconv.ParseFloat(valStr, 64); err == nil {
+		return val
+	}
+	return defaultValue
+}
+
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	var values []string
+	for _, val := range strings.Split(getEnv(key, ""), ",") {
+		if val = strings.TrimSpace(val); val != "" {
+			values = append(values, val)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
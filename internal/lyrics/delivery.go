@@ -6,9 +6,62 @@ import (
 
 type Handlers interface {
 	Ping() echo.HandlerFunc
+	Health() echo.HandlerFunc
 	DeleteSongByID() echo.HandlerFunc
 	UpdateTrackByID() echo.HandlerFunc
 	CreateTrack() echo.HandlerFunc
+	GetEnrichmentJob() echo.HandlerFunc
+	GetSongByID() echo.HandlerFunc
 	GetSongVerseByID() echo.HandlerFunc
+	GetSongVerses() echo.HandlerFunc
+	GetSongSections() echo.HandlerFunc
+	GetTranslations() echo.HandlerFunc
+	GetTranslation() echo.HandlerFunc
+	SaveTranslation() echo.HandlerFunc
+	DeleteTranslation() echo.HandlerFunc
+	GetSongLRC() echo.HandlerFunc
+	SetSongLRC() echo.HandlerFunc
+	DeleteSongLRC() echo.HandlerFunc
 	GetLibrary() echo.HandlerFunc
+	ExportLibrary() echo.HandlerFunc
+	SearchLyrics() echo.HandlerFunc
+	Suggest() echo.HandlerFunc
+	ImportSongs() echo.HandlerFunc
+
+	CreateAlbum() echo.HandlerFunc
+	GetAlbums() echo.HandlerFunc
+	GetAlbumByID() echo.HandlerFunc
+	UpdateAlbumByID() echo.HandlerFunc
+	DeleteAlbumByID() echo.HandlerFunc
+	AttachSongsToAlbum() echo.HandlerFunc
+	DetachSongFromAlbum() echo.HandlerFunc
+
+	CreateGroup() echo.HandlerFunc
+	GetGroups() echo.HandlerFunc
+	GetGroupByID() echo.HandlerFunc
+	UpdateGroupByID() echo.HandlerFunc
+	DeleteGroupByID() echo.HandlerFunc
+	AddGroupAlias() echo.HandlerFunc
+	DeleteGroupAlias() echo.HandlerFunc
+	MergeGroups() echo.HandlerFunc
+
+	GetGenres() echo.HandlerFunc
+	CreateGenre() echo.HandlerFunc
+	UpdateGenreByID() echo.HandlerFunc
+	DeleteGenreByID() echo.HandlerFunc
+	AttachGenres() echo.HandlerFunc
+	DetachGenres() echo.HandlerFunc
+
+	GetTags() echo.HandlerFunc
+	DeleteTagByID() echo.HandlerFunc
+	AttachTags() echo.HandlerFunc
+	DetachTags() echo.HandlerFunc
+
+	TriggerRefresh() echo.HandlerFunc
+	GetLastRefresh() echo.HandlerFunc
+}
+
+// RefreshTrigger ручной запуск обновления песен в рамках времени жизни сервера
+type RefreshTrigger interface {
+	Trigger(limit int) error
 }
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/labstack/echo/v4"
+)
+
+// TriggerRefresh запускает обновление устаревших и неполных песен.
+// @Summary Запуск обновления песен
+// @Description Запускает в фоне повторную загрузку песен без текста, ссылки или даты выхода и песен, давно не обновлявшихся
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Сколько песен обновить, по умолчанию REFRESH_BATCH_SIZE"
+// @Success 202 {object} map[string]string "Обновление запущено"
+// @Failure 400 {object} map[string]string "Некорректный limit"
+// @Failure 409 {object} map[string]string "Обновление уже идет"
+// @Failure 503 {object} map[string]string "Сервер запускается или останавливается"
+// @Router /lyrics/admin/refresh [post]
+func (h lyricsHandlers) TriggerRefresh() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler TriggerRefresh")
+
+		limit := 0
+		if limitParam := c.QueryParam("limit"); limitParam != "" {
+			var err error
+			limit, err = strconv.Atoi(limitParam)
+			if err != nil || limit <= 0 {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Invalid limit",
+				})
+			}
+		}
+
+		if err := h.refresh.Trigger(limit); err != nil {
+			if errors.Is(err, lyrics.ErrRefreshInProgress) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": err.Error(),
+				})
+			}
+			if errors.Is(err, lyrics.ErrRefreshUnavailable) {
+				return c.JSON(http.StatusServiceUnavailable, map[string]string{
+					"error": err.Error(),
+				})
+			}
+			h.logger.Errorf("Error in TriggerRefresh: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to start refresh",
+			})
+		}
+
+		return c.JSON(http.StatusAccepted, map[string]string{
+			"message":    "refresh started",
+			"status_url": h.cfg.Middleware.MiddlewareAPIVersion + "/lyrics/admin/refresh",
+		})
+	}
+}
+
+// GetLastRefresh возвращает итог последнего обновления песен.
+// @Summary Итог обновления песен
+// @Description Счетчики и изменения последнего или текущего прогона обновления
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} models.RefreshReport "Итог прогона"
+// @Failure 404 {object} map[string]string "Обновление еще не запускалось"
+// @Router /lyrics/admin/refresh [get]
+func (h lyricsHandlers) GetLastRefresh() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report, ok := h.lyricsUsecase.LastRefresh()
+		if !ok {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "no refresh runs yet",
+			})
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,347 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// CreateAlbum создает альбом.
+// @Summary Создание альбома
+// @Description Создает альбом группы, группа создается при необходимости
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param body body models.AlbumRequest true "Данные альбома"
+// @Success 201 {object} models.Album "Созданный альбом"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 409 {object} map[string]string "Альбом уже существует"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/albums [post]
+func (h lyricsHandlers) CreateAlbum() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler CreateAlbum")
+
+		var albumRequest models.AlbumRequest
+		if err := c.Bind(&albumRequest); err != nil {
+			h.logger.Debug("in handler CreateAlbum() Bind() return error: ", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid JSON format",
+			})
+		}
+
+		if err := c.Validate(&albumRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+		}
+
+		album, err := h.lyricsUsecase.CreateAlbum(c.Request().Context(), albumRequest)
+		if err != nil {
+			if errors.Is(err, lyrics.ErrConflict) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "album already exists",
+				})
+			}
+			h.logger.Errorf("failed to create album: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to create album",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, album)
+	}
+}
+
+// GetAlbums возвращает список альбомов.
+// @Summary Список альбомов
+// @Description Возвращает альбомы с количеством песен, с фильтром по группе
+// @Tags Albums
+// @Produce json
+// @Param group query string false "Фильтр по группе"
+// @Param page query int false "Номер страницы"
+// @Param limit query int false "Количество записей на странице"
+// @Success 200 {object} map[string]interface{} "Список альбомов"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/albums [get]
+func (h lyricsHandlers) GetAlbums() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		page, err := strconv.Atoi(c.QueryParam("page"))
+		if err != nil || page <= 0 {
+			page = 1
+		}
+
+		limit, err := strconv.Atoi(c.QueryParam("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		albums, total, err := h.lyricsUsecase.GetAlbums(ctx, c.QueryParam("group"), page, limit)
+		if err != nil {
+			h.logger.Errorf("Error in GetAlbums: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch albums",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"data":  albums,
+		})
+	}
+}
+
+// GetAlbumByID возвращает альбом с песнями.
+// @Summary Получение альбома
+// @Description Возвращает альбом и его песни в порядке номеров треков
+// @Tags Albums
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Success 200 {object} models.Album "Альбом"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Альбом не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/albums/{id} [get]
+func (h lyricsHandlers) GetAlbumByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid album ID",
+			})
+		}
+
+		album, err := h.lyricsUsecase.GetAlbumByID(c.Request().Context(), uint(id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "album not found",
+				})
+			}
+			h.logger.Errorf("Error in GetAlbumByID: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch album",
+			})
+		}
+
+		return c.JSON(http.StatusOK, album)
+	}
+}
+
+// UpdateAlbumByID обновляет альбом.
+// @Summary Обновление альбома
+// @Description Обновляет название, группу и дату выхода альбома. Группу альбома с песнями сменить нельзя.
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param body body models.AlbumRequest true "Данные альбома"
+// @Success 200 {object} map[string]string "Альбом обновлен"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 404 {object} map[string]string "Альбом не найден"
+// @Failure 409 {object} map[string]string "Альбом с таким названием уже есть у группы или у альбома с песнями меняется группа"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/albums/{id} [put]
+func (h lyricsHandlers) UpdateAlbumByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid album ID",
+			})
+		}
+
+		var albumRequest models.AlbumRequest
+		if err := c.Bind(&albumRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid JSON format",
+			})
+		}
+
+		if err := c.Validate(&albumRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+		}
+
+		err = h.lyricsUsecase.UpdateAlbumByID(c.Request().Context(), uint(id), albumRequest)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "album not found",
+				})
+			}
+			if errors.Is(err, lyrics.ErrAlbumHasSongs) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "album has songs, detach them before changing the group",
+				})
+			}
+			if errors.Is(err, lyrics.ErrConflict) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "album already exists",
+				})
+			}
+			h.logger.Errorf("failed to update album: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to update album",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "album updated successfully",
+		})
+	}
+}
+
+// DeleteAlbumByID удаляет альбом.
+// @Summary Удаление альбома
+// @Description Удаляет альбом, песни альбома остаются в библиотеке
+// @Tags Albums
+// @Param id path int true "ID альбома"
+// @Success 204 "Альбом удален"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Альбом не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/albums/{id} [delete]
+func (h lyricsHandlers) DeleteAlbumByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid album ID",
+			})
+		}
+
+		err = h.lyricsUsecase.DeleteAlbumByID(c.Request().Context(), uint(id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "album not found",
+				})
+			}
+			h.logger.Errorf("failed to delete album: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to delete album",
+			})
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// AttachSongsToAlbum добавляет песни в альбом.
+// @Summary Добавление песен в альбом
+// @Description Привязывает песни к альбому с номерами треков, песня может быть только в одном альбоме.
+// @Description В альбом можно добавить только песни группы альбома.
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param body body models.AlbumTracksRequest true "Песни и номера треков"
+// @Success 200 {object} map[string]string "Песни добавлены"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 404 {object} map[string]string "Альбом или песня не найдены"
+// @Failure 409 {object} map[string]string "Номер трека уже занят или песня другой группы"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/albums/{id}/songs [post]
+func (h lyricsHandlers) AttachSongsToAlbum() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid album ID",
+			})
+		}
+
+		var tracksRequest models.AlbumTracksRequest
+		if err := c.Bind(&tracksRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid JSON format",
+			})
+		}
+
+		if err := c.Validate(&tracksRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+		}
+
+		err = h.lyricsUsecase.AttachSongsToAlbum(c.Request().Context(), uint(id), tracksRequest.Tracks)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": err.Error(),
+				})
+			}
+			if errors.Is(err, lyrics.ErrConflict) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": err.Error(),
+				})
+			}
+			h.logger.Errorf("failed to attach songs to album: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to attach songs to album",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "songs attached successfully",
+		})
+	}
+}
+
+// DetachSongFromAlbum убирает песню из альбома.
+// @Summary Удаление песни из альбома
+// @Description Отвязывает песню от альбома, сама песня не удаляется
+// @Tags Albums
+// @Param id path int true "ID альбома"
+// @Param song_id path int true "ID песни"
+// @Success 204 "Песня убрана из альбома"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Песни нет в альбоме"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/albums/{id}/songs/{song_id} [delete]
+func (h lyricsHandlers) DetachSongFromAlbum() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		albumID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || albumID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid album ID",
+			})
+		}
+
+		songID, err := strconv.Atoi(c.Param("song_id"))
+		if err != nil || songID <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid song ID",
+			})
+		}
+
+		err = h.lyricsUsecase.DetachSongFromAlbum(c.Request().Context(), uint(albumID), uint(songID))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found in album",
+				})
+			}
+			h.logger.Errorf("failed to detach song from album: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to detach song from album",
+			})
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
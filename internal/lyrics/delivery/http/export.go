@@ -0,0 +1,239 @@
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// exportFlushRows через сколько строк выгрузка отправляется клиенту
+const exportFlushRows = 500
+
+// ExportLibrary выгрузка библиотеки в файл.
+// @Summary Выгрузка библиотеки
+// @Description Выгружает все песни, подходящие под фильтры библиотеки, в CSV, NDJSON или JSON.
+// @Description Строки читаются из Postgres курсором и сразу отправляются клиенту; параметры page, limit, after и count игнорируются.
+// @Tags Songs
+// @Produce json,text/csv,application/x-ndjson
+// @Param format query string false "Формат: csv, ndjson или json (по умолчанию json)"
+// @Param group query string false "Фильтр по группе или любому участнику песни"
+// @Param song query string false "Фильтр по названию песни"
+// @Param text query string false "Фильтр по тексту"
+// @Param release_date query string false "Фильтр по дате выпуска: dd.mm.yyyy или yyyy-mm-dd"
+// @Param released_from query string false "Вышли не раньше даты"
+// @Param released_to query string false "Вышли не позже даты"
+// @Param year query int false "Фильтр по году выпуска"
+// @Param sort query string false "Сортировка, как у библиотеки"
+// @Param fields query string false "Поля выгрузки, как у библиотеки"
+// @Param album query string false "Фильтр по названию альбома"
+// @Param album_id query int false "Фильтр по ID альбома"
+// @Param language query string false "Фильтр по языку текста (en, pt - вместе с pt-BR); und - язык не определен"
+// @Param genre query string false "Фильтр по жанрам через запятую, жанр включает свои поджанры"
+// @Param genre_match query string false "any - песня любого из жанров (по умолчанию), all - каждого из жанров"
+// @Param tag query string false "Фильтр по меткам через запятую"
+// @Param tag_match query string false "any - песня с любой из меток (по умолчанию), all - с каждой из меток"
+// @Success 200 {file} file "Файл выгрузки"
+// @Failure 400 {object} map[string]string "Некорректные фильтры или формат"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /lyrics/export [get]
+func (h lyricsHandlers) ExportLibrary() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		format := c.QueryParam("format")
+		if format == "" {
+			format = models.ExportFormatJSON
+		}
+		contentType, ok := exportContentTypes[format]
+		if !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid format, expected csv, ndjson or json",
+			})
+		}
+
+		filter, err := parseLibraryFilter(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		// Выгружается вся выборка, пагинация не нужна
+		filter.After = nil
+		filter.WithTotal = false
+
+		res := c.Response()
+		buffer := bufio.NewWriterSize(res, 32<<10)
+		exporter := newLibraryExporter(format, buffer, filter.Fields)
+
+		// Заголовки отправляются с первой строкой, чтобы ошибку до начала выгрузки можно было вернуть кодом 500
+		started := false
+		start := func() error {
+			started = true
+
+			// Выгрузка всего каталога дольше WRITE_TIMEOUT сервера
+			if err := http.NewResponseController(res).SetWriteDeadline(time.Time{}); err != nil {
+				h.logger.Warnf("Export: cannot reset write deadline: %v", err)
+			}
+
+			filename := fmt.Sprintf("musiclab-library-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+			res.Header().Set(echo.HeaderContentType, contentType)
+			res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+			res.WriteHeader(http.StatusOK)
+			return exporter.Begin()
+		}
+
+		rows := 0
+		err = h.lyricsUsecase.ExportLibrary(ctx, filter, func(song models.Song) error {
+			if !started {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+
+			if err := exporter.Write(song); err != nil {
+				return err
+			}
+
+			rows++
+			if rows%exportFlushRows == 0 {
+				if err := buffer.Flush(); err != nil {
+					return err
+				}
+				res.Flush()
+			}
+			return nil
+		})
+		if err == nil && !started {
+			err = start()
+		}
+		if err == nil {
+			err = exporter.End()
+		}
+		if err == nil {
+			err = buffer.Flush()
+		}
+
+		if err != nil {
+			if !started {
+				h.logger.Errorf("Error in ExportLibrary: %v", err)
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to export library",
+				})
+			}
+
+			// Код 200 уже отправлен: обрываем соединение, чтобы клиент не принял обрезанный файл за целый
+			h.logger.Errorf("Error in ExportLibrary after %d rows: %v", rows, err)
+			panic(http.ErrAbortHandler)
+		}
+
+		h.logger.Debugf("Exported %d songs as %s", rows, format)
+		return nil
+	}
+}
+
+// exportContentTypes Content-Type для форматов выгрузки
+var exportContentTypes = map[string]string{
+	models.ExportFormatCSV:    "text/csv; charset=utf-8",
+	models.ExportFormatNDJSON: "application/x-ndjson",
+	models.ExportFormatJSON:   echo.MIMEApplicationJSONCharsetUTF8,
+}
+
+// libraryExporter пишет песни в поток в одном из форматов выгрузки
+type libraryExporter interface {
+	Begin() error
+	Write(song models.Song) error
+	End() error
+}
+
+func newLibraryExporter(format string, w io.Writer, fields []string) libraryExporter {
+	if len(fields) == 0 {
+		fields = models.LibraryFields
+	}
+
+	switch format {
+	case models.ExportFormatCSV:
+		return &csvExporter{w: csv.NewWriter(w), fields: fields}
+	case models.ExportFormatNDJSON:
+		return &ndjsonExporter{encoder: json.NewEncoder(w), fields: fields}
+	default:
+		return &jsonExporter{w: w, fields: fields}
+	}
+}
+
+// csvExporter CSV с заголовком, колонки в snake_case
+type csvExporter struct {
+	w      *csv.Writer
+	fields []string
+}
+
+func (e *csvExporter) Begin() error {
+	return e.w.Write(models.ExportCSVHeader(e.fields))
+}
+
+func (e *csvExporter) Write(song models.Song) error {
+	return e.w.Write(song.CSVRecord(e.fields))
+}
+
+func (e *csvExporter) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ndjsonExporter по объекту на строку, ключи как в data библиотеки
+type ndjsonExporter struct {
+	encoder *json.Encoder
+	fields  []string
+}
+
+func (e *ndjsonExporter) Begin() error {
+	return nil
+}
+
+func (e *ndjsonExporter) Write(song models.Song) error {
+	return e.encoder.Encode(song.Project(e.fields))
+}
+
+func (e *ndjsonExporter) End() error {
+	return nil
+}
+
+// jsonExporter один массив объектов, ключи как в data библиотеки
+type jsonExporter struct {
+	w      io.Writer
+	fields []string
+	rows   int
+}
+
+func (e *jsonExporter) Begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExporter) Write(song models.Song) error {
+	data, err := json.Marshal(song.Project(e.fields))
+	if err != nil {
+		return err
+	}
+
+	if e.rows > 0 {
+		if _, err := io.WriteString(e.w, ",\n"); err != nil {
+			return err
+		}
+	}
+	e.rows++
+
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExporter) End() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exportSongs = []models.Song{
+	{ID: 1, SongName: "Uprising"},
+	{ID: 2, SongName: "Starlight, live"},
+	{ID: 3, SongName: "Hysteria"},
+}
+
+func TestLibraryExporter(t *testing.T) {
+	fields := []string{"id", "song"}
+
+	tests := []struct {
+		name   string
+		format string
+		songs  []models.Song
+		want   string
+	}{
+		{name: "csv empty", format: models.ExportFormatCSV, songs: nil, want: "id,song\n"},
+		{name: "csv one", format: models.ExportFormatCSV, songs: exportSongs[:1], want: "id,song\n1,Uprising\n"},
+		{name: "csv many", format: models.ExportFormatCSV, songs: exportSongs,
+			want: "id,song\n1,Uprising\n2,\"Starlight, live\"\n3,Hysteria\n"},
+		{name: "ndjson empty", format: models.ExportFormatNDJSON, songs: nil, want: ""},
+		{name: "ndjson one", format: models.ExportFormatNDJSON, songs: exportSongs[:1], want: `{"ID":1,"SongName":"Uprising"}` + "\n"},
+		{name: "ndjson many", format: models.ExportFormatNDJSON, songs: exportSongs,
+			want: `{"ID":1,"SongName":"Uprising"}` + "\n" + `{"ID":2,"SongName":"Starlight, live"}` + "\n" + `{"ID":3,"SongName":"Hysteria"}` + "\n"},
+		{name: "json empty", format: models.ExportFormatJSON, songs: nil, want: "[]\n"},
+		{name: "json one", format: models.ExportFormatJSON, songs: exportSongs[:1], want: `[{"ID":1,"SongName":"Uprising"}]` + "\n"},
+		{name: "json many", format: models.ExportFormatJSON, songs: exportSongs,
+			want: `[{"ID":1,"SongName":"Uprising"},` + "\n" + `{"ID":2,"SongName":"Starlight, live"},` + "\n" + `{"ID":3,"SongName":"Hysteria"}]` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			exporter := newLibraryExporter(tt.format, &out, fields)
+
+			require.NoError(t, exporter.Begin())
+			for _, song := range tt.songs {
+				require.NoError(t, exporter.Write(song))
+			}
+			require.NoError(t, exporter.End())
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+// exportUseCase отдает песни выгрузки по одной, err возвращается после songs
+type exportUseCase struct {
+	lyrics.UseCase
+
+	songs []models.Song
+	err   error
+}
+
+func (u exportUseCase) ExportLibrary(ctx context.Context, filter models.LibraryFilter, fn func(models.Song) error) error {
+	for _, song := range u.songs {
+		if err := fn(song); err != nil {
+			return err
+		}
+	}
+	return u.err
+}
+
+// failingWriter принимает заголовки, но не тело ответа
+type failingWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *failingWriter) Header() http.Header { return w.header }
+
+func (w *failingWriter) WriteHeader(status int) { w.status = status }
+
+func (w *failingWriter) Write([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func exportContext(w http.ResponseWriter, query string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/lyrics/export?"+query, nil)
+	return echo.New().NewContext(req, w)
+}
+
+func exportHandler(uc lyrics.UseCase) echo.HandlerFunc {
+	h := lyricsHandlers{cfg: &config.Config{}, lyricsUsecase: uc, logger: utils.CreateTestLogger()}
+	return h.ExportLibrary()
+}
+
+func TestExportLibrary_StreamsJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := exportHandler(exportUseCase{songs: exportSongs[:2]})(exportContext(rec, "format=json&fields=song"))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "attachment")
+	assert.JSONEq(t, `[{"ID":1,"SongName":"Uprising"},{"ID":2,"SongName":"Starlight, live"}]`, rec.Body.String())
+}
+
+func TestExportLibrary_WriteErrorAbortsStream(t *testing.T) {
+	w := &failingWriter{header: http.Header{}}
+	handler := exportHandler(exportUseCase{songs: exportSongs})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		_ = handler(exportContext(w, "format=ndjson"))
+	})
+	assert.Equal(t, http.StatusOK, w.status)
+}
+
+func TestExportLibrary_UseCaseErrorAfterStartAbortsStream(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handler := exportHandler(exportUseCase{songs: exportSongs[:1], err: errors.New("cursor failed")})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		_ = handler(exportContext(rec, "format=csv"))
+	})
+}
+
+func TestExportLibrary_UseCaseErrorBeforeStart(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := exportHandler(exportUseCase{err: errors.New("begin failed")})(exportContext(rec, "format=csv"))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition))
+}
+
+func TestExportLibrary_InvalidFormat(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := exportHandler(exportUseCase{})(exportContext(rec, "format=xml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
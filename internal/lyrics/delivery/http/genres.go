@@ -0,0 +1,277 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// bindGenreRequest разбирает и проверяет тело запроса жанра, имя приводится к виду без пробелов по краям
+func bindGenreRequest(c echo.Context) (models.GenreRequest, map[string]interface{}) {
+	var genreRequest models.GenreRequest
+	if err := c.Bind(&genreRequest); err != nil {
+		return genreRequest, map[string]interface{}{"error": "invalid JSON format"}
+	}
+
+	genreRequest.Name = strings.TrimSpace(genreRequest.Name)
+	if err := c.Validate(&genreRequest); err != nil {
+		return genreRequest, map[string]interface{}{
+			"error":   "validation failed",
+			"details": err.Error(),
+		}
+	}
+
+	return genreRequest, nil
+}
+
+// bindSongGenresRequest разбирает и проверяет тело запроса на изменение жанров песен
+func bindSongGenresRequest(c echo.Context) (models.SongGenresRequest, map[string]interface{}) {
+	var request models.SongGenresRequest
+	if err := c.Bind(&request); err != nil {
+		return request, map[string]interface{}{"error": "invalid JSON format"}
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return request, map[string]interface{}{
+			"error":   "validation failed",
+			"details": err.Error(),
+		}
+	}
+
+	return request, nil
+}
+
+// GetGenres возвращает дерево жанров.
+// @Summary Дерево жанров
+// @Description Возвращает жанры верхнего уровня с вложенными поджанрами и количеством песен каждого жанра
+// @Tags Genres
+// @Produce json
+// @Success 200 {array} models.GenreInfo "Дерево жанров"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/genres [get]
+func (h lyricsHandlers) GetGenres() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		genres, err := h.lyricsUsecase.GetGenres(c.Request().Context())
+		if err != nil {
+			h.logger.Errorf("Error in GetGenres: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch genres",
+			})
+		}
+
+		return c.JSON(http.StatusOK, genres)
+	}
+}
+
+// CreateGenre создает жанр.
+// @Summary Создание жанра
+// @Description Создает жанр, с parent_id - поджанр указанного жанра
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param body body models.GenreRequest true "Данные жанра"
+// @Success 201 {object} models.GenreInfo "Созданный жанр"
+// @Failure 400 {object} map[string]string "Некорректный запрос или родительский жанр не найден"
+// @Failure 409 {object} map[string]string "Жанр уже существует"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/genres [post]
+func (h lyricsHandlers) CreateGenre() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler CreateGenre")
+
+		genreRequest, badRequest := bindGenreRequest(c)
+		if badRequest != nil {
+			return c.JSON(http.StatusBadRequest, badRequest)
+		}
+
+		genre, err := h.lyricsUsecase.CreateGenre(c.Request().Context(), genreRequest)
+		if err != nil {
+			switch {
+			case errors.Is(err, lyrics.ErrInvalidGenreParent):
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "parent genre not found",
+				})
+			case errors.Is(err, lyrics.ErrConflict):
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "genre already exists",
+				})
+			}
+			h.logger.Errorf("failed to create genre: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to create genre",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, genre)
+	}
+}
+
+// UpdateGenreByID переименовывает жанр и меняет родительский жанр.
+// @Summary Изменение жанра
+// @Description Переименовывает жанр и переносит его под parent_id, без parent_id жанр становится жанром верхнего уровня.
+// @Description Жанр нельзя перенести в самого себя или в свой поджанр.
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param id path int true "ID жанра"
+// @Param body body models.GenreRequest true "Данные жанра"
+// @Success 200 {object} models.GenreInfo "Измененный жанр"
+// @Failure 400 {object} map[string]string "Некорректный запрос или родительский жанр"
+// @Failure 404 {object} map[string]string "Жанр не найден"
+// @Failure 409 {object} map[string]string "Имя занято другим жанром"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/genres/{id} [put]
+func (h lyricsHandlers) UpdateGenreByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler UpdateGenreByID")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid genre ID",
+			})
+		}
+
+		genreRequest, badRequest := bindGenreRequest(c)
+		if badRequest != nil {
+			return c.JSON(http.StatusBadRequest, badRequest)
+		}
+
+		genre, err := h.lyricsUsecase.UpdateGenreByID(c.Request().Context(), uint(id), genreRequest)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "genre not found",
+				})
+			case errors.Is(err, lyrics.ErrInvalidGenreParent):
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "parent genre not found or is the genre itself or its subgenre",
+				})
+			case errors.Is(err, lyrics.ErrConflict):
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "genre name is already taken",
+				})
+			}
+			h.logger.Errorf("failed to update genre: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to update genre",
+			})
+		}
+
+		return c.JSON(http.StatusOK, genre)
+	}
+}
+
+// DeleteGenreByID удаляет жанр.
+// @Summary Удаление жанра
+// @Description Удаляет жанр у всех песен, его поджанры переходят к родительскому жанру удаленного
+// @Tags Genres
+// @Param id path int true "ID жанра"
+// @Success 204 "Жанр удален"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Жанр не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/genres/{id} [delete]
+func (h lyricsHandlers) DeleteGenreByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler DeleteGenreByID")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid genre ID",
+			})
+		}
+
+		if err = h.lyricsUsecase.DeleteGenreByID(c.Request().Context(), uint(id)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "genre not found",
+				})
+			}
+			h.logger.Errorf("failed to delete genre: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to delete genre",
+			})
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// AttachGenres добавляет жанры нескольким песням.
+// @Summary Добавление жанров песням
+// @Description Добавляет каждой из песен каждый из жанров, уже добавленные жанры пропускаются
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param body body models.SongGenresRequest true "Песни и жанры"
+// @Success 200 {object} models.ClassificationResult "Количество добавленных связей"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 404 {object} map[string]string "Песня или жанр не найдены"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/genres/attach [post]
+func (h lyricsHandlers) AttachGenres() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler AttachGenres")
+
+		request, badRequest := bindSongGenresRequest(c)
+		if badRequest != nil {
+			return c.JSON(http.StatusBadRequest, badRequest)
+		}
+
+		result, err := h.lyricsUsecase.AttachGenres(c.Request().Context(), request)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song or genre not found",
+				})
+			}
+			h.logger.Errorf("failed to attach genres: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to attach genres",
+			})
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
+
+// DetachGenres убирает жанры у нескольких песен.
+// @Summary Удаление жанров у песен
+// @Description Убирает у каждой из песен каждый из жанров, поджанры не затрагиваются
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param body body models.SongGenresRequest true "Песни и жанры"
+// @Success 200 {object} models.ClassificationResult "Количество удаленных связей"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/genres/detach [post]
+func (h lyricsHandlers) DetachGenres() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler DetachGenres")
+
+		request, badRequest := bindSongGenresRequest(c)
+		if badRequest != nil {
+			return c.JSON(http.StatusBadRequest, badRequest)
+		}
+
+		result, err := h.lyricsUsecase.DetachGenres(c.Request().Context(), request)
+		if err != nil {
+			h.logger.Errorf("failed to detach genres: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to detach genres",
+			})
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
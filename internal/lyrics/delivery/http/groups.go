@@ -0,0 +1,424 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// bindGroupRequest разбирает и проверяет тело запроса группы, имя приводится к виду без пробелов по краям
+func bindGroupRequest(c echo.Context) (models.GroupRequest, map[string]interface{}) {
+	var groupRequest models.GroupRequest
+	if err := c.Bind(&groupRequest); err != nil {
+		return groupRequest, map[string]interface{}{"error": "invalid JSON format"}
+	}
+
+	groupRequest.Name = strings.TrimSpace(groupRequest.Name)
+	if err := c.Validate(&groupRequest); err != nil {
+		return groupRequest, map[string]interface{}{
+			"error":   "validation failed",
+			"details": err.Error(),
+		}
+	}
+
+	return groupRequest, nil
+}
+
+// CreateGroup создает группу.
+// @Summary Создание группы
+// @Description Создает группу без песен. Группа без песен и альбомов удаляется периодической очисткой через GROUP_CLEANUP_MIN_AGE.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param body body models.GroupRequest true "Данные группы"
+// @Success 201 {object} models.GroupInfo "Созданная группа"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 409 {object} map[string]string "Группа уже существует"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/groups [post]
+func (h lyricsHandlers) CreateGroup() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler CreateGroup")
+
+		groupRequest, badRequest := bindGroupRequest(c)
+		if badRequest != nil {
+			return c.JSON(http.StatusBadRequest, badRequest)
+		}
+
+		group, err := h.lyricsUsecase.CreateGroup(c.Request().Context(), groupRequest)
+		if err != nil {
+			if errors.Is(err, lyrics.ErrConflict) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "group already exists",
+				})
+			}
+			h.logger.Errorf("failed to create group: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to create group",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, group)
+	}
+}
+
+// GetGroups возвращает список групп.
+// @Summary Список групп
+// @Description Возвращает группы с количеством песен и альбомов, с фильтром по имени
+// @Tags Groups
+// @Produce json
+// @Param name query string false "Фильтр по имени группы"
+// @Param page query int false "Номер страницы"
+// @Param limit query int false "Количество записей на странице"
+// @Success 200 {object} map[string]interface{} "Список групп"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/groups [get]
+func (h lyricsHandlers) GetGroups() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		page, err := strconv.Atoi(c.QueryParam("page"))
+		if err != nil || page <= 0 {
+			page = 1
+		}
+
+		limit, err := strconv.Atoi(c.QueryParam("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		groups, total, err := h.lyricsUsecase.GetGroups(ctx, c.QueryParam("name"), page, limit)
+		if err != nil {
+			h.logger.Errorf("Error in GetGroups: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch groups",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"data":  groups,
+		})
+	}
+}
+
+// GetGroupByID возвращает группу.
+// @Summary Получение группы
+// @Tags Groups
+// @Produce json
+// @Param id path int true "ID группы"
+// @Success 200 {object} models.GroupInfo "Группа"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Группа не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/groups/{id} [get]
+func (h lyricsHandlers) GetGroupByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid group ID",
+			})
+		}
+
+		group, err := h.lyricsUsecase.GetGroupByID(c.Request().Context(), uint(id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "group not found",
+				})
+			}
+			h.logger.Errorf("Error in GetGroupByID: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch group",
+			})
+		}
+
+		return c.JSON(http.StatusOK, group)
+	}
+}
+
+// UpdateGroupByID переименовывает группу и меняет ее описание.
+// @Summary Изменение группы
+// @Description Переименовывает группу и меняет описание; без description описание не меняется
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param body body models.GroupRequest true "Данные группы"
+// @Success 200 {object} models.GroupInfo "Измененная группа"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 404 {object} map[string]string "Группа не найдена"
+// @Failure 409 {object} map[string]string "Имя занято другой группой"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/groups/{id} [put]
+func (h lyricsHandlers) UpdateGroupByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler UpdateGroupByID")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid group ID",
+			})
+		}
+
+		groupRequest, badRequest := bindGroupRequest(c)
+		if badRequest != nil {
+			return c.JSON(http.StatusBadRequest, badRequest)
+		}
+
+		group, err := h.lyricsUsecase.UpdateGroupByID(c.Request().Context(), uint(id), groupRequest)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "group not found",
+				})
+			case errors.Is(err, lyrics.ErrConflict):
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "group name is already taken",
+				})
+			}
+			h.logger.Errorf("failed to update group: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to update group",
+			})
+		}
+
+		return c.JSON(http.StatusOK, group)
+	}
+}
+
+// DeleteGroupByID удаляет группу.
+// @Summary Удаление группы
+// @Description Удаляет группу и ее альбомы. Группа с песнями удаляется только с cascade=true, вместе с песнями.
+// @Tags Groups
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param cascade query bool false "Удалить вместе с песнями группы"
+// @Success 200 {object} models.GroupDeleteResult "Группа удалена"
+// @Failure 400 {object} map[string]string "Некорректный ID или cascade"
+// @Failure 404 {object} map[string]string "Группа не найдена"
+// @Failure 409 {object} map[string]string "У группы есть песни"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/groups/{id} [delete]
+func (h lyricsHandlers) DeleteGroupByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler DeleteGroupByID")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid group ID",
+			})
+		}
+
+		cascade := false
+		if cascadeParam := c.QueryParam("cascade"); cascadeParam != "" {
+			if cascade, err = strconv.ParseBool(cascadeParam); err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Invalid cascade, expected true or false",
+				})
+			}
+		}
+
+		result, err := h.lyricsUsecase.DeleteGroupByID(c.Request().Context(), uint(id), cascade)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "group not found",
+				})
+			case errors.Is(err, lyrics.ErrGroupHasSongs):
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "group has songs, delete with cascade=true to remove them too",
+				})
+			}
+			h.logger.Errorf("failed to delete group: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to delete group",
+			})
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
+
+// AddGroupAlias добавляет другое написание имени группы.
+// @Summary Добавление другого написания имени группы
+// @Description Другое написание находит группу при создании песен, в поиске, фильтре библиотеки и подсказках
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param body body models.GroupAliasRequest true "Другое написание"
+// @Success 201 {object} models.GroupInfo "Группа с другими написаниями"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 404 {object} map[string]string "Группа не найдена"
+// @Failure 409 {object} map[string]string "Написание занято другой группой"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/groups/{id}/aliases [post]
+func (h lyricsHandlers) AddGroupAlias() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler AddGroupAlias")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid group ID",
+			})
+		}
+
+		var aliasRequest models.GroupAliasRequest
+		if err := c.Bind(&aliasRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid JSON format",
+			})
+		}
+
+		aliasRequest.Alias = strings.TrimSpace(aliasRequest.Alias)
+		if err := c.Validate(&aliasRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+		}
+
+		group, err := h.lyricsUsecase.AddGroupAlias(c.Request().Context(), uint(id), aliasRequest.Alias)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "group not found",
+				})
+			case errors.Is(err, lyrics.ErrConflict):
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error": "alias is already a group name or an alias of another group",
+				})
+			}
+			h.logger.Errorf("failed to add group alias: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to add group alias",
+			})
+		}
+
+		return c.JSON(http.StatusCreated, group)
+	}
+}
+
+// DeleteGroupAlias удаляет другое написание имени группы.
+// @Summary Удаление другого написания имени группы
+// @Tags Groups
+// @Param id path int true "ID группы"
+// @Param alias path string true "Другое написание"
+// @Success 204 "Написание удалено"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Написание не найдено"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/groups/{id}/aliases/{alias} [delete]
+func (h lyricsHandlers) DeleteGroupAlias() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler DeleteGroupAlias")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid group ID",
+			})
+		}
+
+		alias, err := url.PathUnescape(c.Param("alias"))
+		if err != nil || strings.TrimSpace(alias) == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid alias",
+			})
+		}
+
+		if err := h.lyricsUsecase.DeleteGroupAlias(c.Request().Context(), uint(id), strings.TrimSpace(alias)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "alias not found",
+				})
+			}
+			h.logger.Errorf("failed to delete group alias: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to delete group alias",
+			})
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// MergeGroups сливает группу с другой.
+// @Summary Слияние дубликатов групп
+// @Description Переносит песни и альбомы группы в группу into одной транзакцией и удаляет группу, ее имя становится другим написанием группы into.
+// @Description Из песен с одинаковым названием остается одна по strategy: keep_target (по умолчанию), keep_source или keep_newest; переводы удаляемой песни на недостающие языки переносятся.
+// @Description Альбомы с одинаковым названием объединяются.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "ID объединяемой группы"
+// @Param body body models.GroupMergeRequest true "Группа, в которую идет слияние, и стратегия"
+// @Success 200 {object} models.GroupMergeResult "Итог слияния"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 404 {object} map[string]string "Группа не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/groups/{id}/merge [post]
+func (h lyricsHandlers) MergeGroups() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler MergeGroups")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid group ID",
+			})
+		}
+
+		var mergeRequest models.GroupMergeRequest
+		if err := c.Bind(&mergeRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid JSON format",
+			})
+		}
+
+		if err := c.Validate(&mergeRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+		}
+
+		if mergeRequest.Into == uint(id) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "cannot merge a group into itself",
+			})
+		}
+
+		result, err := h.lyricsUsecase.MergeGroups(c.Request().Context(), uint(id), mergeRequest)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "group not found",
+				})
+			}
+			h.logger.Errorf("failed to merge groups: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to merge groups",
+			})
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
@@ -3,8 +3,11 @@ package http
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/22Fariz22/musiclab/config"
 	"github.com/22Fariz22/musiclab/internal/lyrics"
@@ -17,11 +20,12 @@ import (
 type lyricsHandlers struct {
 	cfg           *config.Config
 	lyricsUsecase lyrics.UseCase
+	refresh       lyrics.RefreshTrigger
 	logger        logger.Logger
 }
 
-func NewLyricsHandler(cfg *config.Config, lyricsUsecase lyrics.UseCase, logger logger.Logger) lyrics.Handlers {
-	return &lyricsHandlers{cfg: cfg, lyricsUsecase: lyricsUsecase, logger: logger}
+func NewLyricsHandler(cfg *config.Config, lyricsUsecase lyrics.UseCase, refresh lyrics.RefreshTrigger, logger logger.Logger) lyrics.Handlers {
+	return &lyricsHandlers{cfg: cfg, lyricsUsecase: lyricsUsecase, refresh: refresh, logger: logger}
 }
 
 // Ping godoc
@@ -46,6 +50,25 @@ func (h lyricsHandlers) Ping() echo.HandlerFunc {
 	}
 }
 
+// Health godoc
+// @Summary Состояние сервиса
+// @Description Доступность базы данных и источников песен, состояние автоматического выключателя внешнего API
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.Health "ok или degraded, если часть источников недоступна"
+// @Failure 503 {object} models.Health "База данных недоступна"
+// @Router /lyrics/health [get]
+func (h lyricsHandlers) Health() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		health := h.lyricsUsecase.Health(c.Request().Context())
+
+		if health.Status == models.HealthDown {
+			return c.JSON(http.StatusServiceUnavailable, health)
+		}
+		return c.JSON(http.StatusOK, health)
+	}
+}
+
 // DeleteSongByID удаляет песню по её ID.
 // @Summary Удаление песни
 // @Description Удаляет песню из базы данных по ID
@@ -87,7 +110,9 @@ func (h lyricsHandlers) DeleteSongByID() echo.HandlerFunc {
 
 // UpdateTrackByID обновляет данные песни.
 // @Summary Обновление песни
-// @Description Обновляет данные песни по ID
+// @Description Обновляет данные песни по ID. Язык текста определяется заново при изменении текста;
+// @Description language закрепляет язык вручную, пустой language снимает закрепление.
+// @Description artists заменяет участников песни, без artists участники не меняются; feat./ft. в имени группы или песни добавляет приглашенных участников.
 // @Tags Songs
 // @Accept json
 // @Produce json
@@ -120,6 +145,18 @@ func (h lyricsHandlers) UpdateTrackByID() echo.HandlerFunc {
 			})
 		}
 
+		// Язык, заданный вручную, приводим к каноническому коду
+		if updateData.Language != nil && *updateData.Language != "" {
+			language, err := models.NormalizeLanguage(*updateData.Language)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]interface{}{
+					"error":   "validation failed",
+					"details": err.Error(),
+				})
+			}
+			updateData.Language = &language
+		}
+
 		// Логика обновления данных
 		err := h.lyricsUsecase.UpdateTrackByID(c.Request().Context(), updateData)
 		if err != nil {
@@ -144,12 +181,16 @@ func (h lyricsHandlers) UpdateTrackByID() echo.HandlerFunc {
 
 // CreateTrack создает новую песню.
 // @Summary Создание песни
-// @Description Создает новую песню на основе данных запроса
+// @Description Сохраняет песню в статусе pending и ставит в очередь загрузку ее данных из источников.
+// @Description Ход загрузки - по ссылке status_url, после загрузки песня переходит в статус enriched или failed.
+// @Description Участники из artists и после feat./ft. в имени группы или песни сохраняются отдельно от группы.
+// @Description Уже существующая песня группы не меняется и повторно не загружается.
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param body body models.SongRequest true "Данные новой песни"
-// @Success 200 {object} models.SongDetail "Созданная песня"
+// @Success 200 {object} map[string]interface{} "Песня уже существует: song_id"
+// @Success 202 {object} map[string]interface{} "Задача загрузки: job_id, song_id, status, status_url"
 // @Failure 400 "Некорректные данные"
 // @Failure 500 "Внутренняя ошибка сервера"
 // @Router /songs [post]
@@ -171,26 +212,130 @@ func (h lyricsHandlers) CreateTrack() echo.HandlerFunc {
 			return c.NoContent(http.StatusBadRequest)
 		}
 
-		songDetail, err := h.lyricsUsecase.CreateTrack(ctx, songRequest)
+		job, created, err := h.lyricsUsecase.CreateTrack(ctx, songRequest)
 		if err != nil {
 			h.logger.Debugf("Failed to create track: %v", err)
 			return c.NoContent(http.StatusInternalServerError)
 		}
 
-		return c.JSON(http.StatusOK, songDetail)
+		if !created {
+			return c.JSON(http.StatusOK, map[string]interface{}{
+				"song_id": job.SongID,
+				"message": "song already exists",
+			})
+		}
+
+		statusURL := fmt.Sprintf("%s/lyrics/jobs/%d", h.cfg.Middleware.MiddlewareAPIVersion, job.ID)
+		c.Response().Header().Set(echo.HeaderLocation, statusURL)
+
+		return c.JSON(http.StatusAccepted, map[string]interface{}{
+			"job_id":     job.ID,
+			"song_id":    job.SongID,
+			"status":     job.Status,
+			"status_url": statusURL,
+		})
+	}
+}
+
+// GetEnrichmentJob возвращает состояние задачи загрузки данных песни.
+// @Summary Статус загрузки песни
+// @Description Возвращает задачу загрузки: queued, running, done или failed с текстом ошибки
+// @Tags Songs
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Success 200 {object} models.EnrichmentJob "Задача"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/jobs/{id} [get]
+func (h lyricsHandlers) GetEnrichmentJob() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid job ID",
+			})
+		}
+
+		job, err := h.lyricsUsecase.GetEnrichmentJob(c.Request().Context(), uint(id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "job not found",
+				})
+			}
+			h.logger.Errorf("Error in GetEnrichmentJob: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch job",
+			})
+		}
+
+		return c.JSON(http.StatusOK, job)
+	}
+}
+
+// GetSongByID получает песню со всеми метаданными.
+// @Summary Получение песни
+// @Description Возвращает песню: группу, название, дату выпуска, ссылку, количество куплетов и полный текст
+// @Tags Songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lang query string false "Код языка перевода (en, de, pt-BR); без перевода возвращается оригинал"
+// @Success 200 {object} models.SongInfo "Песня"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Песня не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id} [get]
+func (h lyricsHandlers) GetSongByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler GetSongByID")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid song ID",
+			})
+		}
+
+		lang, err := parseLangParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		song, err := h.lyricsUsecase.GetSongByID(c.Request().Context(), uint(id), lang)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found",
+				})
+			}
+			h.logger.Errorf("Error in GetSongByID: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch song",
+			})
+		}
+
+		return c.JSON(http.StatusOK, song)
 	}
 }
 
 // GetSongVerseByID получает куплет песни.
 // @Summary Получение куплета
-// @Description Возвращает куплет песни по ID песни и номеру страницы
+// @Description Возвращает куплет песни по ID песни и номеру страницы.
+// @Description В ответе тип и метка части песни (куплет, припев и т.п.), в которую входит куплет.
+// @Description Если у песни есть синхронизированный текст, в lines приходят строки куплета со временем начала.
 // @Tags Songs
 // @Param id path int true "ID песни"
 // @Param page query int true "Номер страницы"
-// @Success 200 {object} map[string]interface{} "Куплет песни"
-// @Failure 400 {object} map[string]string "Некорректный ID или номер страницы"
+// @Param mode query string false "Разбиение на страницы: verses - по size строф (по умолчанию 1), lines - по size строк (4), chars - не больше size символов (500)"
+// @Param size query int false "Размер страницы для выбранного mode"
+// @Param lang query string false "Код языка перевода, который вернется рядом с оригиналом"
+// @Success 200 {object} models.Verse "Куплет песни"
+// @Failure 400 {object} map[string]string "Некорректный ID, номер страницы или способ разбиения"
 // @Failure 404 {object} map[string]string "Куплет не найден"
-// @Router /songs/{id}/verses [get]
+// @Router /lyrics/verses/{id} [get]
 func (h lyricsHandlers) GetSongVerseByID() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		h.logger.Debug("In handler GetSongVerseByPage")
@@ -213,29 +358,33 @@ func (h lyricsHandlers) GetSongVerseByID() echo.HandlerFunc {
 			})
 		}
 
-		// Вызываем usecase для получения куплета
-		verse, err := h.lyricsUsecase.GetSongVerseByID(ctx, uint(id), page)
+		// Способ разбиения на страницы, по умолчанию одна строфа
+		paging, err := models.ParseVersePaging(c.QueryParam("mode"), c.QueryParam("size"))
 		if err != nil {
-			h.logger.Errorf("Error fetching verse: %v", err)
-			return c.JSON(http.StatusNotFound, map[string]string{
+			return c.JSON(http.StatusBadRequest, map[string]string{
 				"error": err.Error(),
 			})
 		}
 
-		// Проверяем на пустой текст для первой страницы
-		if page == 1 && verse == "" {
-			h.logger.Debugf("Page %d has an empty verse", page)
-			return c.JSON(http.StatusOK, map[string]interface{}{
-				"page":  page,
-				"verse": "",
+		// Язык перевода, который вернется рядом с оригиналом
+		lang, err := parseLangParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		// Вызываем usecase для получения куплета
+		verse, err := h.lyricsUsecase.GetSongVerseByID(ctx, uint(id), page, paging, lang)
+		if err != nil {
+			h.logger.Errorf("Error fetching verse: %v", err)
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
 			})
 		}
 
 		// Возвращаем куплет клиенту
-		return c.JSON(http.StatusOK, map[string]interface{}{
-			"page":  page,
-			"verse": verse,
-		})
+		return c.JSON(http.StatusOK, verse)
 	}
 }
 
@@ -243,23 +392,225 @@ func (h lyricsHandlers) GetSongVerseByID() echo.HandlerFunc {
 // @Summary Получение библиотеки
 // @Description Возвращает список песен на основе фильтров
 // @Tags Songs
-// @Param group query string false "Фильтр по группе"
+// @Param group query string false "Фильтр по группе или любому участнику песни"
 // @Param song query string false "Фильтр по названию песни"
 // @Param text query string false "Фильтр по тексту"
-// @Param release_date query string false "Фильтр по дате выпуска"
+// @Param release_date query string false "Фильтр по дате выпуска: dd.mm.yyyy или yyyy-mm-dd"
+// @Param released_from query string false "Вышли не раньше даты"
+// @Param released_to query string false "Вышли не позже даты"
+// @Param year query int false "Фильтр по году выпуска"
+// @Param sort query string false "Сортировка через запятую: group, song, release_date, created_at, updated_at, id; по убыванию: -song или song:desc"
+// @Param fields query string false "Поля ответа через запятую: id, group, song, release_date, text, link, album, source, language, status, created_at, updated_at"
+// @Param album query string false "Фильтр по названию альбома"
+// @Param album_id query int false "Фильтр по ID альбома"
+// @Param language query string false "Фильтр по языку текста (en, pt - вместе с pt-BR); und - язык не определен"
+// @Param genre query string false "Фильтр по жанрам через запятую, жанр включает свои поджанры"
+// @Param genre_match query string false "any - песня любого из жанров (по умолчанию), all - каждого из жанров"
+// @Param tag query string false "Фильтр по меткам через запятую"
+// @Param tag_match query string false "any - песня с любой из меток (по умолчанию), all - с каждой из меток"
 // @Param page query int false "Номер страницы"
 // @Param limit query int false "Количество записей на странице"
-// @Success 200 {object} map[string]interface{} "Список песен"
+// @Param after query string false "Курсор из next_cursor предыдущего ответа; пустой параметр начинает выдачу в режиме курсора вместо page"
+// @Param count query bool false "Считать общее количество и фасеты facets по языкам, жанрам и меткам (по умолчанию true для page и false для after)"
+// @Success 200 {object} map[string]interface{} "Список песен, при пустом результате - подсказки did_you_mean"
+// @Failure 400 {object} map[string]string "Некорректные фильтры"
 // @Failure 500 {object} map[string]string "Ошибка сервера"
 // @Router /library [get]
 func (h lyricsHandlers) GetLibrary() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		group := c.QueryParam("group")
-		song := c.QueryParam("song")
-		text := c.QueryParam("text")
-		releaseDate := c.QueryParam("release_date")
+		filter, err := parseLibraryFilter(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		result, err := h.lyricsUsecase.GetLibrary(ctx, filter)
+		if err != nil {
+			if errors.Is(err, lyrics.ErrInvalidCursor) {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": err.Error(),
+				})
+			}
+			h.logger.Errorf("Error in GetLibrary: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch library",
+			})
+		}
+
+		response := map[string]interface{}{
+			"limit": filter.Limit,
+			"data":  result.Songs,
+		}
+		if result.Total != nil {
+			response["total"] = *result.Total
+		}
+		if result.Facets != nil {
+			response["facets"] = result.Facets
+		}
+		if filter.After != nil {
+			response["next_cursor"] = result.NextCursor
+		} else {
+			response["page"] = filter.Page
+		}
+
+		// Отдаем только запрошенные поля
+		if filter.Fields != nil {
+			data := make([]map[string]interface{}, 0, len(result.Songs))
+			for _, s := range result.Songs {
+				data = append(data, s.Project(filter.Fields))
+			}
+			response["data"] = data
+		}
+
+		// Ничего не нашли по названию - предлагаем похожие группы и песни
+		firstPage := filter.Page == 1 && (filter.After == nil || *filter.After == "")
+		if firstPage && len(result.Songs) == 0 && (filter.Group != "" || filter.Song != "") {
+			suggestions, err := h.lyricsUsecase.Suggest(ctx, filter.Group, filter.Song)
+			if err != nil {
+				h.logger.Errorf("Error in GetLibrary Suggest: %v", err)
+			} else if !suggestions.Empty() {
+				response["did_you_mean"] = suggestions
+			}
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// parseLibraryFilter разбирает фильтры, сортировку и пагинацию библиотеки из query-параметров
+func parseLibraryFilter(c echo.Context) (models.LibraryFilter, error) {
+	filter := models.LibraryFilter{
+		Group: c.QueryParam("group"),
+		Song:  c.QueryParam("song"),
+		Text:  c.QueryParam("text"),
+		Album: c.QueryParam("album"),
+	}
+
+	var err error
+	if filter.ReleaseDate, err = parseDateParam(c, "release_date"); err != nil {
+		return filter, err
+	}
+	if filter.ReleasedFrom, err = parseDateParam(c, "released_from"); err != nil {
+		return filter, err
+	}
+	if filter.ReleasedTo, err = parseDateParam(c, "released_to"); err != nil {
+		return filter, err
+	}
+
+	if yearParam := c.QueryParam("year"); yearParam != "" {
+		filter.Year, err = strconv.Atoi(yearParam)
+		if err != nil || filter.Year <= 0 {
+			return filter, errors.New("Invalid year")
+		}
+	}
+
+	if albumParam := c.QueryParam("album_id"); albumParam != "" {
+		id, err := strconv.Atoi(albumParam)
+		if err != nil || id <= 0 {
+			return filter, errors.New("Invalid album ID")
+		}
+		filter.AlbumID = uint(id)
+	}
+
+	if languageParam := c.QueryParam("language"); languageParam != "" {
+		filter.Language = models.LanguageUndetermined
+		if languageParam != models.LanguageUndetermined {
+			if filter.Language, err = models.NormalizeLanguage(languageParam); err != nil {
+				return filter, err
+			}
+		}
+	}
+
+	filter.Genres = splitListParam(c.QueryParam("genre"))
+	if filter.GenreMatch, err = parseMatchParam(c, "genre_match"); err != nil {
+		return filter, err
+	}
+	filter.Tags = splitListParam(c.QueryParam("tag"))
+	if filter.TagMatch, err = parseMatchParam(c, "tag_match"); err != nil {
+		return filter, err
+	}
+
+	if filter.Sort, err = models.ParseLibrarySort(c.QueryParam("sort")); err != nil {
+		return filter, err
+	}
+	if filter.Fields, err = models.ParseLibraryFields(c.QueryParam("fields")); err != nil {
+		return filter, err
+	}
+
+	filter.Page, err = strconv.Atoi(c.QueryParam("page"))
+	if err != nil || filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	filter.Limit, err = strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+
+	// Наличие параметра after (даже пустого) включает режим курсора
+	if after, ok := c.QueryParams()["after"]; ok {
+		cursor := ""
+		if len(after) > 0 {
+			cursor = after[0]
+		}
+		filter.After = &cursor
+	}
+
+	// В режиме курсора общее количество по умолчанию не считается
+	filter.WithTotal = filter.After == nil
+	if countParam := c.QueryParam("count"); countParam != "" {
+		filter.WithTotal, err = strconv.ParseBool(countParam)
+		if err != nil {
+			return filter, errors.New("Invalid count, expected true or false")
+		}
+	}
+
+	return filter, nil
+}
+
+// splitListParam значения параметра через запятую без пустых
+func splitListParam(param string) []string {
+	var values []string
+	for _, value := range strings.Split(param, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parseMatchParam режим сочетания значений фильтра: any (по умолчанию) или all
+func parseMatchParam(c echo.Context, name string) (string, error) {
+	switch match := c.QueryParam(name); match {
+	case "", models.MatchAny:
+		return models.MatchAny, nil
+	case models.MatchAll:
+		return match, nil
+	default:
+		return "", errors.New("Invalid " + name + ", expected any or all")
+	}
+}
+
+// SearchLyrics полнотекстовый поиск по текстам песен.
+// @Summary Поиск по текстам
+// @Description Ищет песни по тексту и названию, сортирует по релевантности и возвращает фрагменты с совпадениями.
+// @Description Фраза в кавычках ищется целиком, слово со звездочкой на конце - по префиксу.
+// @Tags Songs
+// @Param q query string true "Поисковый запрос, например: \"set my soul\" alig*"
+// @Param page query int false "Номер страницы"
+// @Param limit query int false "Количество записей на странице"
+// @Success 200 {object} map[string]interface{} "Найденные песни"
+// @Failure 400 {object} map[string]string "Пустой поисковый запрос"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /lyrics/search [get]
+func (h lyricsHandlers) SearchLyrics() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		query := c.QueryParam("q")
 
 		page, err := strconv.Atoi(c.QueryParam("page"))
 		if err != nil || page <= 0 {
@@ -271,19 +622,86 @@ func (h lyricsHandlers) GetLibrary() echo.HandlerFunc {
 			limit = 10
 		}
 
-		songs, total, err := h.lyricsUsecase.GetLibrary(ctx, group, song, text, releaseDate, page, limit)
+		results, total, err := h.lyricsUsecase.SearchLyrics(ctx, query, page, limit)
 		if err != nil {
-			h.logger.Errorf("Error in GetLibrary: %v", err)
+			if errors.Is(err, lyrics.ErrEmptySearchQuery) {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Search query is empty",
+				})
+			}
+			h.logger.Errorf("Error in SearchLyrics: %v", err)
 			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to fetch library",
+				"error": "Failed to search lyrics",
 			})
 		}
 
 		return c.JSON(http.StatusOK, map[string]interface{}{
+			"query": query,
 			"page":  page,
 			"limit": limit,
 			"total": total,
-			"data":  songs,
+			"data":  results,
 		})
 	}
 }
+
+// Suggest подсказки для названий групп и песен с опечатками.
+// @Summary Возможно, вы имели в виду
+// @Description Возвращает группы и песни с похожими названиями (pg_trgm)
+// @Tags Songs
+// @Param q query string true "Название группы или песни"
+// @Param type query string false "Где искать: group, song или all (по умолчанию all)"
+// @Success 200 {object} models.Suggestions "Похожие названия"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /lyrics/suggest [get]
+func (h lyricsHandlers) Suggest() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		query := strings.TrimSpace(c.QueryParam("q"))
+		if query == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Query is empty",
+			})
+		}
+
+		var group, song string
+		switch c.QueryParam("type") {
+		case "group":
+			group = query
+		case "song":
+			song = query
+		case "", "all":
+			group, song = query, query
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid type, expected group, song or all",
+			})
+		}
+
+		suggestions, err := h.lyricsUsecase.Suggest(ctx, group, song)
+		if err != nil {
+			h.logger.Errorf("Error in Suggest: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch suggestions",
+			})
+		}
+
+		return c.JSON(http.StatusOK, suggestions)
+	}
+}
+
+// parseDateParam разбирает дату из query-параметра, пустой параметр - nil
+func parseDateParam(c echo.Context, name string) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := utils.ParseReleaseDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return &date, nil
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// ImportSongs массовый импорт песен из CSV или NDJSON.
+// @Summary Импорт песен
+// @Description Загружает песни из файла CSV (заголовок group,song,release_date,text,link) или NDJSON (по объекту на строку).
+// @Description Файл передается полем file в multipart/form-data или телом запроса. Формат берется из параметра format,
+// @Description расширения файла или Content-Type. Ошибки отдельных строк не прерывают импорт и попадают в отчет.
+// @Tags Songs
+// @Accept mpfd,text/csv,application/x-ndjson
+// @Produce json
+// @Param file formData file false "Файл импорта"
+// @Param format query string false "Формат файла: csv или ndjson"
+// @Param enrich query bool false "Поставить в очередь загрузку недостающих текста, ссылки и даты выхода; задача строки - в job_id"
+// @Param overwrite query bool false "Перезаписать существующие песни, иначе они пропускаются"
+// @Success 200 {object} models.ImportReport "Отчет по строкам"
+// @Failure 400 {object} map[string]string "Неизвестный формат или некорректный файл"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/import [post]
+func (h lyricsHandlers) ImportSongs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler ImportSongs")
+
+		var opts models.ImportOptions
+		for name, value := range map[string]*bool{"enrich": &opts.Enrich, "overwrite": &opts.Overwrite} {
+			if param := c.QueryParam(name); param != "" {
+				parsed, err := strconv.ParseBool(param)
+				if err != nil {
+					return c.JSON(http.StatusBadRequest, map[string]string{
+						"error": "Invalid " + name + " parameter",
+					})
+				}
+				*value = parsed
+			}
+		}
+
+		var (
+			body     io.Reader = c.Request().Body
+			filename string
+		)
+		contentType := c.Request().Header.Get(echo.HeaderContentType)
+
+		// Файл в multipart-форме, иначе файлом считается все тело запроса
+		if fileHeader, err := c.FormFile("file"); err == nil {
+			file, err := fileHeader.Open()
+			if err != nil {
+				h.logger.Errorf("Error opening uploaded file: %v", err)
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Failed to read uploaded file",
+				})
+			}
+			defer file.Close()
+
+			body = file
+			filename = fileHeader.Filename
+			contentType = fileHeader.Header.Get(echo.HeaderContentType)
+		}
+
+		format := c.QueryParam("format")
+		if format == "" {
+			format = models.DetectImportFormat(filename, contentType)
+		}
+		if format == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Cannot detect import format, pass format=csv or format=ndjson",
+			})
+		}
+
+		report, err := h.lyricsUsecase.ImportSongs(c.Request().Context(), format, body, opts)
+		if err != nil {
+			if errors.Is(err, lyrics.ErrInvalidImportFile) {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": err.Error(),
+				})
+			}
+			h.logger.Errorf("Error in ImportSongs: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to import songs",
+			})
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLibraryFilter_Dates(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantErr  string
+		wantFrom *time.Time
+		wantTo   *time.Time
+	}{
+		{name: "no dates", query: ""},
+		{name: "range in different layouts", query: "released_from=01.02.2006&released_to=2009-09-14",
+			wantFrom: datePtr(2006, 2, 1), wantTo: datePtr(2009, 9, 14)},
+		{name: "year only", query: "released_from=2006", wantFrom: datePtr(2006, 1, 1)},
+		{name: "invalid day", query: "released_from=31.02.2020", wantErr: "invalid released_from"},
+		{name: "invalid month", query: "released_to=2020-13-01", wantErr: "invalid released_to"},
+		{name: "invalid exact date", query: "release_date=someday", wantErr: "invalid release_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/lyrics/library?"+tt.query, nil)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			filter, err := parseLibraryFilter(c)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFrom, filter.ReleasedFrom)
+			assert.Equal(t, tt.wantTo, filter.ReleasedTo)
+		})
+	}
+}
+
+func TestGetLibrary_RejectsInvalidDates(t *testing.T) {
+	// Встроенный nil-интерфейс паникует, если обработчик дойдет до usecase
+	h := lyricsHandlers{cfg: &config.Config{}, lyricsUsecase: exportUseCase{}, logger: utils.CreateTestLogger()}
+
+	for _, query := range []string{"released_from=31.02.2020", "released_to=2020-13-01"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/lyrics/library?"+query, nil)
+
+		require.NoError(t, h.GetLibrary()(echo.New().NewContext(req, rec)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func datePtr(year int, month time.Month, day int) *time.Time {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
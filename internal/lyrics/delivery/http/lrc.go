@@ -0,0 +1,181 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// maxLRCSize максимальный размер синхронизированного текста
+const maxLRCSize = 1 << 20
+
+// GetSongLRC синхронизированный текст песни.
+// @Summary Синхронизированный текст
+// @Description Возвращает текст песни с метками времени: исходный LRC (format=lrc) или строки со временем начала (format=json)
+// @Tags Songs
+// @Produce plain,json
+// @Param id path int true "ID песни"
+// @Param format query string false "Формат ответа: lrc (по умолчанию) или json"
+// @Success 200 {object} models.SongLRC "Строки со временем начала"
+// @Failure 400 {object} map[string]string "Некорректный ID или формат"
+// @Failure 404 {object} map[string]string "Песня не найдена или у нее нет синхронизированного текста"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id}/lrc [get]
+func (h lyricsHandlers) GetSongLRC() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler GetSongLRC")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid song ID",
+			})
+		}
+
+		format := c.QueryParam("format")
+		if format != "" && format != "lrc" && format != "json" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid format, expected lrc or json",
+			})
+		}
+
+		songLRC, err := h.lyricsUsecase.GetSongLRC(c.Request().Context(), uint(id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found",
+				})
+			}
+			if errors.Is(err, lyrics.ErrNoLRC) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": err.Error(),
+				})
+			}
+			h.logger.Errorf("Error in GetSongLRC: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch timed lyrics",
+			})
+		}
+
+		if format == "json" {
+			return c.JSON(http.StatusOK, songLRC)
+		}
+		return c.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, []byte(songLRC.Raw))
+	}
+}
+
+// SetSongLRC сохраняет синхронизированный текст песни.
+// @Summary Загрузка синхронизированного текста
+// @Description Принимает LRC телом запроса (text/plain) или полем lrc в JSON. Каждая непустая строка должна начинаться
+// @Description с метки [mm:ss.xx] или быть тегом заголовка ([ar:], [ti:], [offset:] и т.п.)
+// @Tags Songs
+// @Accept plain,json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param body body models.LRCRequest true "Синхронизированный текст"
+// @Success 200 {object} models.SongLRC "Сохраненные строки со временем начала"
+// @Failure 400 {object} map[string]string "Некорректный LRC"
+// @Failure 404 {object} map[string]string "Песня не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id}/lrc [put]
+func (h lyricsHandlers) SetSongLRC() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler SetSongLRC")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid song ID",
+			})
+		}
+
+		var lrcRequest models.LRCRequest
+		if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+			if err := c.Bind(&lrcRequest); err != nil {
+				h.logger.Debug("in handler SetSongLRC() Bind() return error: ", err)
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "invalid JSON format",
+				})
+			}
+		} else {
+			body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxLRCSize+1))
+			if err != nil || len(body) > maxLRCSize {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Failed to read LRC, the limit is 1 MB",
+				})
+			}
+			lrcRequest.LRC = string(body)
+		}
+
+		if err := c.Validate(&lrcRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+		}
+
+		songLRC, err := h.lyricsUsecase.SetSongLRC(c.Request().Context(), uint(id), lrcRequest.LRC)
+		if err != nil {
+			if errors.Is(err, lyrics.ErrInvalidLRC) {
+				return c.JSON(http.StatusBadRequest, map[string]interface{}{
+					"error":   "validation failed",
+					"details": err.Error(),
+				})
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found",
+				})
+			}
+			h.logger.Errorf("Error in SetSongLRC: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to save timed lyrics",
+			})
+		}
+
+		return c.JSON(http.StatusOK, songLRC)
+	}
+}
+
+// DeleteSongLRC удаляет синхронизированный текст песни.
+// @Summary Удаление синхронизированного текста
+// @Tags Songs
+// @Param id path int true "ID песни"
+// @Success 204 "Синхронизированный текст удален"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Песня не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id}/lrc [delete]
+func (h lyricsHandlers) DeleteSongLRC() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler DeleteSongLRC")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid song ID",
+			})
+		}
+
+		if err := h.lyricsUsecase.DeleteSongLRC(c.Request().Context(), uint(id)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found",
+				})
+			}
+			h.logger.Errorf("Error in DeleteSongLRC: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete timed lyrics",
+			})
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -8,9 +8,57 @@ import (
 // Map lyrics routes
 func MapLyricsRoutes(lyricsGroup *echo.Group, h lyrics.Handlers) {
 	lyricsGroup.GET("/ping", h.Ping())
+	lyricsGroup.GET("/health", h.Health())
 	lyricsGroup.DELETE("/delete/:id", h.DeleteSongByID())
 	lyricsGroup.PUT("/update", h.UpdateTrackByID())
 	lyricsGroup.POST("/create", h.CreateTrack())
+	lyricsGroup.GET("/jobs/:id", h.GetEnrichmentJob())
+	lyricsGroup.GET("/songs/:id", h.GetSongByID())
+	lyricsGroup.GET("/songs/:id/verses", h.GetSongVerses())
+	lyricsGroup.GET("/songs/:id/sections", h.GetSongSections())
+	lyricsGroup.GET("/songs/:id/translations", h.GetTranslations())
+	lyricsGroup.GET("/songs/:id/translations/:lang", h.GetTranslation())
+	lyricsGroup.PUT("/songs/:id/translations/:lang", h.SaveTranslation())
+	lyricsGroup.DELETE("/songs/:id/translations/:lang", h.DeleteTranslation())
+	lyricsGroup.GET("/songs/:id/lrc", h.GetSongLRC())
+	lyricsGroup.PUT("/songs/:id/lrc", h.SetSongLRC())
+	lyricsGroup.DELETE("/songs/:id/lrc", h.DeleteSongLRC())
 	lyricsGroup.GET("/verses/:id", h.GetSongVerseByID())
 	lyricsGroup.GET("/library", h.GetLibrary())
+	lyricsGroup.GET("/export", h.ExportLibrary())
+	lyricsGroup.GET("/search", h.SearchLyrics())
+	lyricsGroup.GET("/suggest", h.Suggest())
+	lyricsGroup.POST("/import", h.ImportSongs())
+
+	lyricsGroup.GET("/albums", h.GetAlbums())
+	lyricsGroup.POST("/albums", h.CreateAlbum())
+	lyricsGroup.GET("/albums/:id", h.GetAlbumByID())
+	lyricsGroup.PUT("/albums/:id", h.UpdateAlbumByID())
+	lyricsGroup.DELETE("/albums/:id", h.DeleteAlbumByID())
+	lyricsGroup.POST("/albums/:id/songs", h.AttachSongsToAlbum())
+	lyricsGroup.DELETE("/albums/:id/songs/:song_id", h.DetachSongFromAlbum())
+
+	lyricsGroup.GET("/groups", h.GetGroups())
+	lyricsGroup.POST("/groups", h.CreateGroup())
+	lyricsGroup.GET("/groups/:id", h.GetGroupByID())
+	lyricsGroup.PUT("/groups/:id", h.UpdateGroupByID())
+	lyricsGroup.DELETE("/groups/:id", h.DeleteGroupByID())
+	lyricsGroup.POST("/groups/:id/aliases", h.AddGroupAlias())
+	lyricsGroup.DELETE("/groups/:id/aliases/:alias", h.DeleteGroupAlias())
+	lyricsGroup.POST("/groups/:id/merge", h.MergeGroups())
+
+	lyricsGroup.GET("/genres", h.GetGenres())
+	lyricsGroup.POST("/genres", h.CreateGenre())
+	lyricsGroup.PUT("/genres/:id", h.UpdateGenreByID())
+	lyricsGroup.DELETE("/genres/:id", h.DeleteGenreByID())
+	lyricsGroup.POST("/songs/genres/attach", h.AttachGenres())
+	lyricsGroup.POST("/songs/genres/detach", h.DetachGenres())
+
+	lyricsGroup.GET("/tags", h.GetTags())
+	lyricsGroup.DELETE("/tags/:id", h.DeleteTagByID())
+	lyricsGroup.POST("/songs/tags/attach", h.AttachTags())
+	lyricsGroup.POST("/songs/tags/detach", h.DetachTags())
+
+	lyricsGroup.POST("/admin/refresh", h.TriggerRefresh())
+	lyricsGroup.GET("/admin/refresh", h.GetLastRefresh())
 }
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetSongSections структура песни.
+// @Summary Структура песни
+// @Description Возвращает части песни (куплеты, припевы, бриджи и т.п.) с номерами страниц куплетов.
+// @Description Части берутся из маркеров [Chorus], [Verse 2], [Bridge] в тексте; без маркеров повторяющиеся строфы считаются припевом.
+// @Tags Songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.SongSections "Структура песни"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Песня не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id}/sections [get]
+func (h lyricsHandlers) GetSongSections() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler GetSongSections")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid song ID",
+			})
+		}
+
+		sections, err := h.lyricsUsecase.GetSongSections(c.Request().Context(), uint(id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found",
+				})
+			}
+			h.logger.Errorf("Error in GetSongSections: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch song sections",
+			})
+		}
+
+		return c.JSON(http.StatusOK, sections)
+	}
+}
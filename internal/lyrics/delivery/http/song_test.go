@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// songUseCase знает только песню 1 "Muse - Uprising", на ID 500 отвечает внутренней ошибкой
+type songUseCase struct {
+	lyrics.UseCase
+}
+
+func (songUseCase) CreateTrack(ctx context.Context, song models.SongRequest) (models.EnrichmentJob, bool, error) {
+	if song.Group == "Muse" && song.Song == "Uprising" {
+		return models.EnrichmentJob{SongID: 1}, false, nil
+	}
+	return models.EnrichmentJob{ID: 3, SongID: 2, Status: models.JobStatusQueued}, true, nil
+}
+
+func (songUseCase) GetSongByID(ctx context.Context, id uint, lang string) (models.SongInfo, error) {
+	switch id {
+	case 1:
+		return models.SongInfo{ID: 1, Group: "Muse", Song: "Uprising"}, nil
+	case 500:
+		return models.SongInfo{}, errors.New("connection refused")
+	}
+	return models.SongInfo{}, fmt.Errorf("failed to fetch song: %w", sql.ErrNoRows)
+}
+
+func TestGetSongByID_StatusCodes(t *testing.T) {
+	h := lyricsHandlers{cfg: &config.Config{}, lyricsUsecase: songUseCase{}, logger: utils.CreateTestLogger()}
+
+	tests := []struct {
+		name     string
+		id       string
+		query    string
+		wantCode int
+	}{
+		{name: "found", id: "1", wantCode: http.StatusOK},
+		{name: "not found", id: "2", wantCode: http.StatusNotFound},
+		{name: "repository error", id: "500", wantCode: http.StatusInternalServerError},
+		{name: "not a number", id: "abc", wantCode: http.StatusBadRequest},
+		{name: "zero", id: "0", wantCode: http.StatusBadRequest},
+		{name: "negative", id: "-1", wantCode: http.StatusBadRequest},
+		{name: "invalid language", id: "1", query: "?lang=123", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/lyrics/songs/"+tt.id+tt.query, nil)
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			require.NoError(t, h.GetSongByID()(c))
+			assert.Equal(t, tt.wantCode, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestCreateTrack_ExistingSong(t *testing.T) {
+	h := lyricsHandlers{cfg: &config.Config{}, lyricsUsecase: songUseCase{}, logger: utils.CreateTestLogger()}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "new song is queued", body: `{"group":"Muse","song":"Hysteria"}`, wantCode: http.StatusAccepted,
+			wantBody: `{"job_id":3,"song_id":2,"status":"queued","status_url":"/lyrics/jobs/3"}`},
+		{name: "existing song is skipped", body: `{"group":"Muse","song":"Uprising"}`, wantCode: http.StatusOK,
+			wantBody: `{"song_id":1,"message":"song already exists"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/songs", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			require.NoError(t, h.CreateTrack()(echo.New().NewContext(req, rec)))
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// suggestUseCase отдает пустую библиотеку и фиксированные подсказки, запоминает запрос подсказок
+type suggestUseCase struct {
+	lyrics.UseCase
+
+	suggestions models.Suggestions
+	group, song *string
+}
+
+func (u suggestUseCase) GetLibrary(ctx context.Context, filter models.LibraryFilter) (models.LibraryPage, error) {
+	return models.LibraryPage{Songs: []models.Song{}}, nil
+}
+
+func (u suggestUseCase) Suggest(ctx context.Context, group, song string) (models.Suggestions, error) {
+	*u.group, *u.song = group, song
+	return u.suggestions, nil
+}
+
+func newSuggestHandlers(suggestions models.Suggestions) (lyricsHandlers, *string, *string) {
+	group, song := new(string), new(string)
+	uc := suggestUseCase{suggestions: suggestions, group: group, song: song}
+	return lyricsHandlers{cfg: &config.Config{}, lyricsUsecase: uc, logger: utils.CreateTestLogger()}, group, song
+}
+
+func TestSuggest_Query(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantCode  int
+		wantGroup string
+		wantSong  string
+	}{
+		{name: "empty", query: "", wantCode: http.StatusBadRequest},
+		{name: "whitespace", query: "q=%20%20", wantCode: http.StatusBadRequest},
+		{name: "invalid type", query: "q=muse&type=album", wantCode: http.StatusBadRequest},
+		{name: "all by default", query: "q=%20muse%20", wantCode: http.StatusOK, wantGroup: "muse", wantSong: "muse"},
+		{name: "group", query: "q=muse&type=group", wantCode: http.StatusOK, wantGroup: "muse"},
+		{name: "song", query: "q=uprsing&type=song", wantCode: http.StatusOK, wantSong: "uprsing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, group, song := newSuggestHandlers(models.Suggestions{})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/lyrics/suggest?"+tt.query, nil)
+			require.NoError(t, h.Suggest()(echo.New().NewContext(req, rec)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantGroup, *group)
+			assert.Equal(t, tt.wantSong, *song)
+		})
+	}
+}
+
+func TestGetLibrary_DidYouMean(t *testing.T) {
+	found := models.Suggestions{
+		Groups: []models.Suggestion{{ID: 1, Name: "Muse", Similarity: 0.5}},
+		Songs:  []models.Suggestion{},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		suggestions models.Suggestions
+		want        bool
+	}{
+		{name: "misspelled group", query: "group=muze", suggestions: found, want: true},
+		{name: "nothing similar", query: "group=muze", suggestions: models.Suggestions{}},
+		{name: "no name filter", query: "text=love", suggestions: found},
+		{name: "second page", query: "group=muze&page=2", suggestions: found},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _ := newSuggestHandlers(tt.suggestions)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/library?"+tt.query, nil)
+			require.NoError(t, h.GetLibrary()(echo.New().NewContext(req, rec)))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var response map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+			didYouMean, ok := response["did_you_mean"]
+			require.Equal(t, tt.want, ok)
+			if tt.want {
+				assert.JSONEq(t, `{"groups":[{"id":1,"name":"Muse","similarity":0.5}],"songs":[]}`, string(didYouMean))
+			}
+		})
+	}
+}
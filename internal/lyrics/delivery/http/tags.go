@@ -0,0 +1,181 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// bindSongTagsRequest разбирает и проверяет тело запроса на изменение меток песен, метки приводятся к виду без пробелов по краям
+func bindSongTagsRequest(c echo.Context) (models.SongTagsRequest, map[string]interface{}) {
+	var request models.SongTagsRequest
+	if err := c.Bind(&request); err != nil {
+		return request, map[string]interface{}{"error": "invalid JSON format"}
+	}
+
+	for i := range request.Tags {
+		request.Tags[i] = strings.TrimSpace(request.Tags[i])
+	}
+	if err := c.Validate(&request); err != nil {
+		return request, map[string]interface{}{
+			"error":   "validation failed",
+			"details": err.Error(),
+		}
+	}
+
+	return request, nil
+}
+
+// GetTags возвращает список меток.
+// @Summary Список меток
+// @Description Возвращает метки с количеством песен, сначала самые частые, с фильтром по имени
+// @Tags Tags
+// @Produce json
+// @Param name query string false "Фильтр по имени метки"
+// @Param page query int false "Номер страницы"
+// @Param limit query int false "Количество записей на странице"
+// @Success 200 {object} map[string]interface{} "Список меток"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/tags [get]
+func (h lyricsHandlers) GetTags() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		page, err := strconv.Atoi(c.QueryParam("page"))
+		if err != nil || page <= 0 {
+			page = 1
+		}
+
+		limit, err := strconv.Atoi(c.QueryParam("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		tags, total, err := h.lyricsUsecase.GetTags(ctx, c.QueryParam("name"), page, limit)
+		if err != nil {
+			h.logger.Errorf("Error in GetTags: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch tags",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"data":  tags,
+		})
+	}
+}
+
+// DeleteTagByID удаляет метку.
+// @Summary Удаление метки
+// @Description Удаляет метку у всех песен
+// @Tags Tags
+// @Param id path int true "ID метки"
+// @Success 204 "Метка удалена"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Метка не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/tags/{id} [delete]
+func (h lyricsHandlers) DeleteTagByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler DeleteTagByID")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid tag ID",
+			})
+		}
+
+		if err = h.lyricsUsecase.DeleteTagByID(c.Request().Context(), uint(id)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "tag not found",
+				})
+			}
+			h.logger.Errorf("failed to delete tag: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to delete tag",
+			})
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// AttachTags добавляет метки нескольким песням.
+// @Summary Добавление меток песням
+// @Description Добавляет каждой из песен каждую из меток. Метки сравниваются без учета регистра, новые метки создаются.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param body body models.SongTagsRequest true "Песни и метки"
+// @Success 200 {object} models.ClassificationResult "Количество добавленных связей"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 404 {object} map[string]string "Песня не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/tags/attach [post]
+func (h lyricsHandlers) AttachTags() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler AttachTags")
+
+		request, badRequest := bindSongTagsRequest(c)
+		if badRequest != nil {
+			return c.JSON(http.StatusBadRequest, badRequest)
+		}
+
+		result, err := h.lyricsUsecase.AttachTags(c.Request().Context(), request)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found",
+				})
+			}
+			h.logger.Errorf("failed to attach tags: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to attach tags",
+			})
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
+
+// DetachTags убирает метки у нескольких песен.
+// @Summary Удаление меток у песен
+// @Description Убирает у каждой из песен каждую из меток, сами метки остаются
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param body body models.SongTagsRequest true "Песни и метки"
+// @Success 200 {object} models.ClassificationResult "Количество удаленных связей"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/tags/detach [post]
+func (h lyricsHandlers) DetachTags() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler DetachTags")
+
+		request, badRequest := bindSongTagsRequest(c)
+		if badRequest != nil {
+			return c.JSON(http.StatusBadRequest, badRequest)
+		}
+
+		result, err := h.lyricsUsecase.DetachTags(c.Request().Context(), request)
+		if err != nil {
+			h.logger.Errorf("failed to detach tags: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to detach tags",
+			})
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,210 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// parseLangParam код языка из параметра lang, пустая строка - язык не запрошен
+func parseLangParam(c echo.Context) (string, error) {
+	lang := c.QueryParam("lang")
+	if lang == "" {
+		return "", nil
+	}
+	return models.NormalizeLanguage(lang)
+}
+
+// parseTranslationPath ID песни и код языка из пути
+func parseTranslationPath(c echo.Context) (uint, string, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		return 0, "", errors.New("Invalid song ID")
+	}
+
+	lang, err := models.NormalizeLanguage(c.Param("lang"))
+	if err != nil {
+		return 0, "", err
+	}
+
+	return uint(id), lang, nil
+}
+
+// GetTranslations переводы песни.
+// @Summary Переводы песни
+// @Tags Translations
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {array} models.SongTranslation "Переводы по коду языка"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 404 {object} map[string]string "Песня не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id}/translations [get]
+func (h lyricsHandlers) GetTranslations() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler GetTranslations")
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid song ID",
+			})
+		}
+
+		translations, err := h.lyricsUsecase.GetTranslations(c.Request().Context(), uint(id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found",
+				})
+			}
+			h.logger.Errorf("Error in GetTranslations: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch translations",
+			})
+		}
+
+		return c.JSON(http.StatusOK, translations)
+	}
+}
+
+// GetTranslation перевод песни на язык.
+// @Summary Перевод песни
+// @Tags Translations
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lang path string true "Код языка (en, de, pt-BR)"
+// @Success 200 {object} models.SongTranslation "Перевод"
+// @Failure 400 {object} map[string]string "Некорректный ID или код языка"
+// @Failure 404 {object} map[string]string "Перевод не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id}/translations/{lang} [get]
+func (h lyricsHandlers) GetTranslation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler GetTranslation")
+
+		id, lang, err := parseTranslationPath(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		translation, err := h.lyricsUsecase.GetTranslation(c.Request().Context(), id, lang)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "translation not found",
+				})
+			}
+			h.logger.Errorf("Error in GetTranslation: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch translation",
+			})
+		}
+
+		return c.JSON(http.StatusOK, translation)
+	}
+}
+
+// SaveTranslation добавляет или заменяет перевод песни.
+// @Summary Добавление или изменение перевода
+// @Description Строфы перевода разделяются пустыми строками в том же порядке, что и в оригинале, - так куплеты выравниваются с оригиналом
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lang path string true "Код языка (en, de, pt-BR)"
+// @Param body body models.TranslationRequest true "Текст перевода"
+// @Success 200 {object} models.SongTranslation "Перевод изменен"
+// @Success 201 {object} models.SongTranslation "Перевод добавлен"
+// @Failure 400 {object} map[string]string "Некорректный запрос"
+// @Failure 404 {object} map[string]string "Песня не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id}/translations/{lang} [put]
+func (h lyricsHandlers) SaveTranslation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler SaveTranslation")
+
+		id, lang, err := parseTranslationPath(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		var translationRequest models.TranslationRequest
+		if err := c.Bind(&translationRequest); err != nil {
+			h.logger.Debug("in handler SaveTranslation() Bind() return error: ", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid JSON format",
+			})
+		}
+
+		if err := c.Validate(&translationRequest); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":   "validation failed",
+				"details": err.Error(),
+			})
+		}
+
+		translation, created, err := h.lyricsUsecase.SaveTranslation(c.Request().Context(), id, lang, translationRequest.Text)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found",
+				})
+			}
+			h.logger.Errorf("Error in SaveTranslation: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to save translation",
+			})
+		}
+
+		if created {
+			return c.JSON(http.StatusCreated, translation)
+		}
+		return c.JSON(http.StatusOK, translation)
+	}
+}
+
+// DeleteTranslation удаляет перевод песни.
+// @Summary Удаление перевода
+// @Tags Translations
+// @Param id path int true "ID песни"
+// @Param lang path string true "Код языка (en, de, pt-BR)"
+// @Success 204 "Перевод удален"
+// @Failure 400 {object} map[string]string "Некорректный ID или код языка"
+// @Failure 404 {object} map[string]string "Перевод не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id}/translations/{lang} [delete]
+func (h lyricsHandlers) DeleteTranslation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler DeleteTranslation")
+
+		id, lang, err := parseTranslationPath(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		if err := h.lyricsUsecase.DeleteTranslation(c.Request().Context(), id, lang); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "translation not found",
+				})
+			}
+			h.logger.Errorf("Error in DeleteTranslation: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to delete translation",
+			})
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
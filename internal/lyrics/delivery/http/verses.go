@@ -0,0 +1,75 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// GetSongVerses все куплеты песни.
+// @Summary Все куплеты песни
+// @Description Без параметра page возвращает все страницы куплетов и их количество в total.
+// @Description С параметром page работает как получение одного куплета.
+// @Tags Songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param page query int false "Номер страницы, без него - все страницы"
+// @Param mode query string false "Разбиение на страницы: verses (по умолчанию), lines или chars"
+// @Param size query int false "Размер страницы для выбранного mode"
+// @Param lang query string false "Код языка перевода, который вернется рядом с оригиналом"
+// @Success 200 {object} models.SongVerses "Куплеты песни"
+// @Failure 400 {object} map[string]string "Некорректный ID, способ разбиения или код языка"
+// @Failure 404 {object} map[string]string "Песня не найдена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /lyrics/songs/{id}/verses [get]
+func (h lyricsHandlers) GetSongVerses() echo.HandlerFunc {
+	getVerse := h.GetSongVerseByID()
+
+	return func(c echo.Context) error {
+		h.logger.Debug("in handler GetSongVerses")
+
+		if c.QueryParam("page") != "" {
+			return getVerse(c)
+		}
+
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid song ID",
+			})
+		}
+
+		paging, err := models.ParseVersePaging(c.QueryParam("mode"), c.QueryParam("size"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		lang, err := parseLangParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		verses, err := h.lyricsUsecase.GetSongVerses(c.Request().Context(), uint(id), paging, lang)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "song not found",
+				})
+			}
+			h.logger.Errorf("Error in GetSongVerses: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to fetch verses",
+			})
+		}
+
+		return c.JSON(http.StatusOK, verses)
+	}
+}
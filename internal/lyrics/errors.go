@@ -0,0 +1,44 @@
+package lyrics
+
+import "errors"
+
+var (
+	// ErrEmptySearchQuery поисковый запрос не содержит ни одного слова
+	ErrEmptySearchQuery = errors.New("search query is empty")
+
+	// ErrConflict запись нарушает уникальность (имя уже занято, номер трека уже используется и т.п.)
+	ErrConflict = errors.New("conflict")
+
+	// ErrInvalidCursor курсор пагинации поврежден или выдан для другой сортировки
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrLyricsNotFound ни один источник не нашел текст песни
+	ErrLyricsNotFound = errors.New("lyrics not found")
+
+	// ErrRefreshInProgress повторная загрузка песен уже идет
+	ErrRefreshInProgress = errors.New("refresh is already running")
+
+	// ErrRefreshUnavailable планировщик обновления еще не запущен или уже остановлен
+	ErrRefreshUnavailable = errors.New("refresh scheduler is not running")
+
+	// ErrEnrichmentJobLost задачу обогащения, зависшую у воркера, уже забрал другой воркер
+	ErrEnrichmentJobLost = errors.New("enrichment job was reclaimed by another worker")
+
+	// ErrInvalidImportFile файл импорта в неизвестном формате или без обязательных колонок
+	ErrInvalidImportFile = errors.New("invalid import file")
+
+	// ErrInvalidLRC синхронизированный текст не в формате LRC
+	ErrInvalidLRC = errors.New("invalid LRC")
+
+	// ErrGroupHasSongs у группы есть песни, а удаление без cascade
+	ErrGroupHasSongs = errors.New("group has songs")
+
+	// ErrAlbumHasSongs у альбома есть песни, а группа альбома меняется
+	ErrAlbumHasSongs = errors.New("album has songs")
+
+	// ErrInvalidGenreParent родительский жанр не существует или является самим жанром либо его поджанром
+	ErrInvalidGenreParent = errors.New("invalid parent genre")
+
+	// ErrNoLRC у песни нет синхронизированного текста
+	ErrNoLRC = errors.New("song has no timed lyrics")
+)
@@ -5,6 +5,7 @@ package lyrics
 
 import (
 	"context"
+	"time"
 
 	"github.com/22Fariz22/musiclab/internal/models"
 )
@@ -13,7 +14,55 @@ type Repository interface {
 	Ping() error
 	DeleteSongByID(ctx context.Context, ID uint) error
 	UpdateTrackByID(ctx context.Context, updateData models.UpdateTrackRequest) error
-	CreateTrack(ctx context.Context, song models.SongRequest, songDetail models.SongDetail) error
+	CreateTrack(ctx context.Context, song models.SongRequest, songDetail models.SongDetail) (uint, bool, error)
 	GetSongByID(ctx context.Context, id uint) (models.Song, error)
-	GetLibrary(ctx context.Context, group, song, text, releaseDate string, offset, limit int) ([]models.Song, int, error)
+	SetSongLRC(ctx context.Context, id uint, lrc *string) error
+	SaveTranslation(ctx context.Context, songID uint, language, text string) (models.SongTranslation, bool, error)
+	GetTranslations(ctx context.Context, songID uint) ([]models.SongTranslation, error)
+	GetTranslation(ctx context.Context, songID uint, language string) (models.SongTranslation, error)
+	DeleteTranslation(ctx context.Context, songID uint, language string) error
+	CreatePendingSong(ctx context.Context, song models.SongRequest) (models.EnrichmentJob, bool, error)
+	ClaimEnrichmentJob(ctx context.Context, staleAfter time.Duration, maxAttempts int) (models.EnrichmentJob, error)
+	CompleteEnrichmentJob(ctx context.Context, job models.EnrichmentJob, songDetail models.SongDetail) error
+	FailEnrichmentJob(ctx context.Context, job models.EnrichmentJob, reason string) error
+	GetEnrichmentJob(ctx context.Context, id uint) (models.EnrichmentJob, error)
+	GetSongsForRefresh(ctx context.Context, staleBefore, retryBefore time.Time, limit int) ([]models.Song, error)
+	ApplySongRefresh(ctx context.Context, songID uint, songDetail models.SongDetail) ([]models.SongChange, error)
+	MarkSongRefreshed(ctx context.Context, songID uint) error
+	ImportSongs(ctx context.Context, rows []models.ImportRow, opts models.ImportOptions) ([]models.ImportRowResult, error)
+	GetLibrary(ctx context.Context, filter models.LibraryFilter) (models.LibraryPage, error)
+	ExportLibrary(ctx context.Context, filter models.LibraryFilter, fetchSize int, fn func(models.Song) error) error
+	SearchLyrics(ctx context.Context, query string, offset, limit int) ([]models.SearchResult, int, error)
+	Suggest(ctx context.Context, group, song string, threshold float64, limit int) (models.Suggestions, error)
+
+	CreateAlbum(ctx context.Context, album models.AlbumRequest) (models.Album, error)
+	GetAlbums(ctx context.Context, group string, offset, limit int) ([]models.Album, int, error)
+	GetAlbumByID(ctx context.Context, id uint) (models.Album, error)
+	UpdateAlbumByID(ctx context.Context, id uint, album models.AlbumRequest) error
+	DeleteAlbumByID(ctx context.Context, id uint) error
+	AttachSongsToAlbum(ctx context.Context, albumID uint, tracks []models.AlbumTrackRequest) error
+	DetachSongFromAlbum(ctx context.Context, albumID, songID uint) error
+
+	CreateGroup(ctx context.Context, group models.GroupRequest) (models.GroupInfo, error)
+	GetGroups(ctx context.Context, name string, offset, limit int) ([]models.GroupInfo, int, error)
+	GetGroupByID(ctx context.Context, id uint) (models.GroupInfo, error)
+	UpdateGroupByID(ctx context.Context, id uint, group models.GroupRequest) (models.GroupInfo, error)
+	DeleteGroupByID(ctx context.Context, id uint, cascade bool) (models.GroupDeleteResult, error)
+	DeleteOrphanGroups(ctx context.Context, updatedBefore time.Time) (int, error)
+	AddGroupAlias(ctx context.Context, groupID uint, alias string) (models.GroupInfo, error)
+	DeleteGroupAlias(ctx context.Context, groupID uint, alias string) error
+	MergeGroups(ctx context.Context, sourceID, targetID uint, strategy string) (models.GroupMergeResult, error)
+
+	GetGenres(ctx context.Context) ([]models.GenreInfo, error)
+	GetGenreByID(ctx context.Context, id uint) (models.GenreInfo, error)
+	CreateGenre(ctx context.Context, genre models.GenreRequest) (models.GenreInfo, error)
+	UpdateGenreByID(ctx context.Context, id uint, genre models.GenreRequest) (models.GenreInfo, error)
+	DeleteGenreByID(ctx context.Context, id uint) error
+	AttachGenres(ctx context.Context, songIDs, genreIDs []uint) (int, error)
+	DetachGenres(ctx context.Context, songIDs, genreIDs []uint) (int, error)
+
+	GetTags(ctx context.Context, name string, offset, limit int) ([]models.TagInfo, int, error)
+	DeleteTagByID(ctx context.Context, id uint) error
+	AttachTags(ctx context.Context, songIDs []uint, tags []string) (int, error)
+	DetachTags(ctx context.Context, songIDs []uint, tags []string) (int, error)
 }
@@ -0,0 +1,17 @@
+package lyrics
+
+import (
+	"context"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// LyricsProvider источник данных о песне: дата выхода, текст и ссылка.
+// Если у источника нет песни, Fetch возвращает ErrLyricsNotFound.
+type LyricsProvider interface {
+	// Name имя источника, сохраняется в песне как ее происхождение
+	Name() string
+	Fetch(ctx context.Context, group, song string) (models.SongDetail, error)
+	// Health состояние источника, цепочка возвращает состояние каждого своего источника
+	Health() []models.ProviderHealth
+}
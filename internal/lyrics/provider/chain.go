@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/logger"
+)
+
+// chainProvider опрашивает источники по порядку до первого, вернувшего текст
+type chainProvider struct {
+	providers []lyrics.LyricsProvider
+	logger    logger.Logger
+}
+
+// NewChain цепочка источников в порядке приоритета.
+// Если источник вернул ошибку или пустой текст, запрашивается следующий.
+func NewChain(logger logger.Logger, providers ...lyrics.LyricsProvider) lyrics.LyricsProvider {
+	return &chainProvider{providers: providers, logger: logger}
+}
+
+// NewFromConfig цепочка источников в порядке из cfg.Providers.Order
+func NewFromConfig(cfg *config.Config, logger logger.Logger) (lyrics.LyricsProvider, error) {
+	providers := make([]lyrics.LyricsProvider, 0, len(cfg.Providers.Order))
+
+	for _, name := range cfg.Providers.Order {
+		switch name {
+		case HTTPName:
+			providers = append(providers, NewHTTPProvider(cfg, logger))
+		case FileName:
+			providers = append(providers, NewFileProvider(cfg.Providers.FileDir, logger))
+		default:
+			return nil, fmt.Errorf("unknown lyrics provider %q", name)
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil, errors.New("no lyrics providers configured")
+	}
+
+	return NewChain(logger, providers...), nil
+}
+
+func (p chainProvider) Name() string {
+	names := make([]string, 0, len(p.providers))
+	for _, provider := range p.providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (p chainProvider) Health() []models.ProviderHealth {
+	health := []models.ProviderHealth{}
+	for _, provider := range p.providers {
+		health = append(health, provider.Health()...)
+	}
+	return health
+}
+
+// Fetch возвращает данные первого источника с непустым текстом, Source - имя этого источника.
+// ErrLyricsNotFound возвращается, только если песни нет ни в одном источнике; сбой хотя бы одного - обычная ошибка.
+func (p chainProvider) Fetch(ctx context.Context, group, song string) (models.SongDetail, error) {
+	var errs []error
+	notFound := true
+
+	for _, provider := range p.providers {
+		songDetail, err := provider.Fetch(ctx, group, song)
+		if err == nil && strings.TrimSpace(songDetail.Text) == "" {
+			err = fmt.Errorf("empty text: %w", lyrics.ErrLyricsNotFound)
+		}
+		if err != nil {
+			p.logger.Warnf("lyrics provider %s failed for %s - %s: %v", provider.Name(), group, song, err)
+			errs = append(errs, fmt.Errorf("%s: %v", provider.Name(), err))
+			notFound = notFound && errors.Is(err, lyrics.ErrLyricsNotFound)
+
+			// Запрос отменен - остальные источники тоже не успеют
+			if ctx.Err() != nil {
+				return models.SongDetail{}, fmt.Errorf("%w: %v", ctx.Err(), errors.Join(errs...))
+			}
+			continue
+		}
+
+		songDetail.Source = provider.Name()
+		return songDetail, nil
+	}
+
+	if notFound {
+		return models.SongDetail{}, fmt.Errorf("%w: %v", lyrics.ErrLyricsNotFound, errors.Join(errs...))
+	}
+	return models.SongDetail{}, fmt.Errorf("all lyrics providers failed: %v", errors.Join(errs...))
+}
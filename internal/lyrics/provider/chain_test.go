@@ -0,0 +1,73 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/lyrics/provider"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingProvider всегда возвращает ошибку, как недоступное API
+type failingProvider struct{}
+
+func (failingProvider) Name() string { return "failing" }
+
+func (failingProvider) Health() []models.ProviderHealth { return nil }
+
+func (failingProvider) Fetch(ctx context.Context, group, song string) (models.SongDetail, error) {
+	return models.SongDetail{}, errors.New("connection refused")
+}
+
+func TestChain_FallsBackToNextProvider(t *testing.T) {
+	empty := provider.NewStaticProvider().Add("Muse", "Uprising", models.SongDetail{Text: "  "})
+	fixture := provider.NewStaticProvider().Add("muse", "uprising", models.SongDetail{Text: "verse", Link: "https://example.com"})
+
+	chain := provider.NewChain(utils.CreateTestLogger(), failingProvider{}, empty, fixture)
+
+	songDetail, err := chain.Fetch(context.Background(), "Muse", "Uprising")
+	require.NoError(t, err)
+	assert.Equal(t, "verse", songDetail.Text)
+	assert.Equal(t, provider.StaticName, songDetail.Source)
+}
+
+func TestChain_NotFoundOnlyWhenEveryProviderMisses(t *testing.T) {
+	logger := utils.CreateTestLogger()
+
+	_, err := provider.NewChain(logger, provider.NewStaticProvider(), provider.NewFileProvider(t.TempDir(), logger)).
+		Fetch(context.Background(), "Muse", "Uprising")
+	assert.ErrorIs(t, err, lyrics.ErrLyricsNotFound)
+
+	_, err = provider.NewChain(logger, failingProvider{}, provider.NewStaticProvider()).
+		Fetch(context.Background(), "Muse", "Uprising")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, lyrics.ErrLyricsNotFound, "a failed provider is not a miss")
+}
+
+func TestFileProvider_ReadsJSONAndText(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Muse"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Muse", "Uprising.json"),
+		[]byte(`{"releaseDate":"16.07.2006","text":"json verse","link":"https://example.com"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Muse", "Starlight.txt"), []byte("text verse"), 0o644))
+
+	fileProvider := provider.NewFileProvider(dir, utils.CreateTestLogger())
+
+	songDetail, err := fileProvider.Fetch(context.Background(), "Muse", "Uprising")
+	require.NoError(t, err)
+	assert.Equal(t, models.SongDetail{ReleaseDate: "16.07.2006", Text: "json verse", Link: "https://example.com"}, songDetail)
+
+	songDetail, err = fileProvider.Fetch(context.Background(), "Muse", "Starlight")
+	require.NoError(t, err)
+	assert.Equal(t, "text verse", songDetail.Text)
+
+	_, err = fileProvider.Fetch(context.Background(), "..", "Uprising")
+	assert.ErrorIs(t, err, lyrics.ErrLyricsNotFound)
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/logger"
+)
+
+// FileName имя локального каталога в цепочке источников
+const FileName = "file"
+
+// fileProvider локальный каталог с песнями.
+// Песня ищется в <dir>/<группа>/<песня>.json (формат ответа API: releaseDate, text, link),
+// затем в <dir>/<группа>/<песня>.txt (только текст).
+type fileProvider struct {
+	dir    string
+	logger logger.Logger
+}
+
+// NewFileProvider источник, читающий песни из каталога dir
+func NewFileProvider(dir string, logger logger.Logger) lyrics.LyricsProvider {
+	return &fileProvider{dir: dir, logger: logger}
+}
+
+func (p fileProvider) Name() string {
+	return FileName
+}
+
+func (p fileProvider) Fetch(ctx context.Context, group, song string) (models.SongDetail, error) {
+	base := filepath.Join(p.dir, fileSafeName(group), fileSafeName(song))
+	p.logger.Debugf("in fileProvider Fetch() path: %s", base)
+
+	data, err := os.ReadFile(base + ".json")
+	if err == nil {
+		var songDetail models.SongDetail
+		if err := json.Unmarshal(data, &songDetail); err != nil {
+			return models.SongDetail{}, fmt.Errorf("decoding %s.json: %w", base, err)
+		}
+		return songDetail, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return models.SongDetail{}, fmt.Errorf("reading %s.json: %w", base, err)
+	}
+
+	data, err = os.ReadFile(base + ".txt")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return models.SongDetail{}, fmt.Errorf("%s - %s: %w", group, song, lyrics.ErrLyricsNotFound)
+		}
+		return models.SongDetail{}, fmt.Errorf("reading %s.txt: %w", base, err)
+	}
+
+	return models.SongDetail{Text: string(data)}, nil
+}
+
+// Health каталог должен существовать
+func (p fileProvider) Health() []models.ProviderHealth {
+	status := models.HealthUp
+	if info, err := os.Stat(p.dir); err != nil || !info.IsDir() {
+		status = models.HealthDown
+	}
+	return []models.ProviderHealth{{Name: FileName, Status: status}}
+}
+
+// fileSafeName имя файла из названия: без разделителей пути, чтобы нельзя было выйти за пределы каталога
+func fileSafeName(name string) string {
+	name = strings.NewReplacer("/", "_", `\`, "_").Replace(strings.TrimSpace(name))
+	if name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/httpclient"
+	"github.com/22Fariz22/musiclab/pkg/logger"
+)
+
+// HTTPName имя внешнего API в цепочке источников
+const HTTPName = "http"
+
+// httpProvider внешнее API с данными о песнях
+type httpProvider struct {
+	cfg        *config.Config
+	logger     logger.Logger
+	httpClient *httpclient.Client
+}
+
+// NewHTTPProvider источник, запрашивающий внешнее API по адресу из конфигурации.
+// Повторы и автоматический выключатель настраиваются в cfg.API.
+func NewHTTPProvider(cfg *config.Config, logger logger.Logger) lyrics.LyricsProvider {
+	httpClient := httpclient.New(HTTPName, httpclient.Config{
+		MaxRetries:       cfg.API.MaxRetries,
+		BaseDelay:        cfg.API.RetryDelay,
+		MaxDelay:         cfg.API.MaxRetryDelay,
+		Timeout:          cfg.API.RequestTimeout,
+		BreakerThreshold: cfg.API.BreakerThreshold,
+		BreakerCooldown:  cfg.API.BreakerCooldown,
+	}, logger)
+
+	return &httpProvider{cfg: cfg, logger: logger, httpClient: httpClient}
+}
+
+func (p httpProvider) Name() string {
+	return HTTPName
+}
+
+// Health источник недоступен, пока разомкнут автомат
+func (p httpProvider) Health() []models.ProviderHealth {
+	stats := p.httpClient.Breaker()
+
+	health := models.ProviderHealth{
+		Name:   HTTPName,
+		Status: models.HealthUp,
+		Circuit: &models.CircuitHealth{
+			State:               string(stats.State),
+			ConsecutiveFailures: stats.ConsecutiveFailures,
+		},
+	}
+	if stats.State == httpclient.StateOpen {
+		health.Status = models.HealthDown
+		health.Circuit.OpenUntil = &stats.OpenUntil
+	}
+
+	return []models.ProviderHealth{health}
+}
+
+// Fetch запрашивает песню у API
+func (p httpProvider) Fetch(ctx context.Context, group, song string) (models.SongDetail, error) {
+	fullURL, err := p.BuildAPIURL(group, song)
+	if err != nil {
+		p.logger.Errorf("failed to build API URL: %v", err)
+		return models.SongDetail{}, err
+	}
+
+	p.logger.Debugf("UrlAPI: %s", fullURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		p.logger.Errorf("failed to create request: %v", err)
+		return models.SongDetail{}, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		p.logger.Errorf("request failed: %v", err)
+		return models.SongDetail{}, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return models.SongDetail{}, fmt.Errorf("API returned %d: %w", resp.StatusCode, lyrics.ErrLyricsNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return models.SongDetail{}, fmt.Errorf("API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var songDetail models.SongDetail
+	if err := json.NewDecoder(resp.Body).Decode(&songDetail); err != nil {
+		p.logger.Errorf("failed to decode response: %v", err)
+		return models.SongDetail{}, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return songDetail, nil
+}
+
+func (p httpProvider) BuildAPIURL(group, song string) (string, error) {
+	APIAddr := fmt.Sprintf("%s:%s%s", p.cfg.Server.BaseUrl, p.cfg.Server.Port, p.cfg.API.APIPath)
+
+	parsedURL, err := url.Parse(APIAddr)
+	if err != nil {
+		return "", fmt.Errorf("parsing URL: %w", err)
+	}
+
+	q := parsedURL.Query()
+	q.Set("group", group)
+	q.Set("song", song)
+	parsedURL.RawQuery = q.Encode()
+
+	p.logger.Debugf("APIAddr:%s", APIAddr)
+	p.logger.Debugf("parsedURL:%s", parsedURL.String())
+
+	return parsedURL.String(), nil
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// StaticName имя набора фиксированных песен в цепочке источников
+const StaticName = "static"
+
+// StaticProvider фиксированный набор песен в памяти, используется в тестах и для демо-данных
+type StaticProvider struct {
+	songs map[string]models.SongDetail
+}
+
+// NewStaticProvider пустой набор песен
+func NewStaticProvider() *StaticProvider {
+	return &StaticProvider{songs: map[string]models.SongDetail{}}
+}
+
+// Add добавляет песню, регистр названий не учитывается
+func (p *StaticProvider) Add(group, song string, songDetail models.SongDetail) *StaticProvider {
+	p.songs[staticKey(group, song)] = songDetail
+	return p
+}
+
+func (p *StaticProvider) Name() string {
+	return StaticName
+}
+
+func (p *StaticProvider) Fetch(ctx context.Context, group, song string) (models.SongDetail, error) {
+	songDetail, ok := p.songs[staticKey(group, song)]
+	if !ok {
+		return models.SongDetail{}, fmt.Errorf("%s - %s: %w", group, song, lyrics.ErrLyricsNotFound)
+	}
+	return songDetail, nil
+}
+
+func (p *StaticProvider) Health() []models.ProviderHealth {
+	return []models.ProviderHealth{{Name: StaticName, Status: models.HealthUp}}
+}
+
+func staticKey(group, song string) string {
+	return strings.ToLower(strings.TrimSpace(group)) + "\x00" + strings.ToLower(strings.TrimSpace(song))
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/pkg/errors"
+)
+
+// CreateAlbum создает альбом, группа создается при необходимости
+func (r lyricsRepo) CreateAlbum(ctx context.Context, album models.AlbumRequest) (models.Album, error) {
+	r.logger.Debugf("in repo CreateAlbum() album: %+v", album)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.Album{}, errors.Wrap(err, "lyricsRepo.CreateAlbum.BeginTx")
+	}
+	defer tx.Rollback()
+
+	groupID, err := getOrCreateGroup(ctx, tx, album.Group)
+	if err != nil {
+		r.logger.Errorf("error getting/creating group: %v", err)
+		return models.Album{}, errors.Wrap(err, "lyricsRepo.CreateAlbum.QueryGroup")
+	}
+
+	releaseDate, err := releaseDateArg(album.ReleaseDate)
+	if err != nil {
+		return models.Album{}, errors.Wrap(err, "lyricsRepo.CreateAlbum.ParseReleaseDate")
+	}
+
+	// Имя группы берется из базы: запрос мог найти группу по псевдониму или в другом написании
+	var created models.Album
+	queryInsert := `
+        WITH ins AS (
+            INSERT INTO albums (group_id, title, release_date, created_at, updated_at)
+            VALUES ($1, $2, $3, NOW(), NOW())
+            RETURNING id, group_id, title, release_date, created_at, updated_at
+        )
+        SELECT ins.id, ins.group_id, g.name AS group_name, ins.title, ins.release_date, ins.created_at, ins.updated_at
+        FROM ins
+        INNER JOIN groups g ON g.id = ins.group_id
+    `
+	err = tx.GetContext(ctx, &created, queryInsert, groupID, album.Title, releaseDate)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Album{}, errors.Wrap(lyrics.ErrConflict, "album already exists")
+		}
+		r.logger.Errorf("error inserting album: %v", err)
+		return models.Album{}, errors.Wrap(err, "lyricsRepo.CreateAlbum.InsertAlbum")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.Album{}, errors.Wrap(err, "lyricsRepo.CreateAlbum.Commit")
+	}
+
+	created.Tracks = []models.AlbumTrack{}
+	return created, nil
+}
+
+// GetAlbums список альбомов с количеством песен, с фильтром по группе и пагинацией
+func (r lyricsRepo) GetAlbums(ctx context.Context, group string, offset, limit int) ([]models.Album, int, error) {
+	albums := []models.Album{}
+	var total int
+
+	condition := ""
+	args := []interface{}{}
+	if group != "" {
+		condition = " WHERE g.name ILIKE $1"
+		args = append(args, "%"+group+"%")
+	}
+
+	query := `SELECT a.id, a.group_id, g.name AS group_name, a.title, a.release_date, a.created_at, a.updated_at,
+                     (SELECT COUNT(*) FROM songs s WHERE s.album_id = a.id) AS track_count
+              FROM albums a
+              INNER JOIN groups g ON a.group_id = g.id` + condition +
+		" ORDER BY g.name, a.release_date NULLS LAST, a.id LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
+
+	if err := r.db.SelectContext(ctx, &albums, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch albums: %w", err)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM albums a INNER JOIN groups g ON a.group_id = g.id` + condition
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch albums total count: %w", err)
+	}
+
+	return albums, total, nil
+}
+
+// GetAlbumByID альбом с песнями в порядке номеров треков
+func (r lyricsRepo) GetAlbumByID(ctx context.Context, id uint) (models.Album, error) {
+	var album models.Album
+	query := `SELECT a.id, a.group_id, g.name AS group_name, a.title, a.release_date, a.created_at, a.updated_at
+              FROM albums a
+              INNER JOIN groups g ON a.group_id = g.id
+              WHERE a.id = $1`
+	if err := r.db.GetContext(ctx, &album, query, id); err != nil {
+		return models.Album{}, fmt.Errorf("failed to fetch album: %w", err)
+	}
+
+	album.Tracks = []models.AlbumTrack{}
+	queryTracks := `SELECT id AS song_id, song_name, track_number
+                    FROM songs
+                    WHERE album_id = $1
+                    ORDER BY track_number NULLS LAST, id`
+	if err := r.db.SelectContext(ctx, &album.Tracks, queryTracks, id); err != nil {
+		return models.Album{}, fmt.Errorf("failed to fetch album tracks: %w", err)
+	}
+	album.TrackCount = len(album.Tracks)
+
+	return album, nil
+}
+
+// UpdateAlbumByID обновляет название, группу и дату выхода альбома.
+// Группу альбома с песнями сменить нельзя (ErrAlbumHasSongs): песни альбома принадлежат его группе.
+func (r lyricsRepo) UpdateAlbumByID(ctx context.Context, id uint, album models.AlbumRequest) error {
+	r.logger.Debugf("in repo UpdateAlbumByID() id: %d, album: %+v", id, album)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.UpdateAlbumByID.BeginTx")
+	}
+	defer tx.Rollback()
+
+	// Блокируем альбом, чтобы к нему не добавили песни старой группы, пока группа меняется
+	var currentGroupID uint
+	if err = tx.GetContext(ctx, &currentGroupID, `SELECT group_id FROM albums WHERE id = $1 FOR UPDATE`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrap(sql.ErrNoRows, "album not found")
+		}
+		return errors.Wrap(err, "lyricsRepo.UpdateAlbumByID.LockAlbum")
+	}
+
+	groupID, err := getOrCreateGroup(ctx, tx, album.Group)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.UpdateAlbumByID.QueryGroup")
+	}
+
+	if groupID != currentGroupID {
+		var hasSongs bool
+		if err = tx.GetContext(ctx, &hasSongs, `SELECT EXISTS (SELECT 1 FROM songs WHERE album_id = $1)`, id); err != nil {
+			return errors.Wrap(err, "lyricsRepo.UpdateAlbumByID.CountSongs")
+		}
+		if hasSongs {
+			return errors.Wrap(lyrics.ErrAlbumHasSongs, "album has songs of its group")
+		}
+	}
+
+	releaseDate, err := releaseDateArg(album.ReleaseDate)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.UpdateAlbumByID.ParseReleaseDate")
+	}
+
+	query := `UPDATE albums SET group_id = $1, title = $2, release_date = $3, updated_at = NOW() WHERE id = $4`
+	result, err := tx.ExecContext(ctx, query, groupID, album.Title, releaseDate, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.Wrap(lyrics.ErrConflict, "album already exists")
+		}
+		return errors.Wrap(err, "lyricsRepo.UpdateAlbumByID.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.UpdateAlbumByID.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "album not found")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "lyricsRepo.UpdateAlbumByID.Commit")
+	}
+
+	return nil
+}
+
+// DeleteAlbumByID удаляет альбом, песни альбома остаются в библиотеке без номера трека
+func (r lyricsRepo) DeleteAlbumByID(ctx context.Context, id uint) error {
+	r.logger.Debugf("in repo DeleteAlbumByID() id: %d", id)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteAlbumByID.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `UPDATE songs SET album_id = NULL, track_number = NULL WHERE album_id = $1`, id); err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteAlbumByID.DetachSongs")
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM albums WHERE id = $1`, id)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteAlbumByID.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteAlbumByID.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteAlbumByID.Commit")
+	}
+
+	return nil
+}
+
+// AttachSongsToAlbum добавляет песни в альбом с указанными номерами треков.
+// Номера треков можно переставлять внутри одного запроса, занятый другой песней номер - конфликт.
+func (r lyricsRepo) AttachSongsToAlbum(ctx context.Context, albumID uint, tracks []models.AlbumTrackRequest) error {
+	r.logger.Debugf("in repo AttachSongsToAlbum() albumID: %d, tracks: %+v", albumID, tracks)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.AttachSongsToAlbum.BeginTx")
+	}
+	defer tx.Rollback()
+
+	// Блокируем альбом, чтобы параллельные запросы не раздали одинаковые номера
+	var albumGroupID uint
+	err = tx.QueryRowContext(ctx, `SELECT group_id FROM albums WHERE id = $1 FOR UPDATE`, albumID).Scan(&albumGroupID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrap(sql.ErrNoRows, "album not found")
+		}
+		return errors.Wrap(err, "lyricsRepo.AttachSongsToAlbum.LockAlbum")
+	}
+
+	// В альбом попадают только песни его группы: удаление и слияние групп переносят альбомы вместе с песнями группы
+	for _, track := range tracks {
+		var songGroupID uint
+		err = tx.QueryRowContext(ctx, `SELECT group_id FROM songs WHERE id = $1 FOR UPDATE`, track.SongID).Scan(&songGroupID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(sql.ErrNoRows, "song %d not found", track.SongID)
+			}
+			return errors.Wrap(err, "lyricsRepo.AttachSongsToAlbum.LockSong")
+		}
+		if songGroupID != albumGroupID {
+			return errors.Wrapf(lyrics.ErrConflict, "song %d belongs to another group than the album", track.SongID)
+		}
+	}
+
+	// Сначала освобождаем номера, чтобы перестановка треков не упиралась в уникальный индекс
+	for _, track := range tracks {
+		if _, err := tx.ExecContext(ctx, `UPDATE songs SET track_number = NULL WHERE id = $1`, track.SongID); err != nil {
+			return errors.Wrap(err, "lyricsRepo.AttachSongsToAlbum.ResetTrackNumber")
+		}
+	}
+
+	for _, track := range tracks {
+		query := `UPDATE songs SET album_id = $1, track_number = $2, updated_at = NOW()
+                  WHERE id = $3 AND group_id = (SELECT group_id FROM albums WHERE id = $1)`
+		if _, err := tx.ExecContext(ctx, query, albumID, track.TrackNumber, track.SongID); err != nil {
+			if isUniqueViolation(err) {
+				return errors.Wrapf(lyrics.ErrConflict, "track number %d is already taken", track.TrackNumber)
+			}
+			return errors.Wrap(err, "lyricsRepo.AttachSongsToAlbum.SetTrack")
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "lyricsRepo.AttachSongsToAlbum.Commit")
+	}
+
+	return nil
+}
+
+// DetachSongFromAlbum убирает песню из альбома
+func (r lyricsRepo) DetachSongFromAlbum(ctx context.Context, albumID, songID uint) error {
+	query := `UPDATE songs SET album_id = NULL, track_number = NULL, updated_at = NOW() WHERE id = $1 AND album_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, songID, albumID)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DetachSongFromAlbum.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DetachSongFromAlbum.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "song not found in album")
+	}
+
+	return nil
+}
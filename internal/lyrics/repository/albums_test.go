@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachSongsToAlbum(t *testing.T) {
+	// Альбом 1 у группы 1
+	tests := []struct {
+		name      string
+		songGroup int64
+		songFound bool
+		wantErr   error
+	}{
+		{name: "song of the album group", songGroup: 1, songFound: true},
+		{name: "song of another group", songGroup: 2, songFound: true, wantErr: lyrics.ErrConflict},
+		{name: "missing song", wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, nil)
+			fake.value = func(query string) (int64, bool) {
+				if strings.Contains(query, "FROM songs") {
+					return tt.songGroup, tt.songFound
+				}
+				return 1, true
+			}
+			repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+			err := repo.AttachSongsToAlbum(context.Background(), 1, []models.AlbumTrackRequest{{SongID: 7, TrackNumber: 1}})
+
+			events := fake.log()
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "unexpected error: %v", err)
+				assert.NotContains(t, strings.Join(events, "\n"), "UPDATE songs")
+				assert.Equal(t, "rollback", events[len(events)-1])
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, strings.Join(events, "\n"), "AND group_id = (SELECT group_id FROM albums WHERE id = $1)")
+			assert.Equal(t, "commit", events[len(events)-1])
+		})
+	}
+}
+
+func TestAttachSongsToAlbum_FreesTrackNumbersBeforeAssigning(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	// Песни меняются номерами: оба номера сбрасываются до первой записи
+	err := repo.AttachSongsToAlbum(context.Background(), 1, []models.AlbumTrackRequest{
+		{SongID: 7, TrackNumber: 2},
+		{SongID: 8, TrackNumber: 1},
+	})
+	require.NoError(t, err)
+
+	var updates []string
+	for _, event := range fake.log() {
+		if strings.Contains(event, "UPDATE songs") {
+			updates = append(updates, event)
+		}
+	}
+	require.Len(t, updates, 4)
+	assert.Contains(t, updates[0], "SET track_number = NULL")
+	assert.Contains(t, updates[1], "SET track_number = NULL")
+	assert.Contains(t, updates[2], "SET album_id = $1, track_number = $2")
+	assert.Contains(t, updates[3], "SET album_id = $1, track_number = $2")
+}
+
+func TestAttachSongsToAlbum_TakenTrackNumberIsConflict(t *testing.T) {
+	db, fake := newFakeDB(t, func(query string) error {
+		if strings.Contains(query, "SET album_id") {
+			return pgx.PgError{Code: "23505"}
+		}
+		return nil
+	})
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	err := repo.AttachSongsToAlbum(context.Background(), 1, []models.AlbumTrackRequest{{SongID: 7, TrackNumber: 3}})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, lyrics.ErrConflict), "unexpected error: %v", err)
+
+	events := fake.log()
+	assert.Equal(t, "rollback", events[len(events)-1])
+}
+
+func TestUpdateAlbumByID_GroupChange(t *testing.T) {
+	// Альбом 1 у группы 1
+	tests := []struct {
+		name     string
+		newGroup int64
+		hasSongs bool
+		wantErr  error
+	}{
+		{name: "same group with songs", newGroup: 1, hasSongs: true},
+		{name: "new group without songs", newGroup: 2},
+		{name: "new group with songs", newGroup: 2, hasSongs: true, wantErr: lyrics.ErrAlbumHasSongs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, nil)
+			fake.value = func(query string) (int64, bool) {
+				switch {
+				case strings.Contains(query, "FROM albums"):
+					return 1, true
+				case strings.Contains(query, "SELECT EXISTS"):
+					if tt.hasSongs {
+						return 1, true
+					}
+					return 0, true
+				}
+				return tt.newGroup, true
+			}
+			repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+			err := repo.UpdateAlbumByID(context.Background(), 1, models.AlbumRequest{Group: "Muse", Title: "The Resistance"})
+
+			events := fake.log()
+			assert.Contains(t, events[1], "FOR UPDATE", "album must be locked before the group check")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.NotContains(t, strings.Join(events, "\n"), "UPDATE albums")
+				assert.Equal(t, "rollback", events[len(events)-1])
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, strings.Join(events, "\n"), "UPDATE albums")
+			assert.Equal(t, "commit", events[len(events)-1])
+		})
+	}
+}
+
+func TestCreateAlbum_ReturnsStoredGroupName(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	_, err := repo.CreateAlbum(context.Background(), models.AlbumRequest{Group: "MUSE", Title: "The Resistance"})
+	require.NoError(t, err)
+
+	var insert string
+	for _, event := range fake.log() {
+		if strings.Contains(event, "INSERT INTO albums") {
+			insert = event
+		}
+	}
+	assert.Contains(t, insert, "g.name AS group_name")
+	assert.Contains(t, insert, "INNER JOIN groups g ON g.id = ins.group_id")
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/pkg/errors"
+)
+
+// execQueryRower *sql.DB, *sql.Tx или *sqlx.Tx
+type execQueryRower interface {
+	queryRower
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// saveSongArtists заменяет участников песни. nil оставляет участников без изменений, пустой список удаляет их.
+// Группа песни (groupID) уже основной исполнитель и отдельно не сохраняется, неизвестные участники создаются как группы.
+func saveSongArtists(ctx context.Context, q execQueryRower, songID, groupID uint, credits []models.ArtistCredit) error {
+	if credits == nil {
+		return nil
+	}
+
+	if _, err := q.ExecContext(ctx, `DELETE FROM song_artists WHERE song_id = $1`, songID); err != nil {
+		return errors.Wrap(err, "delete song artists")
+	}
+
+	query := `INSERT INTO song_artists (song_id, group_id, role, position, created_at)
+              VALUES ($1, $2, $3, $4, NOW())
+              ON CONFLICT DO NOTHING`
+	for i, credit := range credits {
+		artistID, err := getOrCreateGroup(ctx, q, credit.Name)
+		if err != nil {
+			return errors.Wrap(err, "get or create artist")
+		}
+		if artistID == groupID && credit.Role == models.ArtistRolePrimary {
+			continue
+		}
+
+		if _, err = q.ExecContext(ctx, query, songID, artistID, credit.Role, i); err != nil {
+			return errors.Wrap(err, "insert song artist")
+		}
+	}
+	return nil
+}
+
+// creditedArtistCondition условие фильтра по группе: совпадает группа песни или любой ее участник,
+// в том числе по другому написанию имени. param - номер параметра с шаблоном ILIKE.
+func creditedArtistCondition(param int) string {
+	p := "$" + strconv.Itoa(param)
+	return `(` + groupNameCondition(param) + ` OR EXISTS (
+                SELECT 1 FROM song_artists sa
+                INNER JOIN groups cg ON sa.group_id = cg.id
+                WHERE sa.song_id = s.id
+                  AND (cg.name ILIKE ` + p + ` OR EXISTS (SELECT 1 FROM group_aliases cga WHERE cga.group_id = cg.id AND cga.alias ILIKE ` + p + `))))`
+}
+
+// loadSongArtists участники песни: группа песни первой, затем остальные в порядке из запроса
+func (r lyricsRepo) loadSongArtists(ctx context.Context, song *models.Song) error {
+	song.Artists = []models.SongArtistInfo{{GroupID: song.GroupID, Name: song.GroupName, Role: models.ArtistRolePrimary}}
+
+	var credits []models.SongArtistInfo
+	query := `SELECT sa.group_id, g.name, sa.role
+              FROM song_artists sa
+              INNER JOIN groups g ON sa.group_id = g.id
+              WHERE sa.song_id = $1
+              ORDER BY sa.position, sa.role`
+	if err := r.db.SelectContext(ctx, &credits, query, song.ID); err != nil {
+		return errors.Wrap(err, "lyricsRepo.loadSongArtists.SelectContext")
+	}
+
+	song.Artists = append(song.Artists, credits...)
+	return nil
+}
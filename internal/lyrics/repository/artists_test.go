@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateTrackByID_RollsBackWhenArtistsFail(t *testing.T) {
+	db, fake := newFakeDB(t, func(query string) error {
+		if strings.Contains(query, "INSERT INTO song_artists") {
+			return errors.New("insert failed")
+		}
+		return nil
+	})
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	group, song, text := "Muse", "Uprising", "new text"
+	err := repo.UpdateTrackByID(context.Background(), models.UpdateTrackRequest{
+		ID:        1,
+		GroupName: &group,
+		SongName:  &song,
+		Text:      &text,
+		Artists:   []models.ArtistCredit{{Name: "Guest", Role: models.ArtistRoleFeatured}},
+	})
+	require.Error(t, err)
+
+	events := fake.log()
+	require.NotEmpty(t, events)
+	assert.Equal(t, "begin", events[0])
+	assert.Equal(t, "rollback", events[len(events)-1])
+	assert.NotContains(t, events, "commit")
+
+	var updatedSong, deletedArtists bool
+	for _, event := range events[1 : len(events)-1] {
+		assert.True(t, strings.HasPrefix(event, "tx: "), "statement outside transaction: %s", event)
+		updatedSong = updatedSong || strings.Contains(event, "UPDATE songs")
+		deletedArtists = deletedArtists || strings.Contains(event, "DELETE FROM song_artists")
+	}
+	assert.True(t, updatedSong)
+	assert.True(t, deletedArtists)
+}
+
+func TestUpdateTrackByID_CommitsSongAndArtistsTogether(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	group, song := "Muse", "Uprising"
+	err := repo.UpdateTrackByID(context.Background(), models.UpdateTrackRequest{
+		ID:        1,
+		GroupName: &group,
+		SongName:  &song,
+		Artists:   []models.ArtistCredit{{Name: "Guest", Role: models.ArtistRoleFeatured}},
+	})
+	require.NoError(t, err)
+
+	events := fake.log()
+	assert.Equal(t, "begin", events[0])
+	assert.Equal(t, "commit", events[len(events)-1])
+	for _, event := range events[1 : len(events)-1] {
+		assert.True(t, strings.HasPrefix(event, "tx: "), "statement outside transaction: %s", event)
+	}
+}
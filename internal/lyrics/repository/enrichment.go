@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// enrichmentJobColumns колонки задачи обогащения для RETURNING и SELECT
+const enrichmentJobColumns = `id, song_id, status, attempts, error, started_at, finished_at, created_at, updated_at`
+
+// CreatePendingSong создает песню в статусе pending и ставит задачу на загрузку ее данных.
+// Уже существующая песня группы не меняется и задача для нее не ставится: возвращается false
+// и задача, в которой заполнен только SongID.
+func (r lyricsRepo) CreatePendingSong(ctx context.Context, songRequest models.SongRequest) (models.EnrichmentJob, bool, error) {
+	r.logger.Debugf("in repo CreatePendingSong() song: %+v", songRequest)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.EnrichmentJob{}, false, errors.Wrap(err, "lyricsRepo.CreatePendingSong.BeginTx")
+	}
+	defer tx.Rollback()
+
+	groupID, err := getOrCreateGroup(ctx, tx, songRequest.Group)
+	if err != nil {
+		r.logger.Errorf("error getting/creating group: %v", err)
+		return models.EnrichmentJob{}, false, errors.Wrap(err, "lyricsRepo.CreatePendingSong.QueryGroup")
+	}
+
+	// Песню, которая уже есть у группы, оставляем как есть
+	var songID uint
+	queryInsert := `
+        INSERT INTO songs (group_id, song_name, song_key, text, status, created_at, updated_at)
+        VALUES ($1, $2, $3, '', $4, NOW(), NOW())
+        ON CONFLICT (group_id, song_key) DO NOTHING
+        RETURNING id
+    `
+	err = tx.GetContext(ctx, &songID, queryInsert, groupID, utils.NormalizeName(songRequest.Song), utils.NameKey(songRequest.Song), models.SongStatusPending)
+	if errors.Is(err, sql.ErrNoRows) {
+		if songID, err = getSongIDByKey(ctx, tx, groupID, songRequest.Song); err != nil {
+			return models.EnrichmentJob{}, false, errors.Wrap(err, "lyricsRepo.CreatePendingSong.SelectSong")
+		}
+		r.logger.Debugf("song %d already exists, skipping", songID)
+		return models.EnrichmentJob{SongID: songID}, false, nil
+	}
+	if err != nil {
+		r.logger.Errorf("error inserting pending song: %v", err)
+		return models.EnrichmentJob{}, false, errors.Wrap(err, "lyricsRepo.CreatePendingSong.InsertSong")
+	}
+
+	if err = saveSongArtists(ctx, tx, songID, groupID, songRequest.Artists); err != nil {
+		return models.EnrichmentJob{}, false, errors.Wrap(err, "lyricsRepo.CreatePendingSong.SaveArtists")
+	}
+
+	job, err := enqueueEnrichmentJob(ctx, tx, songID)
+	if err != nil {
+		return models.EnrichmentJob{}, false, errors.Wrap(err, "lyricsRepo.CreatePendingSong.InsertJob")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.EnrichmentJob{}, false, errors.Wrap(err, "lyricsRepo.CreatePendingSong.Commit")
+	}
+
+	return job, true, nil
+}
+
+// enqueueEnrichmentJob ставит задачу обогащения песни в очередь
+func enqueueEnrichmentJob(ctx context.Context, tx *sqlx.Tx, songID uint) (models.EnrichmentJob, error) {
+	var job models.EnrichmentJob
+	query := `INSERT INTO enrichment_jobs (song_id, status, attempts, created_at, updated_at)
+              VALUES ($1, $2, 0, NOW(), NOW())
+              RETURNING ` + enrichmentJobColumns
+	err := tx.GetContext(ctx, &job, query, songID, models.JobStatusQueued)
+	return job, err
+}
+
+// enrichmentAttemptsExceeded причина отказа от задачи, которая maxAttempts раз зависла у воркера
+const enrichmentAttemptsExceeded = "enrichment attempts exceeded"
+
+// ClaimEnrichmentJob забирает из очереди самую старую задачу и помечает ее running.
+// Задача, которая висит в running дольше staleAfter (воркер упал), забирается повторно, пока попыток меньше maxAttempts;
+// после этого задача и песня помечаются failed, чтобы задача, роняющая воркер, не возвращалась бесконечно.
+// FOR UPDATE SKIP LOCKED не дает двум воркерам взять одну задачу. Пустая очередь - sql.ErrNoRows.
+func (r lyricsRepo) ClaimEnrichmentJob(ctx context.Context, staleAfter time.Duration, maxAttempts int) (models.EnrichmentJob, error) {
+	staleBefore := time.Now().Add(-staleAfter)
+
+	queryExpire := `
+        WITH expired AS (
+            UPDATE enrichment_jobs
+            SET status = $1, error = $2, finished_at = NOW(), updated_at = NOW()
+            WHERE status = $3 AND started_at < $4 AND attempts >= $5
+            RETURNING song_id
+        )
+        UPDATE songs
+        SET status = $6, enrich_error = $2, updated_at = NOW()
+        WHERE id IN (SELECT song_id FROM expired) AND status = $7`
+	result, err := r.db.ExecContext(ctx, queryExpire, models.JobStatusFailed, enrichmentAttemptsExceeded, models.JobStatusRunning, staleBefore, maxAttempts,
+		models.SongStatusFailed, models.SongStatusPending)
+	if err != nil {
+		return models.EnrichmentJob{}, errors.Wrap(err, "lyricsRepo.ClaimEnrichmentJob.Expire")
+	}
+	if expired, err := result.RowsAffected(); err == nil && expired > 0 {
+		r.logger.Warnf("%d songs failed: enrichment job hung %d times", expired, maxAttempts)
+	}
+
+	var job models.EnrichmentJob
+	query := `
+        UPDATE enrichment_jobs
+        SET status = $1, attempts = attempts + 1, started_at = NOW(), updated_at = NOW()
+        WHERE id = (
+            SELECT id FROM enrichment_jobs
+            WHERE status = $2 OR (status = $1 AND started_at < $3 AND attempts < $4)
+            ORDER BY id
+            FOR UPDATE SKIP LOCKED
+            LIMIT 1
+        )
+        RETURNING ` + enrichmentJobColumns
+	err = r.db.GetContext(ctx, &job, query, models.JobStatusRunning, models.JobStatusQueued, staleBefore, maxAttempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EnrichmentJob{}, sql.ErrNoRows
+		}
+		return models.EnrichmentJob{}, errors.Wrap(err, "lyricsRepo.ClaimEnrichmentJob")
+	}
+
+	return job, nil
+}
+
+// CompleteEnrichmentJob сохраняет загруженные данные песни и закрывает задачу.
+// Заполняются только пустые поля: данные, которые редактор сохранил, пока песня ждала в очереди, не перезаписываются.
+// Если задачу уже забрал другой воркер, ничего не сохраняется и возвращается ErrEnrichmentJobLost.
+func (r lyricsRepo) CompleteEnrichmentJob(ctx context.Context, job models.EnrichmentJob, songDetail models.SongDetail) error {
+	r.logger.Debugf("in repo CompleteEnrichmentJob() job: %d, song: %d", job.ID, job.SongID)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.CompleteEnrichmentJob.BeginTx")
+	}
+	defer tx.Rollback()
+
+	// Источник может вернуть дату в неизвестном формате, такую дату не сохраняем
+	releaseDate, err := releaseDateArg(songDetail.ReleaseDate)
+	if err != nil {
+		r.logger.Warnf("skipping release date of song %d: %v", job.SongID, err)
+	}
+
+	if err = finishEnrichmentJob(ctx, tx, job, models.JobStatusDone, nil); err != nil {
+		return errors.Wrap(err, "lyricsRepo.CompleteEnrichmentJob.UpdateJob")
+	}
+
+	// Язык определяется заново, только если текст заполняется сейчас
+	querySong := `UPDATE songs
+                  SET release_date = COALESCE(release_date, $1),
+                      text = CASE WHEN text = '' THEN $2 ELSE text END,
+                      link = COALESCE(NULLIF(link, ''), NULLIF($3, '')),
+                      source = COALESCE(NULLIF(source, ''), NULLIF($4, '')),
+                      language = CASE WHEN text <> '' OR language_manual THEN language ELSE $7 END,
+                      language_confidence = CASE WHEN text <> '' OR language_manual THEN language_confidence ELSE $8 END,
+                      status = $5, enrich_error = NULL, updated_at = NOW()
+                  WHERE id = $6`
+	_, err = tx.ExecContext(ctx, querySong, releaseDate, songDetail.Text, songDetail.Link, songDetail.Source, models.SongStatusEnriched, job.SongID,
+		songDetail.Language.Language, songDetail.Language.Confidence)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.CompleteEnrichmentJob.UpdateSong")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "lyricsRepo.CompleteEnrichmentJob.Commit")
+	}
+
+	return nil
+}
+
+// FailEnrichmentJob помечает песню и задачу как failed и сохраняет причину.
+// Если задачу уже забрал другой воркер, ничего не сохраняется и возвращается ErrEnrichmentJobLost.
+func (r lyricsRepo) FailEnrichmentJob(ctx context.Context, job models.EnrichmentJob, reason string) error {
+	r.logger.Debugf("in repo FailEnrichmentJob() job: %d, song: %d, reason: %s", job.ID, job.SongID, reason)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.FailEnrichmentJob.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if err = finishEnrichmentJob(ctx, tx, job, models.JobStatusFailed, &reason); err != nil {
+		return errors.Wrap(err, "lyricsRepo.FailEnrichmentJob.UpdateJob")
+	}
+
+	querySong := `UPDATE songs SET status = $1, enrich_error = $2, updated_at = NOW() WHERE id = $3`
+	if _, err = tx.ExecContext(ctx, querySong, models.SongStatusFailed, reason, job.SongID); err != nil {
+		return errors.Wrap(err, "lyricsRepo.FailEnrichmentJob.UpdateSong")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "lyricsRepo.FailEnrichmentJob.Commit")
+	}
+
+	return nil
+}
+
+// finishEnrichmentJob переводит задачу в конечный статус, если она все еще принадлежит вызывающему:
+// running с тем же номером попытки. Иначе задачу забрали как зависшую - ErrEnrichmentJobLost.
+func finishEnrichmentJob(ctx context.Context, tx *sqlx.Tx, job models.EnrichmentJob, status string, reason *string) error {
+	query := `UPDATE enrichment_jobs SET status = $1, error = $2, finished_at = NOW(), updated_at = NOW()
+              WHERE id = $3 AND status = $4 AND attempts = $5`
+	result, err := tx.ExecContext(ctx, query, status, reason, job.ID, models.JobStatusRunning, job.Attempts)
+	if err != nil {
+		return err
+	}
+
+	finished, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if finished == 0 {
+		return lyrics.ErrEnrichmentJobLost
+	}
+	return nil
+}
+
+// GetEnrichmentJob задача обогащения по ID
+func (r lyricsRepo) GetEnrichmentJob(ctx context.Context, id uint) (models.EnrichmentJob, error) {
+	var job models.EnrichmentJob
+	query := `SELECT ` + enrichmentJobColumns + ` FROM enrichment_jobs WHERE id = $1`
+	if err := r.db.GetContext(ctx, &job, query, id); err != nil {
+		return models.EnrichmentJob{}, fmt.Errorf("failed to fetch enrichment job: %w", err)
+	}
+	return job, nil
+}
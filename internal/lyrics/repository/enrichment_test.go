@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimEnrichmentJob_ExpiresJobsOverAttempts(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	_, err := repo.ClaimEnrichmentJob(context.Background(), time.Minute, 3)
+	require.NoError(t, err)
+
+	events := fake.log()
+	require.Len(t, events, 2)
+	assert.Contains(t, events[0], "WHERE status = $3 AND started_at < $4 AND attempts >= $5")
+	assert.Contains(t, events[0], "UPDATE songs")
+	assert.Contains(t, events[1], "(status = $1 AND started_at < $3 AND attempts < $4)")
+}
+
+func TestFinishEnrichmentJob_ReclaimedJob(t *testing.T) {
+	tests := []struct {
+		name   string
+		finish func(repo lyricsRepo, job models.EnrichmentJob) error
+	}{
+		{name: "complete", finish: func(repo lyricsRepo, job models.EnrichmentJob) error {
+			return repo.CompleteEnrichmentJob(context.Background(), job, models.SongDetail{Text: "late verse"})
+		}},
+		{name: "fail", finish: func(repo lyricsRepo, job models.EnrichmentJob) error {
+			return repo.FailEnrichmentJob(context.Background(), job, "timeout")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, nil)
+			// Задачу уже забрал другой воркер: условие на статус и попытку не находит строку
+			fake.affected = func(query string) int64 {
+				if strings.Contains(query, "UPDATE enrichment_jobs") {
+					return 0
+				}
+				return 1
+			}
+			repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+			err := tt.finish(repo, models.EnrichmentJob{ID: 1, SongID: 2, Attempts: 1})
+			assert.ErrorIs(t, err, lyrics.ErrEnrichmentJobLost)
+
+			events := fake.log()
+			assert.Equal(t, "rollback", events[len(events)-1])
+			assert.NotContains(t, events, "commit")
+			for _, event := range events {
+				assert.NotContains(t, event, "UPDATE songs", "song must not be written by a reclaimed job")
+			}
+		})
+	}
+}
+
+func TestCompleteEnrichmentJob_FillsOnlyEmptyFields(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	err := repo.CompleteEnrichmentJob(context.Background(), models.EnrichmentJob{ID: 1, SongID: 2, Attempts: 1}, models.SongDetail{Text: "verse"})
+	require.NoError(t, err)
+
+	events := fake.log()
+	assert.Equal(t, "commit", events[len(events)-1])
+
+	var update string
+	for _, event := range events {
+		if strings.Contains(event, "UPDATE songs") {
+			update = event
+		}
+	}
+	assert.Contains(t, update, "release_date = COALESCE(release_date, $1)")
+	assert.Contains(t, update, "text = CASE WHEN text = '' THEN $2 ELSE text END")
+	assert.Contains(t, update, "link = COALESCE(NULLIF(link, ''), NULLIF($3, ''))")
+}
+
+func TestCreatePendingSong_SkipsExistingSong(t *testing.T) {
+	tests := []struct {
+		name   string
+		exists bool
+	}{
+		{name: "new song", exists: false},
+		{name: "existing song", exists: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, nil)
+			fake.value = func(query string) (int64, bool) {
+				// Конфликт по song_key - INSERT ... DO NOTHING не возвращает строку
+				if strings.Contains(query, "INSERT INTO songs") {
+					return 7, !tt.exists
+				}
+				return 7, true
+			}
+			repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+			job, created, err := repo.CreatePendingSong(context.Background(), models.SongRequest{Group: "Muse", Song: "Uprising"})
+			require.NoError(t, err)
+			assert.Equal(t, !tt.exists, created)
+
+			events := fake.log()
+			log := strings.Join(events, "\n")
+			assert.Contains(t, log, "ON CONFLICT (group_id, song_key) DO NOTHING")
+			assert.NotContains(t, log, "UPDATE songs")
+			if tt.exists {
+				assert.Equal(t, uint(7), job.SongID)
+				assert.Zero(t, job.ID)
+				assert.Contains(t, log, "SELECT id FROM songs WHERE group_id = $1 AND song_key = $2")
+				assert.NotContains(t, log, "INSERT INTO enrichment_jobs")
+				assert.Equal(t, "rollback", events[len(events)-1])
+				return
+			}
+			assert.Equal(t, uint(7), job.ID)
+			assert.Contains(t, log, "INSERT INTO enrichment_jobs")
+			assert.Equal(t, "commit", events[len(events)-1])
+		})
+	}
+}
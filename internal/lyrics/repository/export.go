@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/pkg/errors"
+)
+
+// ExportLibrary читает библиотеку с фильтрами и сортировкой GetLibrary без пагинации.
+// Строки выбираются серверным курсором по fetchSize штук, поэтому весь каталог не держится в памяти;
+// fn вызывается для каждой песни, ошибка fn прерывает выгрузку.
+func (r lyricsRepo) ExportLibrary(ctx context.Context, filter models.LibraryFilter, fetchSize int, fn func(models.Song) error) error {
+	r.logger.Debugf("in repo ExportLibrary() filter: %+v", filter)
+
+	// Курсор живет только внутри транзакции; снимок REPEATABLE READ дает согласованную выгрузку
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.ExportLibrary.BeginTx")
+	}
+	defer tx.Rollback()
+
+	sort := normalizeLibrarySort(filter.Sort)
+	conditions, args := buildLibraryConditions(filter)
+	query := `DECLARE library_export NO SCROLL CURSOR FOR
+              SELECT ` + buildLibrarySelect(filter.Fields, sort) + `
+              FROM songs s
+              INNER JOIN groups g ON s.group_id = g.id
+              LEFT JOIN albums a ON s.album_id = a.id` + whereClause(conditions) + buildLibraryOrder(sort)
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "lyricsRepo.ExportLibrary.DeclareCursor")
+	}
+
+	fetchQuery := `FETCH FORWARD ` + strconv.Itoa(fetchSize) + ` FROM library_export`
+	for {
+		songs := []models.Song{}
+		if err := tx.SelectContext(ctx, &songs, fetchQuery); err != nil {
+			return errors.Wrap(err, "lyricsRepo.ExportLibrary.Fetch")
+		}
+
+		for _, song := range songs {
+			if err := fn(song); err != nil {
+				return err
+			}
+		}
+
+		// Пустая порция - курсор дочитан, даже если fetchSize задан некорректно
+		if len(songs) == 0 || len(songs) < fetchSize {
+			return nil
+		}
+	}
+}
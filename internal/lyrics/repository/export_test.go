@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportLibrary_StopsOnEmptyFetch(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	fake.value = func(query string) (int64, bool) {
+		return 0, false
+	}
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	// С fetchSize = 0 условие "порция меньше fetchSize" никогда не выполняется
+	err := repo.ExportLibrary(context.Background(), models.LibraryFilter{}, 0, func(models.Song) error {
+		t.Fatal("no rows expected")
+		return nil
+	})
+	require.NoError(t, err)
+
+	fetches := 0
+	for _, event := range fake.log() {
+		if strings.Contains(event, "FETCH FORWARD") {
+			fetches++
+		}
+	}
+	assert.Equal(t, 1, fetches)
+}
+
+func TestExportLibrary_FetchesUntilShortBatch(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	// fakeDriver отдает одну строку на запрос: порция из одной строки при fetchSize 2 - последняя
+	var ids []uint
+	err := repo.ExportLibrary(context.Background(), models.LibraryFilter{}, 2, func(song models.Song) error {
+		ids = append(ids, song.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1}, ids)
+
+	events := fake.log()
+	assert.Equal(t, "begin", events[0])
+	assert.Equal(t, "rollback", events[len(events)-1])
+	assert.Contains(t, events, "tx: FETCH FORWARD 2 FROM library_export")
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// fakeDriver драйвер database/sql для тестов репозитория без Postgres: записывает выполненные запросы,
+// ошибку запроса задает fail. Запрос возвращает одну строку со значением из value, без value - со значением 1;
+// value с ok = false - пустой результат. Exec затрагивает одну строку, если affected не задает другое число.
+type fakeDriver struct {
+	mu       sync.Mutex
+	events   []string
+	args     map[string][]driver.NamedValue
+	fail     func(query string) error
+	value    func(query string) (v int64, ok bool)
+	affected func(query string) int64
+}
+
+var (
+	fakeDriversMu sync.Mutex
+	fakeDrivers   = map[string]*fakeDriver{}
+)
+
+func init() {
+	sql.Register("fakepg", fakeConnector{})
+}
+
+type fakeConnector struct{}
+
+func (fakeConnector) Open(name string) (driver.Conn, error) {
+	fakeDriversMu.Lock()
+	defer fakeDriversMu.Unlock()
+	d, ok := fakeDrivers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown fake database %q", name)
+	}
+	return &fakeConn{driver: d}, nil
+}
+
+// newFakeDB открывает базу на fakeDriver, fail может быть nil
+func newFakeDB(t *testing.T, fail func(query string) error) (*sqlx.DB, *fakeDriver) {
+	d := &fakeDriver{fail: fail}
+
+	fakeDriversMu.Lock()
+	fakeDrivers[t.Name()] = d
+	fakeDriversMu.Unlock()
+
+	db, err := sql.Open("fakepg", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "postgres"), d
+}
+
+// log события в порядке выполнения: begin, commit, rollback и запросы с пометкой tx/db
+func (d *fakeDriver) log() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.events...)
+}
+
+// argsOf аргументы последнего выполнения запроса query, пробелы в запросе схлопываются как в log
+func (d *fakeDriver) argsOf(query string) []driver.NamedValue {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.args[strings.Join(strings.Fields(query), " ")]
+}
+
+func (d *fakeDriver) record(event string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = append(d.events, event)
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+	inTx   bool
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver does not prepare statements")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.inTx = true
+	c.driver.record("begin")
+	return fakeTx{conn: c}, nil
+}
+
+func (c *fakeConn) statement(query string, args []driver.NamedValue) error {
+	scope := "db"
+	if c.inTx {
+		scope = "tx"
+	}
+	collapsed := strings.Join(strings.Fields(query), " ")
+	c.driver.record(scope + ": " + collapsed)
+
+	c.driver.mu.Lock()
+	if c.driver.args == nil {
+		c.driver.args = map[string][]driver.NamedValue{}
+	}
+	c.driver.args[collapsed] = args
+	c.driver.mu.Unlock()
+
+	if c.driver.fail != nil {
+		return c.driver.fail(query)
+	}
+	return nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.statement(query, args); err != nil {
+		return nil, err
+	}
+	if c.driver.affected != nil {
+		return driver.RowsAffected(c.driver.affected(query)), nil
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.statement(query, args); err != nil {
+		return nil, err
+	}
+	if c.driver.value == nil {
+		return &fakeRows{value: 1}, nil
+	}
+	value, ok := c.driver.value(query)
+	return &fakeRows{value: value, done: !ok}, nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (tx fakeTx) Commit() error {
+	tx.conn.inTx = false
+	tx.conn.driver.record("commit")
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.conn.inTx = false
+	tx.conn.driver.record("rollback")
+	return nil
+}
+
+// fakeRows не больше одной строки с одной колонкой id
+type fakeRows struct {
+	value int64
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
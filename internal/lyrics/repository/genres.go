@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// genreInfoColumns колонки жанра с количеством песен, запрос должен содержать псевдоним ge (genres)
+const genreInfoColumns = `ge.id, ge.name, ge.parent_id, ge.created_at, ge.updated_at,
+                     (SELECT COUNT(*) FROM song_genres sg WHERE sg.genre_id = ge.id) AS song_count`
+
+// genreSubtree запрос ID жанров с заданными ключами и всех их поджанров, in - список параметров с ключами
+func genreSubtree(in string) string {
+	return `WITH RECURSIVE subtree AS (
+                SELECT id FROM genres WHERE name_key IN (` + in + `)
+                UNION
+                SELECT c.id FROM genres c INNER JOIN subtree ON c.parent_id = subtree.id
+            )
+            SELECT id FROM subtree`
+}
+
+// listPlaceholders добавляет значения в аргументы запроса и возвращает их параметры через запятую
+func listPlaceholders(args []interface{}, values []string) (string, []interface{}) {
+	params := make([]string, len(values))
+	for i, value := range values {
+		args = append(args, value)
+		params[i] = "$" + strconv.Itoa(len(args))
+	}
+	return strings.Join(params, ", "), args
+}
+
+// nameKeys ключи имен без пустых и повторяющихся
+func nameKeys(names []string) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		key := utils.NameKey(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// genreConditions условия фильтра по жанрам: жанр включает свои поджанры,
+// с match = all песня должна относиться к каждому из жанров, иначе - хотя бы к одному
+func genreConditions(genres []string, match string, args []interface{}) ([]string, []interface{}) {
+	keys := nameKeys(genres)
+	if len(keys) == 0 {
+		return nil, args
+	}
+
+	groups := [][]string{keys}
+	if match == models.MatchAll {
+		groups = groups[:0]
+		for _, key := range keys {
+			groups = append(groups, []string{key})
+		}
+	}
+
+	var conditions []string
+	for _, group := range groups {
+		var in string
+		in, args = listPlaceholders(args, group)
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM song_genres sg WHERE sg.song_id = s.id AND sg.genre_id IN (`+genreSubtree(in)+`))`)
+	}
+	return conditions, args
+}
+
+// genreFacet количество песен по жанрам, песня поджанра учитывается и в родительских жанрах
+func (r lyricsRepo) genreFacet(ctx context.Context, filter models.LibraryFilter) ([]models.FacetCount, error) {
+	facet := []models.FacetCount{}
+
+	filter.Genres = nil
+	conditions, args := buildLibraryConditions(filter)
+	query := `WITH RECURSIVE ancestors AS (
+                  SELECT id AS genre_id, id AS ancestor_id, parent_id FROM genres
+                  UNION
+                  SELECT an.genre_id, p.id, p.parent_id FROM ancestors an INNER JOIN genres p ON p.id = an.parent_id
+              )
+              SELECT ge.name AS value, COUNT(DISTINCT s.id) AS count
+              FROM songs s
+              INNER JOIN groups g ON s.group_id = g.id
+              LEFT JOIN albums a ON s.album_id = a.id
+              INNER JOIN song_genres sg ON sg.song_id = s.id
+              INNER JOIN ancestors an ON an.genre_id = sg.genre_id
+              INNER JOIN genres ge ON ge.id = an.ancestor_id` + whereClause(conditions) + `
+              GROUP BY ge.name
+              ORDER BY count DESC, value`
+	if err := r.db.SelectContext(ctx, &facet, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to fetch genre facet: %w", err)
+	}
+	return facet, nil
+}
+
+// GetGenres все жанры с количеством песен, по алфавиту
+func (r lyricsRepo) GetGenres(ctx context.Context) ([]models.GenreInfo, error) {
+	genres := []models.GenreInfo{}
+	query := `SELECT ` + genreInfoColumns + ` FROM genres ge ORDER BY ge.name, ge.id`
+	if err := r.db.SelectContext(ctx, &genres, query); err != nil {
+		return nil, fmt.Errorf("failed to fetch genres: %w", err)
+	}
+	return genres, nil
+}
+
+// GetGenreByID жанр с количеством песен, без поджанров
+func (r lyricsRepo) GetGenreByID(ctx context.Context, id uint) (models.GenreInfo, error) {
+	var genre models.GenreInfo
+	query := `SELECT ` + genreInfoColumns + ` FROM genres ge WHERE ge.id = $1`
+	if err := r.db.GetContext(ctx, &genre, query, id); err != nil {
+		return models.GenreInfo{}, fmt.Errorf("failed to fetch genre: %w", err)
+	}
+	genre.Children = []models.GenreInfo{}
+	return genre, nil
+}
+
+// checkGenreParent проверяет, что родитель существует и не является жанром id или его поджанром
+func checkGenreParent(ctx context.Context, tx *sqlx.Tx, id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+
+	var exists bool
+	if err := tx.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM genres WHERE id = $1)`, *parentID); err != nil {
+		return errors.Wrap(err, "select parent genre")
+	}
+	if !exists {
+		return errors.Wrap(lyrics.ErrInvalidGenreParent, "parent genre not found")
+	}
+	if id == 0 {
+		return nil
+	}
+
+	var cycle bool
+	query := `WITH RECURSIVE subtree AS (
+                  SELECT id FROM genres WHERE id = $1
+                  UNION
+                  SELECT c.id FROM genres c INNER JOIN subtree ON c.parent_id = subtree.id
+              )
+              SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)`
+	if err := tx.GetContext(ctx, &cycle, query, id, *parentID); err != nil {
+		return errors.Wrap(err, "select subgenres")
+	}
+	if cycle {
+		return errors.Wrap(lyrics.ErrInvalidGenreParent, "parent genre is the genre itself or its subgenre")
+	}
+	return nil
+}
+
+// CreateGenre создает жанр, занятое имя - ErrConflict
+func (r lyricsRepo) CreateGenre(ctx context.Context, genre models.GenreRequest) (models.GenreInfo, error) {
+	r.logger.Debugf("in repo CreateGenre() genre: %+v", genre)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.GenreInfo{}, errors.Wrap(err, "lyricsRepo.CreateGenre.BeginTx")
+	}
+	defer tx.Rollback()
+
+	if err = checkGenreParent(ctx, tx, 0, genre.ParentID); err != nil {
+		return models.GenreInfo{}, err
+	}
+
+	var id uint
+	query := `INSERT INTO genres (name, name_key, parent_id, created_at, updated_at)
+              VALUES ($1, $2, $3, NOW(), NOW())
+              RETURNING id`
+	if err = tx.GetContext(ctx, &id, query, utils.NormalizeName(genre.Name), utils.NameKey(genre.Name), genre.ParentID); err != nil {
+		if isUniqueViolation(err) {
+			return models.GenreInfo{}, errors.Wrap(lyrics.ErrConflict, "genre already exists")
+		}
+		return models.GenreInfo{}, errors.Wrap(err, "lyricsRepo.CreateGenre.Insert")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.GenreInfo{}, errors.Wrap(err, "lyricsRepo.CreateGenre.Commit")
+	}
+
+	return r.GetGenreByID(ctx, id)
+}
+
+// UpdateGenreByID переименовывает жанр и переносит его под другого родителя, без родителя жанр становится верхнего уровня
+func (r lyricsRepo) UpdateGenreByID(ctx context.Context, id uint, genre models.GenreRequest) (models.GenreInfo, error) {
+	r.logger.Debugf("in repo UpdateGenreByID() id: %d, genre: %+v", id, genre)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.GenreInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGenreByID.BeginTx")
+	}
+	defer tx.Rollback()
+
+	// Изменения дерева выполняются по очереди, иначе два встречных переноса могут замкнуть его в цикл
+	if _, err = tx.ExecContext(ctx, `LOCK TABLE genres IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return models.GenreInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGenreByID.LockGenres")
+	}
+
+	var genreID uint
+	if err = tx.GetContext(ctx, &genreID, `SELECT id FROM genres WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.GenreInfo{}, errors.Wrap(sql.ErrNoRows, "genre not found")
+		}
+		return models.GenreInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGenreByID.LockGenre")
+	}
+
+	if err = checkGenreParent(ctx, tx, id, genre.ParentID); err != nil {
+		return models.GenreInfo{}, err
+	}
+
+	query := `UPDATE genres SET name = $1, name_key = $2, parent_id = $3, updated_at = NOW() WHERE id = $4`
+	if _, err = tx.ExecContext(ctx, query, utils.NormalizeName(genre.Name), utils.NameKey(genre.Name), genre.ParentID, id); err != nil {
+		if isUniqueViolation(err) {
+			return models.GenreInfo{}, errors.Wrap(lyrics.ErrConflict, "genre name is already taken")
+		}
+		return models.GenreInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGenreByID.Update")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.GenreInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGenreByID.Commit")
+	}
+
+	return r.GetGenreByID(ctx, id)
+}
+
+// DeleteGenreByID удаляет жанр, его поджанры переходят к родителю удаленного жанра
+func (r lyricsRepo) DeleteGenreByID(ctx context.Context, id uint) error {
+	r.logger.Debugf("in repo DeleteGenreByID() id: %d", id)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteGenreByID.BeginTx")
+	}
+	defer tx.Rollback()
+
+	var parentID *uint
+	if err = tx.GetContext(ctx, &parentID, `SELECT parent_id FROM genres WHERE id = $1 FOR UPDATE`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrap(sql.ErrNoRows, "genre not found")
+		}
+		return errors.Wrap(err, "lyricsRepo.DeleteGenreByID.LockGenre")
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE genres SET parent_id = $1, updated_at = NOW() WHERE parent_id = $2`, parentID, id); err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteGenreByID.MoveSubgenres")
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM genres WHERE id = $1`, id); err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteGenreByID.Delete")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteGenreByID.Commit")
+	}
+	return nil
+}
+
+// firstMissingID первый ID из ids, которого нет в таблице, 0 - все есть
+func firstMissingID(ctx context.Context, q sqlx.QueryerContext, table string, ids []uint) (uint, error) {
+	query, args, err := sqlx.In(`SELECT id FROM `+table+` WHERE id IN (?)`, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	var found []uint
+	if err = sqlx.SelectContext(ctx, q, &found, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+		return 0, err
+	}
+
+	existing := make(map[uint]bool, len(found))
+	for _, id := range found {
+		existing[id] = true
+	}
+	for _, id := range ids {
+		if !existing[id] {
+			return id, nil
+		}
+	}
+	return 0, nil
+}
+
+// AttachGenres добавляет жанры песням, уже добавленные пропускаются. Возвращает количество новых связей.
+func (r lyricsRepo) AttachGenres(ctx context.Context, songIDs, genreIDs []uint) (int, error) {
+	r.logger.Debugf("in repo AttachGenres() songs: %v, genres: %v", songIDs, genreIDs)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachGenres.BeginTx")
+	}
+	defer tx.Rollback()
+
+	missing, err := firstMissingID(ctx, tx, "songs", songIDs)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachGenres.CheckSongs")
+	}
+	if missing != 0 {
+		return 0, errors.Wrapf(sql.ErrNoRows, "song %d not found", missing)
+	}
+
+	if missing, err = firstMissingID(ctx, tx, "genres", genreIDs); err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachGenres.CheckGenres")
+	}
+	if missing != 0 {
+		return 0, errors.Wrapf(sql.ErrNoRows, "genre %d not found", missing)
+	}
+
+	query, args, err := sqlx.In(`INSERT INTO song_genres (song_id, genre_id)
+              SELECT s.id, ge.id FROM songs s CROSS JOIN genres ge
+              WHERE s.id IN (?) AND ge.id IN (?)
+              ON CONFLICT DO NOTHING`, songIDs, genreIDs)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachGenres.In")
+	}
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachGenres.Insert")
+	}
+	attached, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachGenres.RowsAffected")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachGenres.Commit")
+	}
+	return int(attached), nil
+}
+
+// DetachGenres убирает жанры у песен, возвращает количество удаленных связей
+func (r lyricsRepo) DetachGenres(ctx context.Context, songIDs, genreIDs []uint) (int, error) {
+	r.logger.Debugf("in repo DetachGenres() songs: %v, genres: %v", songIDs, genreIDs)
+
+	query, args, err := sqlx.In(`DELETE FROM song_genres WHERE song_id IN (?) AND genre_id IN (?)`, songIDs, genreIDs)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.DetachGenres.In")
+	}
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.DetachGenres.Delete")
+	}
+	detached, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.DetachGenres.RowsAffected")
+	}
+	return int(detached), nil
+}
+
+// loadSongGenres названия жанров песни
+func (r lyricsRepo) loadSongGenres(ctx context.Context, song *models.Song) error {
+	song.Genres = []string{}
+	query := `SELECT ge.name FROM song_genres sg
+              INNER JOIN genres ge ON sg.genre_id = ge.id
+              WHERE sg.song_id = $1
+              ORDER BY ge.name`
+	if err := r.db.SelectContext(ctx, &song.Genres, query, song.ID); err != nil {
+		return errors.Wrap(err, "lyricsRepo.loadSongGenres.SelectContext")
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// groupNameCondition условие поиска группы g по имени или другому написанию, param - номер параметра с шаблоном ILIKE
+func groupNameCondition(param int) string {
+	p := "$" + strconv.Itoa(param)
+	return "(g.name ILIKE " + p + " OR EXISTS (SELECT 1 FROM group_aliases ga WHERE ga.group_id = g.id AND ga.alias ILIKE " + p + "))"
+}
+
+// loadGroupAliases заполняет другие написания имен групп одним запросом
+func (r lyricsRepo) loadGroupAliases(ctx context.Context, groups []models.GroupInfo) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(groups))
+	byID := make(map[uint]int, len(groups))
+	for i := range groups {
+		ids[i] = groups[i].ID
+		byID[groups[i].ID] = i
+		groups[i].Aliases = []string{}
+	}
+
+	query, args, err := sqlx.In(`SELECT group_id, alias FROM group_aliases WHERE group_id IN (?) ORDER BY alias`, ids)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.loadGroupAliases.In")
+	}
+
+	var aliases []models.GroupAlias
+	if err = r.db.SelectContext(ctx, &aliases, r.db.Rebind(query), args...); err != nil {
+		return errors.Wrap(err, "lyricsRepo.loadGroupAliases.SelectContext")
+	}
+
+	for _, alias := range aliases {
+		i := byID[alias.GroupID]
+		groups[i].Aliases = append(groups[i].Aliases, alias.Alias)
+	}
+	return nil
+}
+
+// AddGroupAlias добавляет другое написание имени группы.
+// Написание, совпадающее после нормализации с именем любой группы или принадлежащее другой группе, - ErrConflict.
+func (r lyricsRepo) AddGroupAlias(ctx context.Context, groupID uint, alias string) (models.GroupInfo, error) {
+	r.logger.Debugf("in repo AddGroupAlias() group: %d, alias: %s", groupID, alias)
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM groups WHERE id = $1)`, groupID); err != nil {
+		return models.GroupInfo{}, errors.Wrap(err, "lyricsRepo.AddGroupAlias.SelectGroup")
+	}
+	if !exists {
+		return models.GroupInfo{}, errors.Wrap(sql.ErrNoRows, "group not found")
+	}
+
+	var owner uint
+	query := `
+        WITH ins AS (
+            INSERT INTO group_aliases (group_id, alias, alias_key, created_at)
+            SELECT $1, $2, $3, NOW()
+            WHERE NOT EXISTS (SELECT 1 FROM groups WHERE name_key = $3)
+            ON CONFLICT DO NOTHING
+            RETURNING group_id
+        )
+        SELECT group_id FROM ins
+        UNION ALL
+        SELECT group_id FROM group_aliases WHERE alias_key = $3
+        LIMIT 1
+    `
+	err := r.db.GetContext(ctx, &owner, query, groupID, utils.NormalizeName(alias), utils.NameKey(alias))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.GroupInfo{}, errors.Wrap(lyrics.ErrConflict, "alias is a group name")
+	case err != nil:
+		return models.GroupInfo{}, errors.Wrap(err, "lyricsRepo.AddGroupAlias.GetContext")
+	case owner != groupID:
+		return models.GroupInfo{}, errors.Wrap(lyrics.ErrConflict, "alias belongs to another group")
+	}
+
+	return r.GetGroupByID(ctx, groupID)
+}
+
+// DeleteGroupAlias удаляет другое написание имени группы
+func (r lyricsRepo) DeleteGroupAlias(ctx context.Context, groupID uint, alias string) error {
+	r.logger.Debugf("in repo DeleteGroupAlias() group: %d, alias: %s", groupID, alias)
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM group_aliases WHERE group_id = $1 AND alias_key = $2`, groupID, utils.NameKey(alias))
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteGroupAlias.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteGroupAlias.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "alias not found")
+	}
+	return nil
+}
+
+// mergeSongConflict песня с одинаковым названием в обеих группах
+type mergeSongConflict struct {
+	SongName      string    `db:"song_name"`
+	SourceID      uint      `db:"source_id"`
+	SourceUpdated time.Time `db:"source_updated"`
+	TargetID      uint      `db:"target_id"`
+	TargetUpdated time.Time `db:"target_updated"`
+}
+
+// keep выбирает по стратегии, какая из песен останется
+func (c mergeSongConflict) keep(strategy string) (kept, removed uint) {
+	switch {
+	case strategy == models.MergeKeepSource,
+		strategy == models.MergeKeepNewest && c.SourceUpdated.After(c.TargetUpdated):
+		return c.SourceID, c.TargetID
+	default:
+		return c.TargetID, c.SourceID
+	}
+}
+
+// MergeGroups переносит песни и альбомы группы sourceID в группу targetID одной транзакцией.
+// Из песен с одинаковым названием остается одна по стратегии, переводы удаляемой песни на недостающие языки переносятся.
+// Альбомы с одинаковым названием объединяются, занятые номера треков сбрасываются.
+// Имя удаленной группы становится другим написанием группы targetID.
+func (r lyricsRepo) MergeGroups(ctx context.Context, sourceID, targetID uint, strategy string) (models.GroupMergeResult, error) {
+	r.logger.Debugf("in repo MergeGroups() source: %d, target: %d, strategy: %s", sourceID, targetID, strategy)
+
+	result := models.GroupMergeResult{MovedSongs: []uint{}, Conflicts: []models.GroupMergeConflict{}}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.BeginTx")
+	}
+	defer tx.Rollback()
+
+	// Блокируем обе группы в порядке id, чтобы встречные слияния не взаимоблокировались
+	var locked []models.Group
+	queryLock := `SELECT id, name FROM groups WHERE id IN ($1, $2) ORDER BY id FOR UPDATE`
+	if err = tx.SelectContext(ctx, &locked, queryLock, sourceID, targetID); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.LockGroups")
+	}
+	if len(locked) != 2 {
+		return result, errors.Wrap(sql.ErrNoRows, "group not found")
+	}
+	source := locked[0]
+	if source.ID != sourceID {
+		source = locked[1]
+	}
+
+	var conflicts []mergeSongConflict
+	queryConflicts := `
+        SELECT s.song_name, s.id AS source_id, s.updated_at AS source_updated,
+               t.id AS target_id, t.updated_at AS target_updated
+        FROM songs s
+        INNER JOIN songs t ON t.group_id = $2 AND t.song_key = s.song_key
+        WHERE s.group_id = $1
+        ORDER BY s.song_name
+    `
+	if err = tx.SelectContext(ctx, &conflicts, queryConflicts, sourceID, targetID); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.SelectConflicts")
+	}
+
+	queryTranslations := `
+        UPDATE song_translations SET song_id = $1, updated_at = NOW()
+        WHERE song_id = $2
+          AND language NOT IN (SELECT language FROM song_translations WHERE song_id = $1)
+    `
+	for _, conflict := range conflicts {
+		kept, removed := conflict.keep(strategy)
+
+		moved, err := tx.ExecContext(ctx, queryTranslations, kept, removed)
+		if err != nil {
+			return result, errors.Wrap(err, "lyricsRepo.MergeGroups.MoveTranslations")
+		}
+		movedTranslations, err := moved.RowsAffected()
+		if err != nil {
+			return result, errors.Wrap(err, "lyricsRepo.MergeGroups.RowsAffected")
+		}
+
+		// Дубликат удаляется до переноса песен, иначе перенос нарушит idx_group_song
+		if _, err = tx.ExecContext(ctx, `DELETE FROM songs WHERE id = $1`, removed); err != nil {
+			return result, errors.Wrap(err, "lyricsRepo.MergeGroups.DeleteDuplicate")
+		}
+
+		result.Conflicts = append(result.Conflicts, models.GroupMergeConflict{
+			Song:              conflict.SongName,
+			KeptSongID:        kept,
+			RemovedSongID:     removed,
+			Strategy:          strategy,
+			MovedTranslations: int(movedTranslations),
+		})
+	}
+
+	var albumPairs []struct {
+		SourceID uint `db:"source_id"`
+		TargetID uint `db:"target_id"`
+	}
+	queryAlbums := `
+        SELECT sa.id AS source_id, ta.id AS target_id
+        FROM albums sa
+        INNER JOIN albums ta ON ta.group_id = $2 AND ta.title = sa.title
+        WHERE sa.group_id = $1
+    `
+	if err = tx.SelectContext(ctx, &albumPairs, queryAlbums, sourceID, targetID); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.SelectAlbums")
+	}
+
+	// Песни альбома переходят в одноименный альбом группы, номер трека сбрасывается, если он уже занят
+	queryMoveTracks := `
+        UPDATE songs s
+        SET album_id = $1,
+            track_number = CASE
+                WHEN EXISTS (SELECT 1 FROM songs t WHERE t.album_id = $1 AND t.track_number = s.track_number)
+                THEN NULL ELSE s.track_number END
+        WHERE s.album_id = $2
+    `
+	for _, pair := range albumPairs {
+		if _, err = tx.ExecContext(ctx, queryMoveTracks, pair.TargetID, pair.SourceID); err != nil {
+			return result, errors.Wrap(err, "lyricsRepo.MergeGroups.MoveTracks")
+		}
+		queryReleaseDate := `UPDATE albums SET release_date = COALESCE(release_date, (SELECT release_date FROM albums WHERE id = $2)), updated_at = NOW()
+                             WHERE id = $1`
+		if _, err = tx.ExecContext(ctx, queryReleaseDate, pair.TargetID, pair.SourceID); err != nil {
+			return result, errors.Wrap(err, "lyricsRepo.MergeGroups.MergeAlbum")
+		}
+		if _, err = tx.ExecContext(ctx, `DELETE FROM albums WHERE id = $1`, pair.SourceID); err != nil {
+			return result, errors.Wrap(err, "lyricsRepo.MergeGroups.DeleteAlbum")
+		}
+	}
+	result.MergedAlbums = len(albumPairs)
+
+	albums, err := tx.ExecContext(ctx, `UPDATE albums SET group_id = $2, updated_at = NOW() WHERE group_id = $1`, sourceID, targetID)
+	if err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.MoveAlbums")
+	}
+	movedAlbums, err := albums.RowsAffected()
+	if err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.RowsAffected")
+	}
+	result.MovedAlbums = int(movedAlbums)
+
+	queryMoveSongs := `UPDATE songs SET group_id = $2, updated_at = NOW() WHERE group_id = $1 RETURNING id`
+	if err = tx.SelectContext(ctx, &result.MovedSongs, queryMoveSongs, sourceID, targetID); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.MoveSongs")
+	}
+
+	// Участие в чужих песнях переходит к группе; если группа уже указана в песне с той же ролью, запись удалится вместе с группой
+	queryCredits := `
+        UPDATE song_artists sa SET group_id = $2
+        WHERE sa.group_id = $1
+          AND NOT EXISTS (SELECT 1 FROM song_artists t WHERE t.song_id = sa.song_id AND t.group_id = $2 AND t.role = sa.role)
+    `
+	if _, err = tx.ExecContext(ctx, queryCredits, sourceID, targetID); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.MoveCredits")
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE group_aliases SET group_id = $2 WHERE group_id = $1`, sourceID, targetID); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.MoveAliases")
+	}
+
+	queryTarget := `UPDATE groups SET description = COALESCE(description, (SELECT description FROM groups WHERE id = $1)), updated_at = NOW()
+                    WHERE id = $2`
+	if _, err = tx.ExecContext(ctx, queryTarget, sourceID, targetID); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.UpdateTarget")
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM groups WHERE id = $1`, sourceID); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.DeleteSource")
+	}
+
+	// Старое имя продолжает находить группу, если после нормализации оно не совпадает с ее именем
+	queryAlias := `INSERT INTO group_aliases (group_id, alias, alias_key, created_at)
+                   SELECT $1, $2, $3, NOW()
+                   WHERE NOT EXISTS (SELECT 1 FROM groups WHERE name_key = $3)
+                   ON CONFLICT DO NOTHING`
+	if _, err = tx.ExecContext(ctx, queryAlias, targetID, source.Name, utils.NameKey(source.Name)); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.InsertAlias")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.MergeGroups.Commit")
+	}
+
+	result.Group, err = r.GetGroupByID(ctx, targetID)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// groupInfoColumns колонки группы с количеством песен и альбомов, запрос должен содержать псевдоним g (groups)
+const groupInfoColumns = `g.id, g.name, g.description, g.created_at, g.updated_at,
+                     (SELECT COUNT(*) FROM songs s WHERE s.group_id = g.id) AS song_count,
+                     (SELECT COUNT(*) FROM albums a WHERE a.group_id = g.id) AS album_count`
+
+// CreateGroup создает группу, занятое имя - ErrConflict
+func (r lyricsRepo) CreateGroup(ctx context.Context, group models.GroupRequest) (models.GroupInfo, error) {
+	r.logger.Debugf("in repo CreateGroup() group: %+v", group)
+
+	// Имя, совпадающее после нормализации с именем или другим написанием группы, - конфликт
+	var created models.GroupInfo
+	query := `INSERT INTO groups (name, name_key, description, created_at, updated_at)
+              SELECT $1, $2, $3, NOW(), NOW()
+              WHERE NOT EXISTS (SELECT 1 FROM group_aliases WHERE alias_key = $2)
+              RETURNING id, name, description, created_at, updated_at`
+	if err := r.db.GetContext(ctx, &created, query, utils.NormalizeName(group.Name), utils.NameKey(group.Name), group.Description); err != nil {
+		if isUniqueViolation(err) || errors.Is(err, sql.ErrNoRows) {
+			return models.GroupInfo{}, errors.Wrap(lyrics.ErrConflict, "group already exists")
+		}
+		return models.GroupInfo{}, errors.Wrap(err, "lyricsRepo.CreateGroup.GetContext")
+	}
+
+	created.Aliases = []string{}
+	return created, nil
+}
+
+// GetGroups список групп с количеством песен и альбомов, с фильтром по имени и пагинацией
+func (r lyricsRepo) GetGroups(ctx context.Context, name string, offset, limit int) ([]models.GroupInfo, int, error) {
+	groups := []models.GroupInfo{}
+	var total int
+
+	condition := ""
+	args := []interface{}{}
+	if name != "" {
+		condition = " WHERE " + groupNameCondition(1)
+		args = append(args, "%"+name+"%")
+	}
+
+	query := `SELECT ` + groupInfoColumns + `
+              FROM groups g` + condition +
+		" ORDER BY g.name, g.id LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
+	if err := r.db.SelectContext(ctx, &groups, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch groups: %w", err)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM groups g` + condition
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch groups total count: %w", err)
+	}
+
+	if err := r.loadGroupAliases(ctx, groups); err != nil {
+		return nil, 0, err
+	}
+
+	return groups, total, nil
+}
+
+// GetGroupByID группа с количеством песен и альбомов
+func (r lyricsRepo) GetGroupByID(ctx context.Context, id uint) (models.GroupInfo, error) {
+	var group models.GroupInfo
+	query := `SELECT ` + groupInfoColumns + ` FROM groups g WHERE g.id = $1`
+	if err := r.db.GetContext(ctx, &group, query, id); err != nil {
+		return models.GroupInfo{}, fmt.Errorf("failed to fetch group: %w", err)
+	}
+
+	groups := []models.GroupInfo{group}
+	if err := r.loadGroupAliases(ctx, groups); err != nil {
+		return models.GroupInfo{}, err
+	}
+	return groups[0], nil
+}
+
+// UpdateGroupByID переименовывает группу и меняет описание, имя или другое написание другой группы - ErrConflict.
+// Переименование в собственное другое написание меняет их местами: старое имя остается другим написанием.
+// Без описания в запросе сохраненное описание не меняется.
+func (r lyricsRepo) UpdateGroupByID(ctx context.Context, id uint, group models.GroupRequest) (models.GroupInfo, error) {
+	r.logger.Debugf("in repo UpdateGroupByID() id: %d, group: %+v", id, group)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.GroupInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGroupByID.BeginTx")
+	}
+	defer tx.Rollback()
+
+	name, nameKey := utils.NormalizeName(group.Name), utils.NameKey(group.Name)
+
+	var aliasOwner uint
+	err = tx.GetContext(ctx, &aliasOwner, `SELECT group_id FROM group_aliases WHERE alias_key = $1 FOR UPDATE`, nameKey)
+	switch {
+	case err == nil && aliasOwner != id:
+		return models.GroupInfo{}, errors.Wrap(lyrics.ErrConflict, "group name is an alias of another group")
+	case err == nil:
+		queryAlias := `UPDATE group_aliases ga SET alias = g.name, alias_key = g.name_key
+                       FROM groups g
+                       WHERE g.id = $1 AND ga.alias_key = $2`
+		if _, err = tx.ExecContext(ctx, queryAlias, id, nameKey); err != nil {
+			return models.GroupInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGroupByID.SwapAlias")
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return models.GroupInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGroupByID.SelectAlias")
+	}
+
+	query := `UPDATE groups
+              SET name = $1, name_key = $2, description = COALESCE($3, description), updated_at = NOW()
+              WHERE id = $4`
+	result, err := tx.ExecContext(ctx, query, name, nameKey, group.Description, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.GroupInfo{}, errors.Wrap(lyrics.ErrConflict, "group name is already taken")
+		}
+		return models.GroupInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGroupByID.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return models.GroupInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGroupByID.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return models.GroupInfo{}, errors.Wrap(sql.ErrNoRows, "group not found")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.GroupInfo{}, errors.Wrap(err, "lyricsRepo.UpdateGroupByID.Commit")
+	}
+
+	return r.GetGroupByID(ctx, id)
+}
+
+// DeleteGroupByID удаляет группу вместе с ее альбомами.
+// С cascade удаляются и песни группы, без него группа с песнями не удаляется (ErrGroupHasSongs).
+func (r lyricsRepo) DeleteGroupByID(ctx context.Context, id uint, cascade bool) (models.GroupDeleteResult, error) {
+	r.logger.Debugf("in repo DeleteGroupByID() id: %d, cascade: %t", id, cascade)
+
+	result := models.GroupDeleteResult{DeletedSongs: []uint{}}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.DeleteGroupByID.BeginTx")
+	}
+	defer tx.Rollback()
+
+	// Блокируем группу, чтобы к ней не добавили песню, пока она удаляется
+	var groupID uint
+	if err = tx.GetContext(ctx, &groupID, `SELECT id FROM groups WHERE id = $1 FOR UPDATE`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, errors.Wrap(sql.ErrNoRows, "group not found")
+		}
+		return result, errors.Wrap(err, "lyricsRepo.DeleteGroupByID.LockGroup")
+	}
+
+	if !cascade {
+		var hasSongs bool
+		if err = tx.GetContext(ctx, &hasSongs, `SELECT EXISTS (SELECT 1 FROM songs WHERE group_id = $1)`, id); err != nil {
+			return result, errors.Wrap(err, "lyricsRepo.DeleteGroupByID.CountSongs")
+		}
+		if hasSongs {
+			return result, errors.Wrap(lyrics.ErrGroupHasSongs, "group has songs")
+		}
+	}
+
+	if err = tx.SelectContext(ctx, &result.DeletedSongs, `DELETE FROM songs WHERE group_id = $1 RETURNING id`, id); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.DeleteGroupByID.DeleteSongs")
+	}
+
+	albums, err := tx.ExecContext(ctx, `DELETE FROM albums WHERE group_id = $1`, id)
+	if err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.DeleteGroupByID.DeleteAlbums")
+	}
+	deletedAlbums, err := albums.RowsAffected()
+	if err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.DeleteGroupByID.RowsAffected")
+	}
+	result.DeletedAlbums = int(deletedAlbums)
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM groups WHERE id = $1`, id); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.DeleteGroupByID.DeleteGroup")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return result, errors.Wrap(err, "lyricsRepo.DeleteGroupByID.Commit")
+	}
+
+	return result, nil
+}
+
+// DeleteOrphanGroups удаляет группы без песен, альбомов и участия в чужих песнях, не менявшиеся с updatedBefore.
+// Группы, которые getOrCreateGroup заблокировал в еще не завершенной транзакции записи песни, пропускаются (SKIP LOCKED)
+// и удалятся при следующей очистке, если песня так и не появилась.
+func (r lyricsRepo) DeleteOrphanGroups(ctx context.Context, updatedBefore time.Time) (int, error) {
+	query := `
+        DELETE FROM groups
+        WHERE id IN (
+            SELECT g.id FROM groups g
+            WHERE g.updated_at < $1
+              AND NOT EXISTS (SELECT 1 FROM songs s WHERE s.group_id = g.id)
+              AND NOT EXISTS (SELECT 1 FROM albums a WHERE a.group_id = g.id)
+              AND NOT EXISTS (SELECT 1 FROM song_artists sa WHERE sa.group_id = g.id)
+            FOR UPDATE SKIP LOCKED
+        )
+    `
+	result, err := r.db.ExecContext(ctx, query, updatedBefore)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.DeleteOrphanGroups.ExecContext")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.DeleteOrphanGroups.RowsAffected")
+	}
+	return int(deleted), nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrCreateGroup_LocksGroup(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	groupID, err := getOrCreateGroup(context.Background(), tx, "Muse")
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), groupID)
+
+	events := fake.log()
+	require.Len(t, events, 3)
+	assert.True(t, strings.HasPrefix(events[2], "tx: SELECT id FROM groups WHERE id = $1 FOR KEY SHARE"), events[2])
+}
+
+func TestGetOrCreateGroup_RecreatesGroupDeletedByCleanup(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+
+	// Первая блокировка не находит группу: очистка удалила ее после поиска
+	locks := 0
+	fake.value = func(query string) (int64, bool) {
+		if strings.Contains(query, "FOR KEY SHARE") {
+			locks++
+			if locks == 1 {
+				return 0, false
+			}
+			return 2, true
+		}
+		return int64(locks + 1), true
+	}
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	groupID, err := getOrCreateGroup(context.Background(), tx, "Muse")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), groupID)
+	assert.Equal(t, 2, locks)
+}
+
+func TestMergeSongConflictKeep(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		strategy      string
+		sourceUpdated time.Time
+		targetUpdated time.Time
+		wantKept      uint
+		wantRemoved   uint
+	}{
+		{name: "keep target", strategy: models.MergeKeepTarget, sourceUpdated: newer, targetUpdated: older, wantKept: 2, wantRemoved: 1},
+		{name: "keep source", strategy: models.MergeKeepSource, sourceUpdated: older, targetUpdated: newer, wantKept: 1, wantRemoved: 2},
+		{name: "keep newest source", strategy: models.MergeKeepNewest, sourceUpdated: newer, targetUpdated: older, wantKept: 1, wantRemoved: 2},
+		{name: "keep newest target", strategy: models.MergeKeepNewest, sourceUpdated: older, targetUpdated: newer, wantKept: 2, wantRemoved: 1},
+		{name: "keep newest tie keeps target", strategy: models.MergeKeepNewest, sourceUpdated: older, targetUpdated: older, wantKept: 2, wantRemoved: 1},
+		{name: "empty strategy keeps target", strategy: "", sourceUpdated: newer, targetUpdated: older, wantKept: 2, wantRemoved: 1},
+		{name: "unknown strategy keeps target", strategy: "keep_oldest", sourceUpdated: newer, targetUpdated: older, wantKept: 2, wantRemoved: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflict := mergeSongConflict{
+				SongName:      "Uprising",
+				SourceID:      1,
+				SourceUpdated: tt.sourceUpdated,
+				TargetID:      2,
+				TargetUpdated: tt.targetUpdated,
+			}
+
+			kept, removed := conflict.keep(tt.strategy)
+			assert.Equal(t, tt.wantKept, kept)
+			assert.Equal(t, tt.wantRemoved, removed)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// ImportSongs сохраняет пачку строк импорта в одной транзакции.
+// Каждая строка выполняется под своей точкой сохранения, поэтому ошибка строки не откатывает остальные.
+// Существующие песни пропускаются или, с opts.Overwrite, перезаписываются. С opts.Enrich сохраненная песня
+// без текста, ссылки или даты выхода переводится в pending и ставится в очередь обогащения.
+func (r lyricsRepo) ImportSongs(ctx context.Context, rows []models.ImportRow, opts models.ImportOptions) ([]models.ImportRowResult, error) {
+	r.logger.Debugf("in repo ImportSongs() rows: %d, options: %+v", len(rows), opts)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "lyricsRepo.ImportSongs.BeginTx")
+	}
+	defer tx.Rollback()
+
+	results := make([]models.ImportRowResult, 0, len(rows))
+	for _, row := range rows {
+		result := models.ImportRowResult{Group: row.Group, Song: row.Song}
+
+		if _, err = tx.ExecContext(ctx, `SAVEPOINT import_row`); err != nil {
+			return nil, errors.Wrap(err, "lyricsRepo.ImportSongs.Savepoint")
+		}
+
+		enrich := opts.Enrich && (row.Text == "" || row.Link == "" || row.ReleaseDate == "")
+		songID, status, err := importSong(ctx, tx, row, opts.Overwrite, enrich)
+		if err == nil && enrich && status != models.ImportSkipped {
+			var job models.EnrichmentJob
+			if job, err = enqueueEnrichmentJob(ctx, tx, songID); err == nil {
+				result.JobID = &job.ID
+			}
+		}
+		if err != nil {
+			if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT import_row`); rbErr != nil {
+				return nil, errors.Wrap(rbErr, "lyricsRepo.ImportSongs.RollbackToSavepoint")
+			}
+			r.logger.Warnf("import of %s - %s failed: %v", row.Group, row.Song, err)
+			result.Status = models.ImportFailed
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.Status = status
+		result.SongID = &songID
+		if status == models.ImportSkipped {
+			result.Error = "song already exists"
+		}
+		results = append(results, result)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "lyricsRepo.ImportSongs.Commit")
+	}
+
+	return results, nil
+}
+
+// importSong добавляет или перезаписывает песню строки импорта, возвращает ID и результат.
+// С enrich песня сохраняется в статусе pending - задачу обогащения ставит вызывающий.
+func importSong(ctx context.Context, tx *sqlx.Tx, row models.ImportRow, overwrite, enrich bool) (uint, string, error) {
+	groupID, err := getOrCreateGroup(ctx, tx, row.Group)
+	if err != nil {
+		return 0, "", errors.Wrap(err, "get or create group")
+	}
+
+	releaseDate, err := releaseDateArg(row.ReleaseDate)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var link interface{}
+	if row.Link != "" {
+		link = row.Link
+	}
+
+	status := models.SongStatusEnriched
+	if enrich {
+		status = models.SongStatusPending
+	}
+
+	var songID uint
+	queryInsert := `
+        INSERT INTO songs (group_id, song_name, release_date, text, link, source, status, language, language_confidence, song_key, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+        ON CONFLICT (group_id, song_key) DO NOTHING
+        RETURNING id
+    `
+	songKey := utils.NameKey(row.Song)
+	err = tx.GetContext(ctx, &songID, queryInsert, groupID, utils.NormalizeName(row.Song), releaseDate, row.Text, link, row.Source, status,
+		row.Language.Language, row.Language.Confidence, songKey)
+	if err == nil {
+		if err = saveSongArtists(ctx, tx, songID, groupID, row.Artists); err != nil {
+			return 0, "", err
+		}
+		return songID, models.ImportCreated, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, "", errors.Wrap(err, "insert song")
+	}
+
+	// Песня уже есть
+	if !overwrite {
+		if err = tx.GetContext(ctx, &songID, `SELECT id FROM songs WHERE group_id = $1 AND song_key = $2`, groupID, songKey); err != nil {
+			return 0, "", errors.Wrap(err, "select existing song")
+		}
+		return songID, models.ImportSkipped, nil
+	}
+
+	queryUpdate := `
+        UPDATE songs
+        SET release_date = $3, text = $4, link = $5, source = $6, status = $7, enrich_error = NULL, updated_at = NOW(),
+            ` + setDetectedLanguage(8, 9) + `
+        WHERE group_id = $1 AND song_key = $2
+        RETURNING id
+    `
+	if err = tx.GetContext(ctx, &songID, queryUpdate, groupID, songKey, releaseDate, row.Text, link, row.Source, status,
+		row.Language.Language, row.Language.Confidence); err != nil {
+		return 0, "", errors.Wrap(err, "update song")
+	}
+	if err = saveSongArtists(ctx, tx, songID, groupID, row.Artists); err != nil {
+		return 0, "", err
+	}
+	return songID, models.ImportUpdated, nil
+}
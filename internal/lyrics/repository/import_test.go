@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportSongs_EnqueuesIncompleteRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		row    models.ImportRow
+		opts   models.ImportOptions
+		queued bool
+	}{
+		{name: "incomplete with enrich", row: models.ImportRow{Group: "Muse", Song: "Uprising"},
+			opts: models.ImportOptions{Enrich: true}, queued: true},
+		{name: "complete with enrich", row: models.ImportRow{Group: "Muse", Song: "Uprising", Text: "verse", Link: "https://example.com", ReleaseDate: "2009"},
+			opts: models.ImportOptions{Enrich: true}, queued: false},
+		{name: "incomplete without enrich", row: models.ImportRow{Group: "Muse", Song: "Uprising"}, queued: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, nil)
+			repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+			results, err := repo.ImportSongs(context.Background(), []models.ImportRow{tt.row}, tt.opts)
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, models.ImportCreated, results[0].Status)
+			assert.Equal(t, tt.queued, results[0].JobID != nil)
+
+			queued := false
+			for _, event := range fake.log() {
+				queued = queued || strings.HasPrefix(event, "tx: INSERT INTO enrichment_jobs")
+			}
+			assert.Equal(t, tt.queued, queued)
+		})
+	}
+}
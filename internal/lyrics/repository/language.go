@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// setDetectedLanguage часть SET, сохраняющая определенный язык текста.
+// Язык, заданный вручную, не перезаписывается. langParam и confidenceParam - номера параметров запроса.
+func setDetectedLanguage(langParam, confidenceParam int) string {
+	return fmt.Sprintf(`language = CASE WHEN language_manual THEN language ELSE $%d END,
+            language_confidence = CASE WHEN language_manual THEN language_confidence ELSE $%d END`, langParam, confidenceParam)
+}
+
+// languageCondition условие фильтра по языку: und - язык не определен,
+// код без региона (pt) находит и региональные варианты (pt-BR)
+func languageCondition(language string, args []interface{}) (string, []interface{}) {
+	if language == models.LanguageUndetermined {
+		return "s.language IS NULL", args
+	}
+
+	condition := "(s.language = $" + strconv.Itoa(len(args)+1) + " OR s.language LIKE $" + strconv.Itoa(len(args)+2) + ")"
+	return condition, append(args, language, language+"-%")
+}
+
+// libraryFacets количество песен по языкам, жанрам и меткам. Каждый фасет учитывает все фильтры библиотеки,
+// кроме своего, чтобы в нем оставались остальные значения, на которые можно переключиться.
+func (r lyricsRepo) libraryFacets(ctx context.Context, filter models.LibraryFilter) (models.LibraryFacets, error) {
+	facets := models.LibraryFacets{Language: []models.FacetCount{}}
+
+	languageFilter := filter
+	languageFilter.Language = ""
+	conditions, args := buildLibraryConditions(languageFilter)
+	query := `SELECT COALESCE(s.language, '` + models.LanguageUndetermined + `') AS value, COUNT(*) AS count
+              FROM songs s
+              INNER JOIN groups g ON s.group_id = g.id
+              LEFT JOIN albums a ON s.album_id = a.id` + whereClause(conditions) + `
+              GROUP BY 1
+              ORDER BY count DESC, value`
+	if err := r.db.SelectContext(ctx, &facets.Language, query, args...); err != nil {
+		return facets, fmt.Errorf("failed to fetch language facet: %w", err)
+	}
+
+	var err error
+	if facets.Genre, err = r.genreFacet(ctx, filter); err != nil {
+		return facets, err
+	}
+	if facets.Tag, err = r.tagFacet(ctx, filter); err != nil {
+		return facets, err
+	}
+
+	return facets, nil
+}
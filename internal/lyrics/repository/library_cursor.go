@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/pkg/errors"
+)
+
+// libraryCursor позиция в выдаче библиотеки: сортировка и значения ее ключей у последней отданной песни.
+// Клиент получает курсор как непрозрачную base64-строку.
+type libraryCursor struct {
+	Sort   string    `json:"s"`
+	Values []*string `json:"v"`
+}
+
+// sortSpec строковое представление сортировки, курсор действителен только для той же сортировки
+func sortSpec(sort []models.SortField) string {
+	parts := make([]string, 0, len(sort))
+	for _, field := range sort {
+		if field.Desc {
+			parts = append(parts, "-"+field.Field)
+			continue
+		}
+		parts = append(parts, field.Field)
+	}
+	return strings.Join(parts, ",")
+}
+
+// encodeLibraryCursor курсор, указывающий на песню song
+func encodeLibraryCursor(sort []models.SortField, song models.Song) string {
+	cursor := libraryCursor{Sort: sortSpec(sort)}
+
+	for _, field := range sort {
+		var value string
+		switch field.Field {
+		case "id":
+			value = strconv.FormatUint(uint64(song.ID), 10)
+		case "group":
+			value = song.GroupName
+		case "song":
+			value = song.SongName
+		case "release_date":
+			if song.ReleaseDate == nil {
+				cursor.Values = append(cursor.Values, nil)
+				continue
+			}
+			value = song.ReleaseDate.String()
+		case "created_at":
+			value = song.CreatedAt.Format(time.RFC3339Nano)
+		case "updated_at":
+			value = song.UpdatedAt.Format(time.RFC3339Nano)
+		}
+		cursor.Values = append(cursor.Values, &value)
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeLibraryCursor разбирает курсор и приводит значения ключей к типам колонок, NULL - nil
+func decodeLibraryCursor(encoded string, sort []models.SortField) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(lyrics.ErrInvalidCursor, "cursor is not base64")
+	}
+
+	var cursor libraryCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, errors.Wrap(lyrics.ErrInvalidCursor, "cursor is malformed")
+	}
+
+	if cursor.Sort != sortSpec(sort) || len(cursor.Values) != len(sort) {
+		return nil, errors.Wrap(lyrics.ErrInvalidCursor, "cursor was issued for a different sort")
+	}
+
+	values := make([]interface{}, len(sort))
+	for i, field := range sort {
+		raw := cursor.Values[i]
+		if raw == nil {
+			if field.Field != "release_date" {
+				return nil, errors.Wrapf(lyrics.ErrInvalidCursor, "cursor value for %s is empty", field.Field)
+			}
+			continue
+		}
+
+		var err error
+		switch field.Field {
+		case "id":
+			values[i], err = strconv.ParseInt(*raw, 10, 64)
+		case "group", "song":
+			values[i] = *raw
+		case "release_date":
+			values[i], err = time.Parse(models.DateLayout, *raw)
+		case "created_at", "updated_at":
+			values[i], err = time.Parse(time.RFC3339Nano, *raw)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(lyrics.ErrInvalidCursor, "cursor value for %s is invalid", field.Field)
+		}
+	}
+
+	return values, nil
+}
+
+// buildKeysetCondition условие "строка идет после курсора" для сортировки sort.
+// Для ключей k1, k2, id: k1 после v1 ИЛИ (k1 = v1 И (k2 после v2 ИЛИ (k2 = v2 И id после vid))).
+// NULL всегда в конце (NULLS LAST), поэтому после NULL идут только строки с NULL.
+func buildKeysetCondition(sort []models.SortField, values []interface{}, args []interface{}) (string, []interface{}) {
+	condition := ""
+
+	for i := len(sort) - 1; i >= 0; i-- {
+		column := librarySortColumns[sort[i].Field]
+
+		var after, equal string
+		if values[i] == nil {
+			after = "FALSE"
+			equal = column + " IS NULL"
+		} else {
+			args = append(args, values[i])
+			param := "$" + strconv.Itoa(len(args))
+
+			operator := " > "
+			if sort[i].Desc {
+				operator = " < "
+			}
+			after = "(" + column + operator + param + " OR " + column + " IS NULL)"
+			equal = column + " = " + param
+		}
+
+		if condition == "" {
+			condition = after
+			continue
+		}
+		condition = "(" + after + " OR (" + equal + " AND " + condition + "))"
+	}
+
+	return condition, args
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cursorSong() models.Song {
+	releaseDate := models.NewDate(time.Date(2009, 9, 14, 0, 0, 0, 0, time.UTC))
+	return models.Song{
+		ID:          42,
+		GroupName:   "Muse",
+		SongName:    "Uprising, \"live\"",
+		ReleaseDate: &releaseDate,
+		CreatedAt:   time.Date(2024, 3, 1, 10, 20, 30, 123456789, time.UTC),
+		UpdatedAt:   time.Date(2024, 3, 2, 11, 0, 0, 0, time.FixedZone("MSK", 3*60*60)),
+	}
+}
+
+func TestLibraryCursor_RoundTrip(t *testing.T) {
+	sort := []models.SortField{
+		{Field: "group"},
+		{Field: "release_date", Desc: true},
+		{Field: "song"},
+		{Field: "created_at"},
+		{Field: "updated_at", Desc: true},
+		{Field: "id"},
+	}
+	song := cursorSong()
+
+	values, err := decodeLibraryCursor(encodeLibraryCursor(sort, song), sort)
+	require.NoError(t, err)
+	require.Len(t, values, len(sort))
+
+	assert.Equal(t, "Muse", values[0])
+	assert.True(t, song.ReleaseDate.Time.Equal(values[1].(time.Time)), "release_date: %v", values[1])
+	assert.Equal(t, song.SongName, values[2])
+	assert.True(t, song.CreatedAt.Equal(values[3].(time.Time)), "created_at lost precision: %v", values[3])
+	assert.True(t, song.UpdatedAt.Equal(values[4].(time.Time)), "updated_at lost time zone: %v", values[4])
+	assert.Equal(t, int64(42), values[5])
+}
+
+func TestLibraryCursor_NullReleaseDate(t *testing.T) {
+	sort := []models.SortField{{Field: "release_date"}, {Field: "id"}}
+	song := cursorSong()
+	song.ReleaseDate = nil
+
+	values, err := decodeLibraryCursor(encodeLibraryCursor(sort, song), sort)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{nil, int64(42)}, values)
+
+	// После песни без даты идут только песни без даты (NULLS LAST)
+	condition, args := buildKeysetCondition(sort, values, nil)
+	assert.Equal(t, "(FALSE OR (s.release_date IS NULL AND (s.id > $1 OR s.id IS NULL)))", condition)
+	assert.Equal(t, []interface{}{int64(42)}, args)
+}
+
+func TestBuildKeysetCondition(t *testing.T) {
+	sort := []models.SortField{{Field: "release_date", Desc: true}, {Field: "id"}}
+	date := time.Date(2009, 9, 14, 0, 0, 0, 0, time.UTC)
+
+	condition, args := buildKeysetCondition(sort, []interface{}{date, int64(42)}, []interface{}{"%muse%"})
+	assert.Equal(t,
+		"((s.release_date < $3 OR s.release_date IS NULL) OR (s.release_date = $3 AND (s.id > $2 OR s.id IS NULL)))",
+		condition)
+	assert.Equal(t, []interface{}{"%muse%", int64(42), date}, args)
+}
+
+func TestDecodeLibraryCursor_Rejects(t *testing.T) {
+	sort := []models.SortField{{Field: "release_date", Desc: true}, {Field: "id"}}
+	valid := encodeLibraryCursor(sort, cursorSong())
+	raw := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   []models.SortField
+	}{
+		{name: "other direction", cursor: valid, sort: []models.SortField{{Field: "release_date"}, {Field: "id"}}},
+		{name: "other field", cursor: valid, sort: []models.SortField{{Field: "created_at", Desc: true}, {Field: "id"}}},
+		{name: "fewer fields", cursor: valid, sort: []models.SortField{{Field: "id"}}},
+		{name: "not base64", cursor: "not a cursor!", sort: sort},
+		{name: "padded base64", cursor: base64.URLEncoding.EncodeToString([]byte(`{"s":"id","v":["1"]}`)) + "=", sort: []models.SortField{{Field: "id"}}},
+		{name: "truncated", cursor: valid[:len(valid)-4], sort: sort},
+		{name: "not json", cursor: raw("hello"), sort: sort},
+		{name: "values count differs from sort", cursor: raw(`{"s":"-release_date,id","v":["2009-09-14"]}`), sort: sort},
+		{name: "null id", cursor: raw(`{"s":"-release_date,id","v":["2009-09-14",null]}`), sort: sort},
+		{name: "non numeric id", cursor: raw(`{"s":"-release_date,id","v":["2009-09-14","1 OR 1=1"]}`), sort: sort},
+		{name: "invalid date", cursor: raw(`{"s":"-release_date,id","v":["14.09.2009","42"]}`), sort: sort},
+		{name: "invalid timestamp", cursor: raw(`{"s":"created_at,id","v":["yesterday","42"]}`), sort: []models.SortField{{Field: "created_at"}, {Field: "id"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := decodeLibraryCursor(tt.cursor, tt.sort)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, lyrics.ErrInvalidCursor), "unexpected error: %v", err)
+			assert.Nil(t, values)
+		})
+	}
+}
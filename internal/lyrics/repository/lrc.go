@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+)
+
+// SetSongLRC сохраняет синхронизированный текст песни, nil удаляет его
+func (r lyricsRepo) SetSongLRC(ctx context.Context, id uint, lrc *string) error {
+	r.logger.Debugf("in repo SetSongLRC() id: %d", id)
+
+	result, err := r.db.ExecContext(ctx, `UPDATE songs SET lrc = $1, updated_at = NOW() WHERE id = $2`, lrc, id)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.SetSongLRC.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.SetSongLRC.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "song not found")
+	}
+
+	return nil
+}
@@ -6,15 +6,21 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/22Fariz22/musiclab/internal/lyrics"
 	"github.com/22Fariz22/musiclab/internal/models"
 	"github.com/22Fariz22/musiclab/pkg/logger"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/jackc/pgx"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 )
 
+// queryRower общий интерфейс *sqlx.DB и транзакций для запросов, возвращающих одну строку
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 type lyricsRepo struct {
 	db     *sqlx.DB
 	logger logger.Logger
@@ -24,6 +30,62 @@ func NewLyricsRepository(db *sqlx.DB, logger logger.Logger) lyrics.Repository {
 	return &lyricsRepo{db: db, logger: logger}
 }
 
+// getOrCreateGroup возвращает ID группы по имени, создавая ее при необходимости.
+// Имена сравниваются по нормализованному ключу, другое написание имени (group_aliases) возвращает основную группу.
+// Группа блокируется (FOR KEY SHARE) до конца транзакции q, поэтому вызывать нужно в той же транзакции,
+// что и запись песни: очистка групп без песен пропускает заблокированные группы. Группу, которую очистка
+// удалила между поиском и блокировкой, создаем заново.
+func getOrCreateGroup(ctx context.Context, q queryRower, name string) (uint, error) {
+	queryGroup := `
+        WITH alias AS (
+            SELECT group_id AS id FROM group_aliases WHERE alias_key = $2
+        ), ins AS (
+            INSERT INTO groups (name, name_key, created_at, updated_at)
+            SELECT $1, $2, NOW(), NOW()
+            WHERE NOT EXISTS (SELECT 1 FROM alias)
+            ON CONFLICT DO NOTHING
+            RETURNING id
+        )
+        SELECT id FROM alias
+        UNION ALL
+        SELECT id FROM ins
+        UNION ALL
+        SELECT id FROM groups WHERE name_key = $2
+        LIMIT 1
+    `
+	for attempt := 0; ; attempt++ {
+		var groupID uint
+		if err := q.QueryRowContext(ctx, queryGroup, utils.NormalizeName(name), utils.NameKey(name)).Scan(&groupID); err != nil {
+			return 0, err
+		}
+
+		err := q.QueryRowContext(ctx, `SELECT id FROM groups WHERE id = $1 FOR KEY SHARE`, groupID).Scan(&groupID)
+		if errors.Is(err, sql.ErrNoRows) && attempt == 0 {
+			continue
+		}
+		return groupID, err
+	}
+}
+
+// releaseDateArg приводит дату выхода к значению для колонки DATE, пустая строка - NULL
+func releaseDateArg(value string) (interface{}, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	date, err := utils.ParseReleaseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	return date, nil
+}
+
+// isUniqueViolation ошибка нарушения уникального индекса
+func isUniqueViolation(err error) bool {
+	var pgErr pgx.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
 // Ping check
 func (r lyricsRepo) Ping() error {
 	r.logger.Debug("Call repo Ping()")
@@ -73,32 +135,34 @@ func (r lyricsRepo) UpdateTrackByID(ctx context.Context, updateData models.Updat
 		return errors.New("group name and song name cannot be nil")
 	}
 
+	// Песня и ее участники меняются вместе: ошибка при сохранении участников не должна оставить переименованную песню без них
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "LyricsRepository.UpdateTrackByID.BeginTx")
+	}
+	defer tx.Rollback()
+
 	// Проверяем существование или создаем группу
-	var groupID uint
-	err := r.db.QueryRowContext(ctx, "SELECT id FROM groups WHERE name = $1", *updateData.GroupName).Scan(&groupID)
+	groupID, err := getOrCreateGroup(ctx, tx, *updateData.GroupName)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			r.logger.Debugf("group not found, creating new group with name: %s", *updateData.GroupName)
-			err = r.db.QueryRowContext(ctx, "INSERT INTO groups (name,created_at,updated_at) VALUES ($1,$2,$2) RETURNING id", *updateData.GroupName, time.Now()).Scan(&groupID)
-			if err != nil {
-				r.logger.Debugf("error creating new group: %v", err)
-				return errors.Wrap(err, "failed to create new group")
-			}
-		} else {
-			r.logger.Debugf("error fetching group ID for name %s: %v", *updateData.GroupName, err)
-			return errors.Wrap(err, "error fetching group ID")
-		}
+		r.logger.Debugf("error getting/creating group %s: %v", *updateData.GroupName, err)
+		return errors.Wrap(err, "LyricsRepository.UpdateTrackByID.GetOrCreateGroup")
 	}
 
 	// Начинаем формировать запрос для обновления песни
-	query := "UPDATE songs SET updated_at = NOW(), group_id = $1, song_name = $2"
-	params := []interface{}{groupID, *updateData.SongName}
-	paramCount := 3
+	query := "UPDATE songs SET updated_at = NOW(), group_id = $1, song_name = $2, song_key = $3"
+	params := []interface{}{groupID, utils.NormalizeName(*updateData.SongName), utils.NameKey(*updateData.SongName)}
+	paramCount := 4
 
 	// Добавляем опциональные поля
 	if updateData.ReleaseDate != nil {
+		releaseDate, err := releaseDateArg(*updateData.ReleaseDate)
+		if err != nil {
+			r.logger.Debugf("invalid release date %q: %v", *updateData.ReleaseDate, err)
+			return errors.Wrap(err, "LyricsRepository.UpdateTrackByID.ParseReleaseDate")
+		}
 		query += fmt.Sprintf(", release_date = $%d", paramCount)
-		params = append(params, *updateData.ReleaseDate)
+		params = append(params, releaseDate)
 		paramCount++
 	}
 
@@ -114,15 +178,35 @@ func (r lyricsRepo) UpdateTrackByID(ctx context.Context, updateData models.Updat
 		paramCount++
 	}
 
+	// Язык, заданный вручную, закрепляется; пустой язык снимает закрепление
+	if updateData.Language != nil && *updateData.Language != "" {
+		query += fmt.Sprintf(", language = $%d, language_confidence = NULL, language_manual = TRUE", paramCount)
+		params = append(params, *updateData.Language)
+		paramCount++
+	} else if detected := updateData.DetectedLanguage; detected != nil {
+		if updateData.Language != nil {
+			query += fmt.Sprintf(", language = $%d, language_confidence = $%d, language_manual = FALSE", paramCount, paramCount+1)
+		} else {
+			query += ", " + setDetectedLanguage(paramCount, paramCount+1)
+		}
+		params = append(params, detected.Language, detected.Confidence)
+		paramCount += 2
+	}
+
+	// Данные, заданные редактором, фоновое обновление больше не перезаписывает
+	if updateData.ReleaseDate != nil || updateData.Text != nil || updateData.Link != nil {
+		query += ", edited_at = NOW()"
+	}
+
 	// Добавляем условие WHERE
 	query += fmt.Sprintf(" WHERE id = $%d", paramCount)
 	params = append(params, updateData.ID)
 
 	// Выполняем запрос
 	r.logger.Debugf("executing query: %s with params: %+v", query, params)
-	result, err := r.db.ExecContext(ctx, query, params...)
+	result, err := tx.ExecContext(ctx, query, params...)
 	if err != nil {
-		r.logger.Debugf("error in UpdateTrackByID() r.db.ExecContext: %v", err)
+		r.logger.Debugf("error in UpdateTrackByID() tx.ExecContext: %v", err)
 		return errors.Wrap(err, "LyricsRepository.UpdateTrackByID.ExecContext")
 	}
 
@@ -137,147 +221,347 @@ func (r lyricsRepo) UpdateTrackByID(ctx context.Context, updateData models.Updat
 		return errors.Wrap(sql.ErrNoRows, "song not found")
 	}
 
+	if err = saveSongArtists(ctx, tx, updateData.ID, groupID, updateData.Artists); err != nil {
+		r.logger.Debugf("error saving artists of song %d: %v", updateData.ID, err)
+		return errors.Wrap(err, "LyricsRepository.UpdateTrackByID.SaveArtists")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "LyricsRepository.UpdateTrackByID.Commit")
+	}
+
 	r.logger.Debug("successfully updated track")
 	return nil
 }
 
-func (r lyricsRepo) CreateTrack(ctx context.Context, songRequest models.SongRequest, songDetail models.SongDetail) error {
+// CreateTrack создает песню и возвращает ее ID.
+// Уже существующая песня группы не меняется: возвращаются ее ID и false.
+func (r lyricsRepo) CreateTrack(ctx context.Context, songRequest models.SongRequest, songDetail models.SongDetail) (uint, bool, error) {
 	// Начинаем транзакцию
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return errors.Wrap(err, "lyricsRepo.CreateTrack.BeginTx")
+		return 0, false, errors.Wrap(err, "lyricsRepo.CreateTrack.BeginTx")
 	}
 	defer tx.Rollback()
 
 	// Получаем или создаем группу
-	var groupID uint
-	queryGroup := `
-        WITH ins AS (
-            INSERT INTO groups (name, created_at, updated_at)
-            VALUES ($1, NOW(), NOW())
-            ON CONFLICT (name) DO NOTHING
-            RETURNING id
-        )
-        SELECT id FROM ins
-        UNION ALL
-        SELECT id FROM groups WHERE name = $1
-        LIMIT 1
-    `
-	err = tx.QueryRowContext(ctx, queryGroup, songRequest.Group).Scan(&groupID)
+	groupID, err := getOrCreateGroup(ctx, tx, songRequest.Group)
 	if err != nil {
 		r.logger.Errorf("error getting/creating group: %v", err)
-		return errors.Wrap(err, "lyricsRepo.CreateTrack.QueryGroup")
+		return 0, false, errors.Wrap(err, "lyricsRepo.CreateTrack.QueryGroup")
 	}
 
-	// Проверяем существование песни у этой группы
-	var exists bool
-	queryCheck := `
-        SELECT EXISTS (
-            SELECT 1 FROM songs 
-            WHERE group_id = $1 AND song_name = $2
-        )
-    `
-	err = tx.QueryRowContext(ctx, queryCheck, groupID, songRequest.Song).Scan(&exists)
+	// API может вернуть дату в неизвестном формате, такую дату не сохраняем
+	releaseDate, err := releaseDateArg(songDetail.ReleaseDate)
 	if err != nil {
-		r.logger.Errorf("error checking song existence: %v", err)
-		return errors.Wrap(err, "lyricsRepo.CreateTrack.CheckExistence")
-	}
-
-	if exists {
-		return nil
+		r.logger.Warnf("skipping release date of %s - %s: %v", songRequest.Group, songRequest.Song, err)
 	}
 
-	// Добавляем песню
+	// Добавляем песню, если ее еще нет у этой группы
+	var songID uint
 	queryInsert := `
-        INSERT INTO songs (group_id, song_name, release_date, text, link, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+        INSERT INTO songs (group_id, song_name, release_date, text, link, source, status, language, language_confidence, song_key, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+        ON CONFLICT (group_id, song_key) DO NOTHING
+        RETURNING id
     `
-	_, err = tx.ExecContext(
+	err = tx.QueryRowContext(
 		ctx,
 		queryInsert,
 		groupID,
-		songRequest.Song,
-		songDetail.ReleaseDate,
+		utils.NormalizeName(songRequest.Song),
+		releaseDate,
 		songDetail.Text,
 		songDetail.Link,
-	)
+		songDetail.Source,
+		models.SongStatusEnriched,
+		songDetail.Language.Language,
+		songDetail.Language.Confidence,
+		utils.NameKey(songRequest.Song),
+	).Scan(&songID)
+	if errors.Is(err, sql.ErrNoRows) {
+		if songID, err = getSongIDByKey(ctx, tx, groupID, songRequest.Song); err != nil {
+			return 0, false, errors.Wrap(err, "lyricsRepo.CreateTrack.SelectSong")
+		}
+		r.logger.Debugf("track %d already exists, skipping", songID)
+		return songID, false, nil
+	}
 	if err != nil {
 		r.logger.Errorf("error inserting song: %v", err)
-		return errors.Wrap(err, "lyricsRepo.CreateTrack.InsertSong")
+		return 0, false, errors.Wrap(err, "lyricsRepo.CreateTrack.InsertSong")
+	}
+
+	if err = saveSongArtists(ctx, tx, songID, groupID, songRequest.Artists); err != nil {
+		r.logger.Errorf("error saving song artists: %v", err)
+		return 0, false, errors.Wrap(err, "lyricsRepo.CreateTrack.SaveArtists")
 	}
 
 	// Подтверждаем транзакцию
 	if err = tx.Commit(); err != nil {
 		r.logger.Errorf("error committing transaction: %v", err)
-		return errors.Wrap(err, "lyricsRepo.CreateTrack.Commit")
+		return 0, false, errors.Wrap(err, "lyricsRepo.CreateTrack.Commit")
 	}
 
-	r.logger.Debug("successfully created track")
-	return nil
+	r.logger.Debugf("successfully created track ID: %d", songID)
+	return songID, true, nil
+}
+
+// getSongIDByKey ID песни группы по нормализованному ключу названия
+func getSongIDByKey(ctx context.Context, q queryRower, groupID uint, song string) (uint, error) {
+	var songID uint
+	query := `SELECT id FROM songs WHERE group_id = $1 AND song_key = $2`
+	err := q.QueryRowContext(ctx, query, groupID, utils.NameKey(song)).Scan(&songID)
+	return songID, err
 }
 
-// GetSongByID получаем песню по ID
+// GetSongByID получаем песню по ID вместе с группой и альбомом
 func (r lyricsRepo) GetSongByID(ctx context.Context, id uint) (models.Song, error) {
 	var song models.Song
-	query := `SELECT text FROM songs WHERE id = $1`
+	query := `SELECT s.id, s.group_id, g.name AS group_name, s.song_name, s.album_id, a.title AS album_title,
+                     s.track_number, s.release_date, s.text, s.link, s.lrc, s.source,
+                     s.language, s.language_confidence, s.language_manual, s.status, s.enrich_error, s.created_at, s.updated_at
+              FROM songs s
+              INNER JOIN groups g ON s.group_id = g.id
+              LEFT JOIN albums a ON s.album_id = a.id
+              WHERE s.id = $1`
 
 	err := r.db.GetContext(ctx, &song, query, id)
 	if err != nil {
 		return models.Song{}, fmt.Errorf("failed to fetch song: %w", err)
 	}
 
+	if err = r.loadSongArtists(ctx, &song); err != nil {
+		return models.Song{}, err
+	}
+	if err = r.loadSongGenres(ctx, &song); err != nil {
+		return models.Song{}, err
+	}
+	if err = r.loadSongTags(ctx, &song); err != nil {
+		return models.Song{}, err
+	}
+
 	return song, nil
 }
 
-// GetLibrary Получение данных библиотеки с фильтрацией по всем полям и пагинацией
-func (r lyricsRepo) GetLibrary(ctx context.Context, group, song, text, releaseDate string, offset, limit int) ([]models.Song, int, error) {
-	var songs []models.Song
-	var total int
+// GetLibrary Получение данных библиотеки с фильтрацией по всем полям и пагинацией.
+// Если задан курсор (filter.After), используется keyset-пагинация по ключу сортировки вместо OFFSET.
+func (r lyricsRepo) GetLibrary(ctx context.Context, filter models.LibraryFilter) (models.LibraryPage, error) {
+	var page models.LibraryPage
+	sort := normalizeLibrarySort(filter.Sort)
 
-	baseQuery := `SELECT s.id, s.group_id, g.name AS group_name, s.song_name, s.text, s.release_date, s.link
+	baseQuery := `SELECT ` + buildLibrarySelect(filter.Fields, sort) + `
                   FROM songs s
-                  INNER JOIN groups g ON s.group_id = g.id`
-	baseCountQuery := `SELECT COUNT(*) FROM songs s INNER JOIN groups g ON s.group_id = g.id`
+                  INNER JOIN groups g ON s.group_id = g.id
+                  LEFT JOIN albums a ON s.album_id = a.id`
+	baseCountQuery := `SELECT COUNT(*) FROM songs s
+                       INNER JOIN groups g ON s.group_id = g.id
+                       LEFT JOIN albums a ON s.album_id = a.id`
+
+	conditions, args := buildLibraryConditions(filter)
+	baseCountQuery += whereClause(conditions)
+	countArgs := append([]interface{}{}, args...)
+
+	// Продолжаем после последней строки предыдущей страницы
+	if filter.After != nil && *filter.After != "" {
+		cursor, err := decodeLibraryCursor(*filter.After, sort)
+		if err != nil {
+			return page, err
+		}
+		var keysetCondition string
+		keysetCondition, args = buildKeysetCondition(sort, cursor, args)
+		conditions = append(conditions, keysetCondition)
+	}
+	baseQuery += whereClause(conditions)
+
+	// Добавляем сортировку и пагинацию
+	baseQuery += buildLibraryOrder(sort)
+	if filter.After != nil {
+		// Лишняя строка показывает, есть ли следующая страница
+		baseQuery += " LIMIT $" + strconv.Itoa(len(args)+1)
+		args = append(args, filter.Limit+1)
+	} else {
+		baseQuery += " LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
+		args = append(args, filter.Limit, filter.Offset())
+	}
+
+	// Выполняем запрос на выборку данных
+	if err := r.db.SelectContext(ctx, &page.Songs, baseQuery, args...); err != nil {
+		return page, fmt.Errorf("failed to fetch songs: %w", err)
+	}
+
+	if filter.After != nil && len(page.Songs) > filter.Limit {
+		page.Songs = page.Songs[:filter.Limit]
+		page.NextCursor = encodeLibraryCursor(sort, page.Songs[len(page.Songs)-1])
+	}
+
+	// Выполняем запрос на подсчет общего количества записей
+	if filter.WithTotal {
+		var total int
+		if err := r.db.GetContext(ctx, &total, baseCountQuery, countArgs...); err != nil {
+			return page, fmt.Errorf("failed to fetch total count: %w", err)
+		}
+		page.Total = &total
+
+		facets, err := r.libraryFacets(ctx, filter)
+		if err != nil {
+			return page, err
+		}
+		page.Facets = &facets
+	}
+
+	return page, nil
+}
+
+// whereClause объединяет условия через AND
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// buildLibraryConditions собирает условия WHERE для фильтров библиотеки.
+// Запрос должен содержать псевдонимы s (songs), g (groups) и a (albums).
+func buildLibraryConditions(filter models.LibraryFilter) ([]string, []interface{}) {
 	conditions := []string{}
 	args := []interface{}{}
 
-	if group != "" {
-		conditions = append(conditions, "g.name ILIKE $"+strconv.Itoa(len(args)+1)) // Фильтрация по названию группы
-		args = append(args, "%"+group+"%")
+	if filter.Group != "" {
+		conditions = append(conditions, creditedArtistCondition(len(args)+1)) // Фильтрация по группе и любому участнику песни
+		args = append(args, "%"+filter.Group+"%")
 	}
-	if song != "" {
+	if filter.Song != "" {
 		conditions = append(conditions, "s.song_name ILIKE $"+strconv.Itoa(len(args)+1)) // Фильтрация по названию песни
-		args = append(args, "%"+song+"%")
+		args = append(args, "%"+filter.Song+"%")
 	}
-	if releaseDate != "" {
+	if filter.ReleaseDate != nil {
 		conditions = append(conditions, "s.release_date = $"+strconv.Itoa(len(args)+1)) // Фильтрация по дате релиза
-		args = append(args, releaseDate)
+		args = append(args, *filter.ReleaseDate)
+	}
+	if filter.ReleasedFrom != nil {
+		conditions = append(conditions, "s.release_date >= $"+strconv.Itoa(len(args)+1)) // Вышли не раньше даты
+		args = append(args, *filter.ReleasedFrom)
 	}
-	if text != "" {
+	if filter.ReleasedTo != nil {
+		conditions = append(conditions, "s.release_date <= $"+strconv.Itoa(len(args)+1)) // Вышли не позже даты
+		args = append(args, *filter.ReleasedTo)
+	}
+	if filter.Year != 0 {
+		conditions = append(conditions, "EXTRACT(YEAR FROM s.release_date) = $"+strconv.Itoa(len(args)+1)) // Фильтрация по году выхода
+		args = append(args, filter.Year)
+	}
+	if filter.Text != "" {
 		conditions = append(conditions, "s.text ILIKE $"+strconv.Itoa(len(args)+1)) // Фильтрация по тексту песни
-		args = append(args, "%"+text+"%")
+		args = append(args, "%"+filter.Text+"%")
+	}
+	if filter.Album != "" {
+		conditions = append(conditions, "a.title ILIKE $"+strconv.Itoa(len(args)+1)) // Фильтрация по названию альбома
+		args = append(args, "%"+filter.Album+"%")
+	}
+	if filter.AlbumID != 0 {
+		conditions = append(conditions, "s.album_id = $"+strconv.Itoa(len(args)+1)) // Фильтрация по ID альбома
+		args = append(args, filter.AlbumID)
+	}
+	if filter.Language != "" {
+		var condition string
+		condition, args = languageCondition(filter.Language, args) // Фильтрация по языку текста
+		conditions = append(conditions, condition)
+	}
+	if len(filter.Genres) > 0 {
+		var genreConds []string
+		genreConds, args = genreConditions(filter.Genres, filter.GenreMatch, args) // Фильтрация по жанрам с поджанрами
+		conditions = append(conditions, genreConds...)
+	}
+	if len(filter.Tags) > 0 {
+		var tagConds []string
+		tagConds, args = tagConditions(filter.Tags, filter.TagMatch, args) // Фильтрация по меткам
+		conditions = append(conditions, tagConds...)
 	}
 
-	// Добавляем условия, если они есть
-	if len(conditions) > 0 {
-		conditionString := " WHERE " + strings.Join(conditions, " AND ")
-		baseQuery += conditionString
-		baseCountQuery += conditionString
+	return conditions, args
+}
+
+// librarySortColumns выражения SQL для разрешенных полей сортировки
+var librarySortColumns = map[string]string{
+	"id":           "s.id",
+	"group":        "g.name",
+	"song":         "s.song_name",
+	"release_date": "s.release_date",
+	"created_at":   "s.created_at",
+	"updated_at":   "s.updated_at",
+}
+
+// normalizeLibrarySort оставляет только разрешенные поля и делает порядок однозначным:
+// ключи после id отбрасываются, без id в конец добавляется id по возрастанию
+func normalizeLibrarySort(sort []models.SortField) []models.SortField {
+	normalized := []models.SortField{}
+	for _, field := range sort {
+		if _, ok := librarySortColumns[field.Field]; !ok {
+			continue
+		}
+		normalized = append(normalized, field)
+
+		// id уникален, сортировать после него бессмысленно
+		if field.Field == "id" {
+			return normalized
+		}
 	}
 
-	// Добавляем сортировку и пагинацию
-	baseQuery += " ORDER BY s.id LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
-	args = append(args, limit, offset)
+	return append(normalized, models.SortField{Field: "id"})
+}
 
-	// Выполняем запрос на выборку данных
-	if err := r.db.SelectContext(ctx, &songs, baseQuery, args...); err != nil {
-		return nil, 0, fmt.Errorf("failed to fetch songs: %w", err)
+// buildLibraryOrder собирает ORDER BY из нормализованной сортировки, песни без даты всегда в конце
+func buildLibraryOrder(sort []models.SortField) string {
+	order := []string{}
+	for _, field := range sort {
+		direction := " ASC"
+		if field.Desc {
+			direction = " DESC"
+		}
+		order = append(order, librarySortColumns[field.Field]+direction+" NULLS LAST")
 	}
 
-	// Выполняем запрос на подсчет общего количества записей
-	if err := r.db.GetContext(ctx, &total, baseCountQuery, args[:len(args)-2]...); err != nil {
-		return nil, 0, fmt.Errorf("failed to fetch total count: %w", err)
+	return " ORDER BY " + strings.Join(order, ", ")
+}
+
+// librarySelectColumns колонки для полей параметра fields
+var librarySelectColumns = map[string]string{
+	"id":           "s.id",
+	"group":        "s.group_id, g.name AS group_name",
+	"song":         "s.song_name",
+	"release_date": "s.release_date",
+	"text":         "s.text",
+	"link":         "s.link",
+	"album":        "s.album_id, a.title AS album_title, s.track_number",
+	"source":       "s.source",
+	"language":     "s.language, s.language_confidence, s.language_manual",
+	"status":       "s.status, s.enrich_error",
+	"created_at":   "s.created_at",
+	"updated_at":   "s.updated_at",
+}
+
+// buildLibrarySelect список колонок для запрошенных полей, без полей - все.
+// Колонки сортировки выбираются всегда, из них строится курсор следующей страницы.
+func buildLibrarySelect(fields []string, sort []models.SortField) string {
+	if len(fields) == 0 {
+		fields = models.LibraryFields
+	}
+
+	selected := map[string]bool{}
+	columns := []string{}
+	add := func(field string) {
+		if column, ok := librarySelectColumns[field]; ok && !selected[field] {
+			selected[field] = true
+			columns = append(columns, column)
+		}
+	}
+
+	for _, field := range fields {
+		add(field)
+	}
+	for _, field := range sort {
+		add(field.Field)
 	}
 
-	return songs, total, nil
+	return strings.Join(columns, ", ")
 }
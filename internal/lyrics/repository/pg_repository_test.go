@@ -1 +1,53 @@
-package repository
\ No newline at end of file
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSongByID_NotFound(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	fake.value = func(query string) (int64, bool) { return 0, false }
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	_, err := repo.GetSongByID(context.Background(), 42)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, sql.ErrNoRows), "unexpected error: %v", err)
+
+	// Песня ищется одним запросом с группой и альбомом, без песни остальные данные не загружаются
+	events := fake.log()
+	require.Len(t, events, 1, events)
+	assert.Contains(t, events[0], "INNER JOIN groups g ON s.group_id = g.id LEFT JOIN albums a ON s.album_id = a.id WHERE s.id = $1")
+}
+
+func TestCreateTrack_SkipsExistingSong(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	fake.value = func(query string) (int64, bool) {
+		if strings.Contains(query, "INSERT INTO songs") {
+			return 0, false
+		}
+		return 7, true
+	}
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	songID, created, err := repo.CreateTrack(context.Background(), models.SongRequest{Group: "Muse", Song: "Uprising"}, models.SongDetail{Text: "new verse"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, uint(7), songID)
+
+	// Текст существующей песни не перезаписывается, участники не меняются
+	events := fake.log()
+	log := strings.Join(events, "\n")
+	assert.Contains(t, log, "ON CONFLICT (group_id, song_key) DO NOTHING")
+	assert.NotContains(t, log, "DO UPDATE")
+	assert.NotContains(t, log, "song_artists")
+	assert.Equal(t, "rollback", events[len(events)-1])
+}
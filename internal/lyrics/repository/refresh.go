@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// GetSongsForRefresh песни, не обновлявшиеся с staleBefore, и песни без текста, ссылки или даты выхода,
+// не проверявшиеся с retryBefore: если в источнике этих данных нет, песня не запрашивается на каждом прогоне.
+// Песни, которые проверялись давнее всего, идут первыми, поэтому неудачные попытки не блокируют остальные.
+// Песни в очереди обогащения (pending) пропускаются.
+func (r lyricsRepo) GetSongsForRefresh(ctx context.Context, staleBefore, retryBefore time.Time, limit int) ([]models.Song, error) {
+	songs := []models.Song{}
+	query := `
+        SELECT s.id, s.group_id, g.name AS group_name, s.song_name
+        FROM songs s
+        INNER JOIN groups g ON s.group_id = g.id
+        WHERE s.status <> $1
+          AND (COALESCE(s.refreshed_at, s.updated_at) < $2
+               OR ((s.text = '' OR s.link IS NULL OR s.link = '' OR s.release_date IS NULL)
+                   AND (s.refreshed_at IS NULL OR s.refreshed_at < $3)))
+        ORDER BY s.refreshed_at NULLS FIRST, s.id
+        LIMIT $4
+    `
+	if err := r.db.SelectContext(ctx, &songs, query, models.SongStatusPending, staleBefore, retryBefore, limit); err != nil {
+		return nil, fmt.Errorf("failed to fetch songs for refresh: %w", err)
+	}
+	return songs, nil
+}
+
+// ApplySongRefresh сохраняет повторно загруженные данные песни и записывает в song_changes измененные поля.
+// Пустые значения из источника не затирают уже сохраненные, а у песни, которую правил редактор,
+// заполняются только пустые поля.
+func (r lyricsRepo) ApplySongRefresh(ctx context.Context, songID uint, songDetail models.SongDetail) ([]models.SongChange, error) {
+	r.logger.Debugf("in repo ApplySongRefresh() song: %d", songID)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "lyricsRepo.ApplySongRefresh.BeginTx")
+	}
+	defer tx.Rollback()
+
+	var current models.Song
+	querySelect := `SELECT id, release_date, text, link, source, edited_at FROM songs WHERE id = $1 FOR UPDATE`
+	if err = tx.GetContext(ctx, &current, querySelect, songID); err != nil {
+		return nil, errors.Wrap(err, "lyricsRepo.ApplySongRefresh.SelectSong")
+	}
+
+	var releaseDate *models.Date
+	if strings.TrimSpace(songDetail.ReleaseDate) != "" {
+		date, err := utils.ParseReleaseDate(songDetail.ReleaseDate)
+		if err != nil {
+			r.logger.Warnf("skipping release date of song %d: %v", songID, err)
+		} else {
+			d := models.NewDate(date)
+			releaseDate = &d
+		}
+	}
+
+	changes := diffSongRefresh(current, songDetail, releaseDate, current.EditedAt != nil)
+	if len(changes) == 0 {
+		if _, err = tx.ExecContext(ctx, `UPDATE songs SET refreshed_at = NOW() WHERE id = $1`, songID); err != nil {
+			return nil, errors.Wrap(err, "lyricsRepo.ApplySongRefresh.Touch")
+		}
+		if err = tx.Commit(); err != nil {
+			return nil, errors.Wrap(err, "lyricsRepo.ApplySongRefresh.Commit")
+		}
+		return nil, nil
+	}
+
+	// В запрос попадают только поля из changes, пустое значение оставляет сохраненное
+	update := models.SongDetail{Language: songDetail.Language}
+	var updateDate *models.Date
+	for _, change := range changes {
+		switch change.Field {
+		case "release_date":
+			updateDate = releaseDate
+		case "text":
+			update.Text = songDetail.Text
+		case "link":
+			update.Link = songDetail.Link
+		case "source":
+			update.Source = songDetail.Source
+		}
+	}
+
+	// Язык меняется только вместе с текстом, заданный вручную язык не меняется
+	queryUpdate := `
+        UPDATE songs
+        SET release_date = COALESCE($1, release_date),
+            text = CASE WHEN $2 = '' THEN text ELSE $2 END,
+            link = COALESCE(NULLIF($3, ''), link),
+            source = COALESCE(NULLIF($4, ''), source),
+            language = CASE WHEN $2 = '' OR language_manual THEN language ELSE $7 END,
+            language_confidence = CASE WHEN $2 = '' OR language_manual THEN language_confidence ELSE $8 END,
+            status = $5, enrich_error = NULL, refreshed_at = NOW(), updated_at = NOW()
+        WHERE id = $6
+    `
+	_, err = tx.ExecContext(ctx, queryUpdate, updateDate, update.Text, update.Link, update.Source, models.SongStatusEnriched, songID,
+		update.Language.Language, update.Language.Confidence)
+	if err != nil {
+		return nil, errors.Wrap(err, "lyricsRepo.ApplySongRefresh.UpdateSong")
+	}
+
+	queryChange := `INSERT INTO song_changes (song_id, field, old_value, new_value, created_at)
+                    VALUES ($1, $2, $3, $4, NOW())
+                    RETURNING id, created_at`
+	for i := range changes {
+		change := &changes[i]
+		if err = tx.QueryRowContext(ctx, queryChange, songID, change.Field, change.OldValue, change.NewValue).Scan(&change.ID, &change.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "lyricsRepo.ApplySongRefresh.InsertChange")
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "lyricsRepo.ApplySongRefresh.Commit")
+	}
+
+	return changes, nil
+}
+
+// MarkSongRefreshed отмечает неудачную проверку, чтобы песня ушла в конец очереди на обновление
+func (r lyricsRepo) MarkSongRefreshed(ctx context.Context, songID uint) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE songs SET refreshed_at = NOW() WHERE id = $1`, songID); err != nil {
+		return errors.Wrap(err, "lyricsRepo.MarkSongRefreshed.ExecContext")
+	}
+	return nil
+}
+
+// diffSongRefresh поля, которые изменятся после обновления; пустые новые значения не считаются изменением.
+// С fillOnly меняются только пустые поля.
+func diffSongRefresh(current models.Song, songDetail models.SongDetail, releaseDate *models.Date, fillOnly bool) []models.SongChange {
+	var changes []models.SongChange
+	add := func(field string, oldValue *string, newValue string) {
+		if newValue == "" || (oldValue != nil && *oldValue == newValue) {
+			return
+		}
+		if fillOnly && oldValue != nil && *oldValue != "" {
+			return
+		}
+		changes = append(changes, models.SongChange{SongID: current.ID, Field: field, OldValue: oldValue, NewValue: &newValue})
+	}
+
+	if releaseDate != nil {
+		var oldDate *string
+		if current.ReleaseDate != nil {
+			value := current.ReleaseDate.String()
+			oldDate = &value
+		}
+		add("release_date", oldDate, releaseDate.String())
+	}
+
+	var oldText *string
+	if current.Text != "" {
+		oldText = &current.Text
+	}
+	add("text", oldText, songDetail.Text)
+	add("link", current.Link, songDetail.Link)
+	add("source", current.Source, songDetail.Source)
+
+	return changes
+}
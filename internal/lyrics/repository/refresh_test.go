@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSongRefresh(t *testing.T) {
+	oldLink, oldSource := "https://old", "http"
+	oldDate := models.NewDate(time.Date(2009, 9, 14, 0, 0, 0, 0, time.UTC))
+	newDate := models.NewDate(time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC))
+	upstream := models.SongDetail{Text: "new text", Link: "https://new", Source: "file"}
+
+	complete := models.Song{ID: 1, ReleaseDate: &oldDate, Text: "old text", Link: &oldLink, Source: &oldSource}
+	incomplete := models.Song{ID: 1, Source: &oldSource}
+
+	tests := []struct {
+		name        string
+		current     models.Song
+		songDetail  models.SongDetail
+		releaseDate *models.Date
+		fillOnly    bool
+		want        []string
+	}{
+		{name: "overwrite changed fields", current: complete, songDetail: upstream, releaseDate: &newDate,
+			want: []string{"release_date", "text", "link", "source"}},
+		{name: "edited song keeps filled fields", current: complete, songDetail: upstream, releaseDate: &newDate, fillOnly: true,
+			want: nil},
+		{name: "edited song gets empty fields", current: incomplete, songDetail: upstream, releaseDate: &newDate, fillOnly: true,
+			want: []string{"release_date", "text", "link"}},
+		{name: "empty upstream values are ignored", current: incomplete, songDetail: models.SongDetail{}, releaseDate: nil,
+			want: nil},
+		{name: "same values are not changes", current: complete,
+			songDetail: models.SongDetail{Text: "old text", Link: oldLink, Source: oldSource}, releaseDate: &oldDate,
+			want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, change := range diffSongRefresh(tt.current, tt.songDetail, tt.releaseDate, tt.fillOnly) {
+				fields = append(fields, change.Field)
+			}
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestUpdateTrackByID_MarksEditorChanges(t *testing.T) {
+	text := "edited text"
+	tests := []struct {
+		name   string
+		update models.UpdateTrackRequest
+		marked bool
+	}{
+		{name: "text", update: models.UpdateTrackRequest{Text: &text}, marked: true},
+		{name: "rename only", update: models.UpdateTrackRequest{}, marked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, nil)
+			repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+			group, song := "Muse", "Uprising"
+			tt.update.ID, tt.update.GroupName, tt.update.SongName = 1, &group, &song
+			require.NoError(t, repo.UpdateTrackByID(context.Background(), tt.update))
+
+			marked := false
+			for _, event := range fake.log() {
+				if strings.Contains(event, "UPDATE songs") && strings.Contains(event, "edited_at = NOW()") {
+					marked = true
+				}
+			}
+			assert.Equal(t, tt.marked, marked)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// searchHeadlineOptions настройки фрагментов ts_headline
+const searchHeadlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" ... "`
+
+// SearchLyrics полнотекстовый поиск по названиям и текстам песен с ранжированием
+func (r lyricsRepo) SearchLyrics(ctx context.Context, query string, offset, limit int) ([]models.SearchResult, int, error) {
+	tsQuery := buildTSQuery(query)
+	if tsQuery == "" {
+		return nil, 0, lyrics.ErrEmptySearchQuery
+	}
+	r.logger.Debugf("in repo SearchLyrics() tsquery: %s", tsQuery)
+
+	searchQuery := `
+        SELECT s.id, g.name AS group_name, s.song_name,
+               ts_rank(s.text_search, q.query) AS rank,
+               ts_headline('simple', s.text, q.query, $2) AS headline
+        FROM songs s
+        INNER JOIN groups g ON s.group_id = g.id
+        CROSS JOIN to_tsquery('simple', $1) AS q(query)
+        WHERE s.text_search @@ q.query
+        ORDER BY rank DESC, s.id
+        LIMIT $3 OFFSET $4
+    `
+	results := []models.SearchResult{}
+	if err := r.db.SelectContext(ctx, &results, searchQuery, tsQuery, searchHeadlineOptions, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to search songs: %w", err)
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM songs s WHERE s.text_search @@ to_tsquery('simple', $1)`
+	if err := r.db.GetContext(ctx, &total, countQuery, tsQuery); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch search total count: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// buildTSQuery переводит пользовательский запрос в синтаксис to_tsquery.
+// Фраза в кавычках ищется как последовательность слов ("set my soul" -> 'set' <-> 'my' <-> 'soul'),
+// слово со звездочкой на конце ищется по префиксу (alig* -> 'alig':*), остальные слова объединяются через &.
+// Из слов удаляется все, кроме букв и цифр, поэтому результат безопасно передавать в to_tsquery.
+func buildTSQuery(query string) string {
+	var terms []string
+
+	// Нечетные части после разбиения по кавычкам находятся внутри фраз
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			if phrase := buildTSPhrase(part); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+
+		for _, word := range strings.Fields(part) {
+			if term := buildTSPhrase(word); term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// buildTSPhrase собирает лексемы фрагмента запроса в цепочку через оператор следования <->
+func buildTSPhrase(fragment string) string {
+	prefix := strings.HasSuffix(strings.TrimSpace(fragment), "*")
+
+	lexemes := strings.FieldsFunc(strings.ToLower(fragment), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(lexemes) == 0 {
+		return ""
+	}
+
+	for i, lexeme := range lexemes {
+		lexemes[i] = "'" + lexeme + "'"
+	}
+	if prefix {
+		lexemes[len(lexemes)-1] += ":*"
+	}
+
+	if len(lexemes) == 1 {
+		return lexemes[0]
+	}
+	return "(" + strings.Join(lexemes, " <-> ") + ")"
+}
+
+// Suggest ищет группы и песни с похожими названиями через pg_trgm.
+// Порог похожести задается на время транзакции, чтобы оператор % мог использовать триграммные индексы;
+// pg_trgm принимает порог от 0 до 1, значения вне диапазона приводятся к границе.
+func (r lyricsRepo) Suggest(ctx context.Context, group, song string, threshold float64, limit int) (models.Suggestions, error) {
+	suggestions := models.Suggestions{Groups: []models.Suggestion{}, Songs: []models.Suggestion{}}
+	// Искать нечего - транзакцию не открываем
+	if group == "" && song == "" {
+		return suggestions, nil
+	}
+	threshold = math.Min(math.Max(threshold, 0), 1)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return suggestions, fmt.Errorf("lyricsRepo.Suggest.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`, strconv.FormatFloat(threshold, 'f', -1, 64)); err != nil {
+		return suggestions, fmt.Errorf("failed to set similarity threshold: %w", err)
+	}
+
+	// Группа находится и по другим написаниям имени, в подсказке всегда основное имя
+	if group != "" {
+		queryGroups := `
+            SELECT m.id, m.name, MAX(m.similarity) AS similarity
+            FROM (
+                SELECT g.id, g.name, similarity(g.name, $1) AS similarity
+                FROM groups g
+                WHERE g.name % $1
+                UNION ALL
+                SELECT g.id, g.name, similarity(ga.alias, $1) AS similarity
+                FROM group_aliases ga
+                INNER JOIN groups g ON ga.group_id = g.id
+                WHERE ga.alias % $1
+            ) m
+            GROUP BY m.id, m.name
+            ORDER BY similarity DESC, m.name
+            LIMIT $2
+        `
+		if err := tx.SelectContext(ctx, &suggestions.Groups, queryGroups, group, limit); err != nil {
+			return suggestions, fmt.Errorf("failed to fetch group suggestions: %w", err)
+		}
+	}
+
+	if song != "" {
+		querySongs := `
+            SELECT s.id, s.song_name AS name, g.name AS group_name, similarity(s.song_name, $1) AS similarity
+            FROM songs s
+            INNER JOIN groups g ON s.group_id = g.id
+            WHERE s.song_name % $1
+            ORDER BY similarity DESC, s.id
+            LIMIT $2
+        `
+		if err := tx.SelectContext(ctx, &suggestions.Songs, querySongs, song, limit); err != nil {
+			return suggestions, fmt.Errorf("failed to fetch song suggestions: %w", err)
+		}
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const setThresholdQuery = "SELECT set_config('pg_trgm.similarity_threshold', $1, true)"
+
+func TestBuildTSQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "words", query: "set my soul", want: "'set' & 'my' & 'soul'"},
+		{name: "case is folded", query: "Set MY Soul", want: "'set' & 'my' & 'soul'"},
+		{name: "quoted phrase", query: `"set my soul"`, want: "('set' <-> 'my' <-> 'soul')"},
+		{name: "phrase and words", query: `muse "knights of cydonia" live`, want: "'muse' & ('knights' <-> 'of' <-> 'cydonia') & 'live'"},
+		{name: "single word phrase", query: `"uprising"`, want: "'uprising'"},
+		{name: "unclosed quote is a phrase", query: `"set my`, want: "('set' <-> 'my')"},
+		{name: "prefix", query: "alig*", want: "'alig':*"},
+		{name: "prefix inside words", query: "muse alig* soul", want: "'muse' & 'alig':* & 'soul'"},
+		{name: "prefix on last word of phrase", query: `"set my so*"`, want: "('set' <-> 'my' <-> 'so':*)"},
+		{name: "leading star is not a prefix", query: "*alig", want: "'alig'"},
+		{name: "lone star", query: "*", want: ""},
+		{name: "operator characters are dropped", query: "a & b | !c <-> (d)", want: "'a' & 'b' & 'c' & 'd'"},
+		{name: "tsquery syntax in a word", query: "d:* e:A", want: "'d':* & ('e' <-> 'a')"},
+		{name: "apostrophe splits the word", query: "it's", want: "('it' <-> 's')"},
+		{name: "quote characters cannot escape", query: `x') | ('y`, want: "'x' & 'y'"},
+		{name: "unicode letters and digits", query: "Любовь 1984", want: "'любовь' & '1984'"},
+		{name: "empty", query: "", want: ""},
+		{name: "whitespace", query: " \t\n ", want: ""},
+		{name: "empty phrase", query: `""`, want: ""},
+		{name: "whitespace phrase", query: `"   "`, want: ""},
+		{name: "only operators", query: "& | ! <->", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildTSQuery(tt.query))
+		})
+	}
+}
+
+func TestSuggest_SetsThresholdInsideTransaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		group, song string
+		queries     []string
+	}{
+		{name: "group and song", group: "Muse", song: "Uprsing", queries: []string{"SELECT m.id, m.name", "SELECT s.id, s.song_name"}},
+		{name: "group only", group: "Muse", queries: []string{"SELECT m.id, m.name"}},
+		{name: "song only", song: "Uprsing", queries: []string{"SELECT s.id, s.song_name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, nil)
+			repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+			_, err := repo.Suggest(context.Background(), tt.group, tt.song, 0.3, 5)
+			require.NoError(t, err)
+
+			// Порог задается set_config(..., true) и действует только до конца этой транзакции
+			log := fake.log()
+			require.Len(t, log, len(tt.queries)+3, log)
+			assert.Equal(t, "begin", log[0])
+			assert.Equal(t, "tx: "+setThresholdQuery, log[1])
+			for i, query := range tt.queries {
+				assert.True(t, strings.HasPrefix(log[i+2], "tx: "+query), log[i+2])
+			}
+			assert.Equal(t, "rollback", log[len(log)-1])
+		})
+	}
+}
+
+func TestSuggest_ClampsThreshold(t *testing.T) {
+	tests := []struct {
+		threshold float64
+		want      string
+	}{
+		{threshold: 0.3, want: "0.3"},
+		{threshold: 0, want: "0"},
+		{threshold: 1, want: "1"},
+		{threshold: -0.5, want: "0"},
+		{threshold: 1.7, want: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			db, fake := newFakeDB(t, nil)
+			repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+			_, err := repo.Suggest(context.Background(), "Muse", "", tt.threshold, 5)
+			require.NoError(t, err)
+
+			args := fake.argsOf(setThresholdQuery)
+			require.Len(t, args, 1)
+			assert.Equal(t, tt.want, args[0].Value)
+		})
+	}
+}
+
+func TestSuggest_EmptyQuery(t *testing.T) {
+	db, fake := newFakeDB(t, nil)
+	repo := lyricsRepo{db: db, logger: utils.CreateTestLogger()}
+
+	suggestions, err := repo.Suggest(context.Background(), "", "", 0.3, 5)
+	require.NoError(t, err)
+
+	assert.True(t, suggestions.Empty())
+	assert.NotNil(t, suggestions.Groups)
+	assert.NotNil(t, suggestions.Songs)
+	assert.Empty(t, fake.log())
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// tagInfoColumns колонки метки с количеством песен, запрос должен содержать псевдоним t (tags)
+const tagInfoColumns = `t.id, t.name, t.created_at,
+                     (SELECT COUNT(*) FROM song_tags st WHERE st.tag_id = t.id) AS song_count`
+
+// tagFacetLimit сколько самых частых меток попадает в фасет библиотеки
+const tagFacetLimit = 50
+
+// tagConditions условия фильтра по меткам: с match = all песня должна иметь каждую из меток, иначе - хотя бы одну
+func tagConditions(tags []string, match string, args []interface{}) ([]string, []interface{}) {
+	keys := nameKeys(tags)
+	if len(keys) == 0 {
+		return nil, args
+	}
+
+	groups := [][]string{keys}
+	if match == models.MatchAll {
+		groups = groups[:0]
+		for _, key := range keys {
+			groups = append(groups, []string{key})
+		}
+	}
+
+	var conditions []string
+	for _, group := range groups {
+		var in string
+		in, args = listPlaceholders(args, group)
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM song_tags st INNER JOIN tags t ON st.tag_id = t.id
+                    WHERE st.song_id = s.id AND t.name_key IN (`+in+`))`)
+	}
+	return conditions, args
+}
+
+// tagFacet самые частые метки песен, подходящих под остальные фильтры
+func (r lyricsRepo) tagFacet(ctx context.Context, filter models.LibraryFilter) ([]models.FacetCount, error) {
+	facet := []models.FacetCount{}
+
+	filter.Tags = nil
+	conditions, args := buildLibraryConditions(filter)
+	query := `SELECT t.name AS value, COUNT(*) AS count
+              FROM songs s
+              INNER JOIN groups g ON s.group_id = g.id
+              LEFT JOIN albums a ON s.album_id = a.id
+              INNER JOIN song_tags st ON st.song_id = s.id
+              INNER JOIN tags t ON t.id = st.tag_id` + whereClause(conditions) + `
+              GROUP BY t.name
+              ORDER BY count DESC, value
+              LIMIT ` + strconv.Itoa(tagFacetLimit)
+	if err := r.db.SelectContext(ctx, &facet, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to fetch tag facet: %w", err)
+	}
+	return facet, nil
+}
+
+// GetTags список меток с количеством песен, с фильтром по имени и пагинацией, сначала самые частые
+func (r lyricsRepo) GetTags(ctx context.Context, name string, offset, limit int) ([]models.TagInfo, int, error) {
+	tags := []models.TagInfo{}
+	var total int
+
+	condition := ""
+	args := []interface{}{}
+	if name != "" {
+		condition = " WHERE t.name ILIKE $1"
+		args = append(args, "%"+name+"%")
+	}
+
+	query := `SELECT ` + tagInfoColumns + `
+              FROM tags t` + condition +
+		" ORDER BY song_count DESC, t.name, t.id LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
+	if err := r.db.SelectContext(ctx, &tags, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM tags t` + condition
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch tags total count: %w", err)
+	}
+
+	return tags, total, nil
+}
+
+// DeleteTagByID удаляет метку у всех песен
+func (r lyricsRepo) DeleteTagByID(ctx context.Context, id uint) error {
+	r.logger.Debugf("in repo DeleteTagByID() id: %d", id)
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteTagByID.ExecContext")
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteTagByID.RowsAffected")
+	}
+	if deleted == 0 {
+		return errors.Wrap(sql.ErrNoRows, "tag not found")
+	}
+	return nil
+}
+
+// AttachTags добавляет метки песням, отсутствующие метки создаются, уже добавленные пропускаются.
+// Возвращает количество новых связей.
+func (r lyricsRepo) AttachTags(ctx context.Context, songIDs []uint, tags []string) (int, error) {
+	r.logger.Debugf("in repo AttachTags() songs: %v, tags: %v", songIDs, tags)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachTags.BeginTx")
+	}
+	defer tx.Rollback()
+
+	missing, err := firstMissingID(ctx, tx, "songs", songIDs)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachTags.CheckSongs")
+	}
+	if missing != 0 {
+		return 0, errors.Wrapf(sql.ErrNoRows, "song %d not found", missing)
+	}
+
+	// Метка сохраняет написание, с которым ее добавили впервые
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		key := utils.NameKey(tag)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+
+		queryTag := `INSERT INTO tags (name, name_key, created_at) VALUES ($1, $2, NOW()) ON CONFLICT (name_key) DO NOTHING`
+		if _, err = tx.ExecContext(ctx, queryTag, utils.NormalizeName(tag), key); err != nil {
+			return 0, errors.Wrap(err, "lyricsRepo.AttachTags.InsertTag")
+		}
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	query, args, err := sqlx.In(`INSERT INTO song_tags (song_id, tag_id, created_at)
+              SELECT s.id, t.id, NOW() FROM songs s CROSS JOIN tags t
+              WHERE s.id IN (?) AND t.name_key IN (?)
+              ON CONFLICT DO NOTHING`, songIDs, keys)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachTags.In")
+	}
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachTags.Insert")
+	}
+	attached, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachTags.RowsAffected")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.AttachTags.Commit")
+	}
+	return int(attached), nil
+}
+
+// DetachTags убирает метки у песен, возвращает количество удаленных связей. Сами метки остаются.
+func (r lyricsRepo) DetachTags(ctx context.Context, songIDs []uint, tags []string) (int, error) {
+	r.logger.Debugf("in repo DetachTags() songs: %v, tags: %v", songIDs, tags)
+
+	keys := nameKeys(tags)
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	query, args, err := sqlx.In(`DELETE FROM song_tags
+              WHERE song_id IN (?) AND tag_id IN (SELECT id FROM tags WHERE name_key IN (?))`, songIDs, keys)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.DetachTags.In")
+	}
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.DetachTags.Delete")
+	}
+	detached, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "lyricsRepo.DetachTags.RowsAffected")
+	}
+	return int(detached), nil
+}
+
+// loadSongTags названия меток песни
+func (r lyricsRepo) loadSongTags(ctx context.Context, song *models.Song) error {
+	song.Tags = []string{}
+	query := `SELECT t.name FROM song_tags st
+              INNER JOIN tags t ON st.tag_id = t.id
+              WHERE st.song_id = $1
+              ORDER BY t.name`
+	if err := r.db.SelectContext(ctx, &song.Tags, query, song.ID); err != nil {
+		return errors.Wrap(err, "lyricsRepo.loadSongTags.SelectContext")
+	}
+	return nil
+}
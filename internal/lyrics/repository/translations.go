@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/pkg/errors"
+)
+
+// SaveTranslation добавляет или заменяет перевод песни на язык, created - перевода раньше не было
+func (r lyricsRepo) SaveTranslation(ctx context.Context, songID uint, language, text string) (models.SongTranslation, bool, error) {
+	r.logger.Debugf("in repo SaveTranslation() songID: %d, language: %s", songID, language)
+
+	var saved struct {
+		models.SongTranslation
+		Created bool `db:"created"`
+	}
+
+	// Песни нет - ошибка sql.ErrNoRows, а не нарушение внешнего ключа
+	query := `
+        INSERT INTO song_translations (song_id, language, text, created_at, updated_at)
+        SELECT s.id, $2, $3, NOW(), NOW() FROM songs s WHERE s.id = $1
+        ON CONFLICT (song_id, language) DO UPDATE SET text = EXCLUDED.text, updated_at = NOW()
+        RETURNING id, song_id, language, text, created_at, updated_at, (xmax = 0) AS created
+    `
+	if err := r.db.GetContext(ctx, &saved, query, songID, language, text); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.SongTranslation{}, false, errors.Wrap(sql.ErrNoRows, "song not found")
+		}
+		return models.SongTranslation{}, false, errors.Wrap(err, "lyricsRepo.SaveTranslation.GetContext")
+	}
+
+	return saved.SongTranslation, saved.Created, nil
+}
+
+// GetTranslations переводы песни по коду языка
+func (r lyricsRepo) GetTranslations(ctx context.Context, songID uint) ([]models.SongTranslation, error) {
+	translations := []models.SongTranslation{}
+	query := `SELECT id, song_id, language, text, created_at, updated_at
+              FROM song_translations
+              WHERE song_id = $1
+              ORDER BY language`
+	if err := r.db.SelectContext(ctx, &translations, query, songID); err != nil {
+		return nil, errors.Wrap(err, "lyricsRepo.GetTranslations.SelectContext")
+	}
+
+	return translations, nil
+}
+
+// GetTranslation перевод песни на язык, sql.ErrNoRows - перевода нет
+func (r lyricsRepo) GetTranslation(ctx context.Context, songID uint, language string) (models.SongTranslation, error) {
+	var translation models.SongTranslation
+	query := `SELECT id, song_id, language, text, created_at, updated_at
+              FROM song_translations
+              WHERE song_id = $1 AND language = $2`
+	if err := r.db.GetContext(ctx, &translation, query, songID, language); err != nil {
+		return models.SongTranslation{}, errors.Wrap(err, "lyricsRepo.GetTranslation.GetContext")
+	}
+
+	return translation, nil
+}
+
+// DeleteTranslation удаляет перевод песни на язык
+func (r lyricsRepo) DeleteTranslation(ctx context.Context, songID uint, language string) error {
+	r.logger.Debugf("in repo DeleteTranslation() songID: %d, language: %s", songID, language)
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM song_translations WHERE song_id = $1 AND language = $2`, songID, language)
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteTranslation.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "lyricsRepo.DeleteTranslation.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "translation not found")
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ package lyrics
 
 import (
 	"context"
+	"io"
 
 	"github.com/22Fariz22/musiclab/internal/models"
 )
@@ -11,8 +12,58 @@ import (
 type UseCase interface {
 	DeleteSongByID(ctx context.Context, ID uint) error
 	UpdateTrackByID(ctx context.Context, updateData models.UpdateTrackRequest) error
-	CreateTrack(ctx context.Context, song models.SongRequest) (models.SongDetail, error)
+	CreateTrack(ctx context.Context, song models.SongRequest) (models.EnrichmentJob, bool, error)
+	ProcessEnrichmentJob(ctx context.Context) (bool, error)
+	GetEnrichmentJob(ctx context.Context, id uint) (models.EnrichmentJob, error)
+	RefreshStaleSongs(ctx context.Context, limit int) (models.RefreshReport, error)
+	TriggerRefresh(ctx context.Context, limit int) (<-chan struct{}, error)
+	LastRefresh() (models.RefreshReport, bool)
+	ImportSongs(ctx context.Context, format string, r io.Reader, opts models.ImportOptions) (models.ImportReport, error)
 	Ping() error
-	GetSongVerseByID(ctx context.Context, id uint, page int) (string, error)
-	GetLibrary(ctx context.Context, group, song, text, releaseDate string, page, limit int) ([]models.Song, int, error)
+	Health(ctx context.Context) models.Health
+	GetSongByID(ctx context.Context, id uint, lang string) (models.SongInfo, error)
+	GetSongVerseByID(ctx context.Context, id uint, page int, paging models.VersePaging, lang string) (models.Verse, error)
+	GetSongVerses(ctx context.Context, id uint, paging models.VersePaging, lang string) (models.SongVerses, error)
+	SaveTranslation(ctx context.Context, songID uint, lang, text string) (models.SongTranslation, bool, error)
+	GetTranslations(ctx context.Context, songID uint) ([]models.SongTranslation, error)
+	GetTranslation(ctx context.Context, songID uint, lang string) (models.SongTranslation, error)
+	DeleteTranslation(ctx context.Context, songID uint, lang string) error
+	GetSongSections(ctx context.Context, id uint) (models.SongSections, error)
+	GetSongLRC(ctx context.Context, id uint) (models.SongLRC, error)
+	SetSongLRC(ctx context.Context, id uint, lrc string) (models.SongLRC, error)
+	DeleteSongLRC(ctx context.Context, id uint) error
+	GetLibrary(ctx context.Context, filter models.LibraryFilter) (models.LibraryPage, error)
+	ExportLibrary(ctx context.Context, filter models.LibraryFilter, fn func(models.Song) error) error
+	SearchLyrics(ctx context.Context, query string, page, limit int) ([]models.SearchResult, int, error)
+	Suggest(ctx context.Context, group, song string) (models.Suggestions, error)
+
+	CreateAlbum(ctx context.Context, album models.AlbumRequest) (models.Album, error)
+	GetAlbums(ctx context.Context, group string, page, limit int) ([]models.Album, int, error)
+	GetAlbumByID(ctx context.Context, id uint) (models.Album, error)
+	UpdateAlbumByID(ctx context.Context, id uint, album models.AlbumRequest) error
+	DeleteAlbumByID(ctx context.Context, id uint) error
+	AttachSongsToAlbum(ctx context.Context, albumID uint, tracks []models.AlbumTrackRequest) error
+	DetachSongFromAlbum(ctx context.Context, albumID, songID uint) error
+
+	CreateGroup(ctx context.Context, group models.GroupRequest) (models.GroupInfo, error)
+	GetGroups(ctx context.Context, name string, page, limit int) ([]models.GroupInfo, int, error)
+	GetGroupByID(ctx context.Context, id uint) (models.GroupInfo, error)
+	UpdateGroupByID(ctx context.Context, id uint, group models.GroupRequest) (models.GroupInfo, error)
+	DeleteGroupByID(ctx context.Context, id uint, cascade bool) (models.GroupDeleteResult, error)
+	CleanupOrphanGroups(ctx context.Context) (int, error)
+	AddGroupAlias(ctx context.Context, groupID uint, alias string) (models.GroupInfo, error)
+	DeleteGroupAlias(ctx context.Context, groupID uint, alias string) error
+	MergeGroups(ctx context.Context, sourceID uint, merge models.GroupMergeRequest) (models.GroupMergeResult, error)
+
+	GetGenres(ctx context.Context) ([]models.GenreInfo, error)
+	CreateGenre(ctx context.Context, genre models.GenreRequest) (models.GenreInfo, error)
+	UpdateGenreByID(ctx context.Context, id uint, genre models.GenreRequest) (models.GenreInfo, error)
+	DeleteGenreByID(ctx context.Context, id uint) error
+	AttachGenres(ctx context.Context, request models.SongGenresRequest) (models.ClassificationResult, error)
+	DetachGenres(ctx context.Context, request models.SongGenresRequest) (models.ClassificationResult, error)
+
+	GetTags(ctx context.Context, name string, page, limit int) ([]models.TagInfo, int, error)
+	DeleteTagByID(ctx context.Context, id uint) error
+	AttachTags(ctx context.Context, request models.SongTagsRequest) (models.ClassificationResult, error)
+	DetachTags(ctx context.Context, request models.SongTagsRequest) (models.ClassificationResult, error)
 }
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+func (u lyricsUseCase) CreateAlbum(ctx context.Context, album models.AlbumRequest) (models.Album, error) {
+	u.logger.Debugf("in usecase CreateAlbum() group=%s, title=%s", album.Group, album.Title)
+	return u.lyricsRepo.CreateAlbum(ctx, album)
+}
+
+func (u lyricsUseCase) GetAlbums(ctx context.Context, group string, page, limit int) ([]models.Album, int, error) {
+	u.logger.Debugf("in usecase GetAlbums() group=%s, page=%d, limit=%d", group, page, limit)
+
+	offset := (page - 1) * limit
+
+	albums, total, err := u.lyricsRepo.GetAlbums(ctx, group, offset, limit)
+	if err != nil {
+		u.logger.Errorf("Error fetching albums from repository: %v", err)
+		return nil, 0, err
+	}
+
+	return albums, total, nil
+}
+
+func (u lyricsUseCase) GetAlbumByID(ctx context.Context, id uint) (models.Album, error) {
+	u.logger.Debugf("in usecase GetAlbumByID() ID:%d", id)
+	return u.lyricsRepo.GetAlbumByID(ctx, id)
+}
+
+func (u lyricsUseCase) UpdateAlbumByID(ctx context.Context, id uint, album models.AlbumRequest) error {
+	u.logger.Debugf("in usecase UpdateAlbumByID() ID:%d", id)
+	return u.lyricsRepo.UpdateAlbumByID(ctx, id, album)
+}
+
+func (u lyricsUseCase) DeleteAlbumByID(ctx context.Context, id uint) error {
+	u.logger.Debugf("in usecase DeleteAlbumByID() ID:%d", id)
+	return u.lyricsRepo.DeleteAlbumByID(ctx, id)
+}
+
+func (u lyricsUseCase) AttachSongsToAlbum(ctx context.Context, albumID uint, tracks []models.AlbumTrackRequest) error {
+	u.logger.Debugf("in usecase AttachSongsToAlbum() albumID:%d, tracks:%d", albumID, len(tracks))
+	return u.lyricsRepo.AttachSongsToAlbum(ctx, albumID, tracks)
+}
+
+func (u lyricsUseCase) DetachSongFromAlbum(ctx context.Context, albumID, songID uint) error {
+	u.logger.Debugf("in usecase DetachSongFromAlbum() albumID:%d, songID:%d", albumID, songID)
+	return u.lyricsRepo.DetachSongFromAlbum(ctx, albumID, songID)
+}
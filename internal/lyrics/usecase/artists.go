@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+)
+
+// songCredits отделяет участников после feat./ft. в именах группы и песни и добавляет их к переданным явно.
+// Возвращает группу и название песни без приглашенных участников. Участник без роли считается приглашенным (featured),
+// повторы и сама группа в роли исполнителя отбрасываются. nil - участники не указаны ни явно, ни в именах.
+func songCredits(group, song string, artists []models.ArtistCredit) (string, string, []models.ArtistCredit) {
+	group, groupFeatured := utils.SplitFeaturing(group)
+	song, songFeatured := utils.SplitFeaturing(song)
+
+	if artists == nil && groupFeatured == nil && songFeatured == nil {
+		return group, song, nil
+	}
+
+	credits := []models.ArtistCredit{}
+	seen := map[string]bool{utils.NameKey(group) + "\x00" + models.ArtistRolePrimary: true}
+	add := func(credit models.ArtistCredit) {
+		credit.Name = utils.NormalizeName(credit.Name)
+		if credit.Role == "" {
+			credit.Role = models.ArtistRoleFeatured
+		}
+
+		key := utils.NameKey(credit.Name) + "\x00" + credit.Role
+		if credit.Name == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		credits = append(credits, credit)
+	}
+
+	for _, artist := range artists {
+		add(artist)
+	}
+	for _, name := range append(groupFeatured, songFeatured...) {
+		add(models.ArtistCredit{Name: name, Role: models.ArtistRoleFeatured})
+	}
+
+	return group, song, credits
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// songTextCacheKey ключ, под которым в Redis лежит полный текст песни
+func songTextCacheKey(id uint) string {
+	return fmt.Sprintf("song:%d", id)
+}
+
+// songLRCCacheKey ключ синхронизированного текста песни, пустое значение - у песни нет LRC
+func songLRCCacheKey(id uint) string {
+	return fmt.Sprintf("song:%d:lrc", id)
+}
+
+// songCacheKeys все ключи кэша, которые зависят от данных песни.
+// Новые производные данные (куплеты, разметка и т.п.) нужно регистрировать здесь,
+// иначе они не будут сбрасываться при изменении песни.
+func songCacheKeys(id uint) []string {
+	return []string{
+		songTextCacheKey(id),
+		songLRCCacheKey(id),
+	}
+}
+
+// invalidateSongCache удаляет из кэша текст песни и все производные от него данные
+func (u lyricsUseCase) invalidateSongCache(ctx context.Context, id uint) {
+	keys := songCacheKeys(id)
+
+	if err := u.redisClient.Del(ctx, keys...).Err(); err != nil {
+		u.logger.Errorf("Error invalidating song cache %v: %v", keys, err)
+		return
+	}
+
+	u.logger.Debugf("song cache invalidated: %v", keys)
+}
+
+// refreshSongCache перезаписывает текст песни в кэше и сбрасывает производные данные
+func (u lyricsUseCase) refreshSongCache(ctx context.Context, id uint, text string) {
+	u.invalidateSongCache(ctx, id)
+
+	if err := u.redisClient.Set(ctx, songTextCacheKey(id), text, u.cfg.Redis.SongTextCasheTTL).Err(); err != nil {
+		u.logger.Errorf("Error caching song in Redis: %v", err)
+	}
+}
+
+// songLyrics текст и LRC песни из кэша, при промахе по любому из ключей оба читаются из базы и кэшируются
+func (u lyricsUseCase) songLyrics(ctx context.Context, id uint) (string, string, error) {
+	text, err := u.redisClient.Get(ctx, songTextCacheKey(id)).Result()
+	if err != nil && err != redis.Nil {
+		u.logger.Errorf("Error fetching from Redis: %v", err)
+	}
+	textCached := err == nil && text != ""
+
+	lrc, err := u.redisClient.Get(ctx, songLRCCacheKey(id)).Result()
+	if err != nil && err != redis.Nil {
+		u.logger.Errorf("Error fetching from Redis: %v", err)
+	}
+	// Пустой LRC в кэше - песня без синхронизированного текста, а не промах
+	lrcCached := err == nil
+
+	if textCached && lrcCached {
+		u.logger.Debugf("Cache hit for song %d", id)
+		return text, lrc, nil
+	}
+
+	u.logger.Debugf("Cache miss for song %d. Fetching from database.", id)
+
+	song, err := u.lyricsRepo.GetSongByID(ctx, id)
+	if err != nil {
+		u.logger.Debugf("error in uc u.lyricsRepo.GetSongByID():%v", err)
+		return "", "", fmt.Errorf("failed to get song from database: %w", err)
+	}
+
+	lrc = ""
+	if song.LRC != nil {
+		lrc = *song.LRC
+	}
+
+	for key, value := range map[string]string{songTextCacheKey(id): song.Text, songLRCCacheKey(id): lrc} {
+		if err := u.redisClient.Set(ctx, key, value, u.cfg.Redis.SongTextCasheTTL).Err(); err != nil {
+			u.logger.Errorf("Error caching song in Redis: %v", err)
+		}
+	}
+
+	return song.Text, lrc, nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// CreateTrack сохраняет песню в статусе pending и ставит в очередь загрузку ее данных из источников.
+// Данные загружает воркер через ProcessEnrichmentJob, статус задачи доступен по ее ID.
+// Уже существующая песня не меняется: false и задача, в которой заполнен только SongID.
+func (u lyricsUseCase) CreateTrack(ctx context.Context, songRequest models.SongRequest) (models.EnrichmentJob, bool, error) {
+	u.logger.Debug("in usecase CreateTrack()\n")
+
+	songRequest.Group, songRequest.Song, songRequest.Artists = songCredits(songRequest.Group, songRequest.Song, songRequest.Artists)
+
+	job, created, err := u.lyricsRepo.CreatePendingSong(ctx, songRequest)
+	if err != nil {
+		u.logger.Errorf("failed to save pending track: %v", err)
+		return models.EnrichmentJob{}, false, fmt.Errorf("saving track: %w", err)
+	}
+	if !created {
+		u.logger.Infof("track %d already exists, skipped", job.SongID)
+		return job, false, nil
+	}
+
+	u.logger.Infof("track %d queued for enrichment, job %d", job.SongID, job.ID)
+	return job, true, nil
+}
+
+// defaultEnrichmentMaxAttempts попыток задачи, если ENRICHMENT_MAX_ATTEMPTS не положительный
+const defaultEnrichmentMaxAttempts = 3
+
+// ProcessEnrichmentJob берет из очереди одну задачу и загружает данные песни.
+// false - очередь пуста. Ошибка источника не возвращается, а сохраняется в песне и задаче.
+func (u lyricsUseCase) ProcessEnrichmentJob(ctx context.Context) (bool, error) {
+	maxAttempts := u.cfg.Enrichment.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultEnrichmentMaxAttempts
+	}
+
+	job, err := u.lyricsRepo.ClaimEnrichmentJob(ctx, u.cfg.Enrichment.JobTimeout, maxAttempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	u.logger.Debugf("in usecase ProcessEnrichmentJob() job: %d, song: %d, attempt: %d", job.ID, job.SongID, job.Attempts)
+
+	song, err := u.lyricsRepo.GetSongByID(ctx, job.SongID)
+	if err != nil {
+		// Песню удалили, пока задача ждала в очереди
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, u.skipLostJob(job, u.lyricsRepo.FailEnrichmentJob(ctx, job, "song was deleted"))
+		}
+		return true, err
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, u.cfg.API.APICtxTimeout)
+	songDetail, err := u.lyricsProvider.Fetch(fetchCtx, song.GroupName, song.SongName)
+	cancel()
+	if err != nil {
+		// Сервис останавливается - задачу заберут заново после ENRICHMENT_JOB_TIMEOUT
+		if ctx.Err() != nil {
+			return true, ctx.Err()
+		}
+
+		u.logger.Warnf("enrichment of track %d failed: %v", job.SongID, err)
+		if err := u.skipLostJob(job, u.lyricsRepo.FailEnrichmentJob(ctx, job, err.Error())); err != nil {
+			return true, fmt.Errorf("saving enrichment failure: %w", err)
+		}
+		return true, nil
+	}
+
+	songDetail.Language = u.detectLanguage(songDetail.Text)
+
+	if err := u.lyricsRepo.CompleteEnrichmentJob(ctx, job, songDetail); err != nil {
+		if err = u.skipLostJob(job, err); err != nil {
+			return true, fmt.Errorf("saving enriched track: %w", err)
+		}
+		return true, nil
+	}
+
+	// Текст песни, который сохранил редактор, остается - кэш заполнится при следующем чтении
+	u.invalidateSongCache(ctx, job.SongID)
+
+	u.logger.Infof("track %d enriched from %s", job.SongID, songDetail.Source)
+	return true, nil
+}
+
+// skipLostJob результат задачи, которую уже забрал другой воркер, не сохраняется и ошибкой не считается
+func (u lyricsUseCase) skipLostJob(job models.EnrichmentJob, err error) error {
+	if errors.Is(err, lyrics.ErrEnrichmentJobLost) {
+		u.logger.Warnf("enrichment job %d attempt %d was reclaimed, result dropped", job.ID, job.Attempts)
+		return nil
+	}
+	return err
+}
+
+// GetEnrichmentJob статус задачи обогащения
+func (u lyricsUseCase) GetEnrichmentJob(ctx context.Context, id uint) (models.EnrichmentJob, error) {
+	u.logger.Debugf("in usecase GetEnrichmentJob() ID:%d", id)
+
+	return u.lyricsRepo.GetEnrichmentJob(ctx, id)
+}
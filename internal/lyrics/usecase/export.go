@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// defaultExportFetchSize размер порции курсора, если EXPORT_FETCH_SIZE не положительный
+const defaultExportFetchSize = 1000
+
+// ExportLibrary передает в fn все песни библиотеки, подходящие под фильтры; пагинация фильтра не учитывается
+func (u lyricsUseCase) ExportLibrary(ctx context.Context, filter models.LibraryFilter, fn func(models.Song) error) error {
+	u.logger.Debugf("in usecase ExportLibrary() filter: %+v", filter)
+
+	fetchSize := u.cfg.Export.FetchSize
+	if fetchSize <= 0 {
+		fetchSize = defaultExportFetchSize
+	}
+
+	if err := u.lyricsRepo.ExportLibrary(ctx, filter, fetchSize, fn); err != nil {
+		u.logger.Errorf("Error exporting library: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// GetGenres дерево жанров, на каждом уровне по алфавиту
+func (u lyricsUseCase) GetGenres(ctx context.Context) ([]models.GenreInfo, error) {
+	u.logger.Debugf("in usecase GetGenres()")
+
+	genres, err := u.lyricsRepo.GetGenres(ctx)
+	if err != nil {
+		u.logger.Errorf("Error fetching genres from repository: %v", err)
+		return nil, err
+	}
+
+	return models.GenreTree(genres), nil
+}
+
+func (u lyricsUseCase) CreateGenre(ctx context.Context, genre models.GenreRequest) (models.GenreInfo, error) {
+	u.logger.Debugf("in usecase CreateGenre() name=%s", genre.Name)
+	return u.lyricsRepo.CreateGenre(ctx, genre)
+}
+
+func (u lyricsUseCase) UpdateGenreByID(ctx context.Context, id uint, genre models.GenreRequest) (models.GenreInfo, error) {
+	u.logger.Debugf("in usecase UpdateGenreByID() ID:%d", id)
+	return u.lyricsRepo.UpdateGenreByID(ctx, id, genre)
+}
+
+func (u lyricsUseCase) DeleteGenreByID(ctx context.Context, id uint) error {
+	u.logger.Debugf("in usecase DeleteGenreByID() ID:%d", id)
+	return u.lyricsRepo.DeleteGenreByID(ctx, id)
+}
+
+func (u lyricsUseCase) AttachGenres(ctx context.Context, request models.SongGenresRequest) (models.ClassificationResult, error) {
+	u.logger.Debugf("in usecase AttachGenres() songs: %d, genres: %v", len(request.SongIDs), request.GenreIDs)
+
+	changed, err := u.lyricsRepo.AttachGenres(ctx, request.SongIDs, request.GenreIDs)
+	return models.ClassificationResult{Changed: changed}, err
+}
+
+func (u lyricsUseCase) DetachGenres(ctx context.Context, request models.SongGenresRequest) (models.ClassificationResult, error) {
+	u.logger.Debugf("in usecase DetachGenres() songs: %d, genres: %v", len(request.SongIDs), request.GenreIDs)
+
+	changed, err := u.lyricsRepo.DetachGenres(ctx, request.SongIDs, request.GenreIDs)
+	return models.ClassificationResult{Changed: changed}, err
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+func (u lyricsUseCase) CreateGroup(ctx context.Context, group models.GroupRequest) (models.GroupInfo, error) {
+	u.logger.Debugf("in usecase CreateGroup() name=%s", group.Name)
+	return u.lyricsRepo.CreateGroup(ctx, group)
+}
+
+func (u lyricsUseCase) GetGroups(ctx context.Context, name string, page, limit int) ([]models.GroupInfo, int, error) {
+	u.logger.Debugf("in usecase GetGroups() name=%s, page=%d, limit=%d", name, page, limit)
+
+	offset := (page - 1) * limit
+
+	groups, total, err := u.lyricsRepo.GetGroups(ctx, name, offset, limit)
+	if err != nil {
+		u.logger.Errorf("Error fetching groups from repository: %v", err)
+		return nil, 0, err
+	}
+
+	return groups, total, nil
+}
+
+func (u lyricsUseCase) GetGroupByID(ctx context.Context, id uint) (models.GroupInfo, error) {
+	u.logger.Debugf("in usecase GetGroupByID() ID:%d", id)
+	return u.lyricsRepo.GetGroupByID(ctx, id)
+}
+
+func (u lyricsUseCase) UpdateGroupByID(ctx context.Context, id uint, group models.GroupRequest) (models.GroupInfo, error) {
+	u.logger.Debugf("in usecase UpdateGroupByID() ID:%d", id)
+	return u.lyricsRepo.UpdateGroupByID(ctx, id, group)
+}
+
+// DeleteGroupByID удаляет группу, с cascade - вместе с песнями, кэш которых сбрасывается
+func (u lyricsUseCase) DeleteGroupByID(ctx context.Context, id uint, cascade bool) (models.GroupDeleteResult, error) {
+	u.logger.Debugf("in usecase DeleteGroupByID() ID:%d, cascade: %t", id, cascade)
+
+	result, err := u.lyricsRepo.DeleteGroupByID(ctx, id, cascade)
+	if err != nil {
+		return result, err
+	}
+
+	for _, songID := range result.DeletedSongs {
+		u.invalidateSongCache(ctx, songID)
+	}
+
+	return result, nil
+}
+
+// CleanupOrphanGroups удаляет группы, у которых не осталось песен и альбомов
+func (u lyricsUseCase) CleanupOrphanGroups(ctx context.Context) (int, error) {
+	deleted, err := u.lyricsRepo.DeleteOrphanGroups(ctx, time.Now().Add(-u.cfg.Groups.CleanupMinAge))
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted > 0 {
+		u.logger.Infof("deleted %d groups without songs", deleted)
+	}
+	return deleted, nil
+}
+
+func (u lyricsUseCase) AddGroupAlias(ctx context.Context, groupID uint, alias string) (models.GroupInfo, error) {
+	u.logger.Debugf("in usecase AddGroupAlias() ID:%d, alias=%s", groupID, alias)
+	return u.lyricsRepo.AddGroupAlias(ctx, groupID, alias)
+}
+
+func (u lyricsUseCase) DeleteGroupAlias(ctx context.Context, groupID uint, alias string) error {
+	u.logger.Debugf("in usecase DeleteGroupAlias() ID:%d, alias=%s", groupID, alias)
+	return u.lyricsRepo.DeleteGroupAlias(ctx, groupID, alias)
+}
+
+// MergeGroups сливает группу sourceID с группой merge.Into, по умолчанию из одноименных песен остается песня merge.Into.
+// Кэш перенесенных и удаленных песен сбрасывается: у них сменилась группа.
+func (u lyricsUseCase) MergeGroups(ctx context.Context, sourceID uint, merge models.GroupMergeRequest) (models.GroupMergeResult, error) {
+	u.logger.Debugf("in usecase MergeGroups() ID:%d, into: %d, strategy: %s", sourceID, merge.Into, merge.Strategy)
+
+	strategy := merge.Strategy
+	if strategy == "" {
+		strategy = models.MergeKeepTarget
+	}
+
+	result, err := u.lyricsRepo.MergeGroups(ctx, sourceID, merge.Into, strategy)
+	if err != nil {
+		return result, err
+	}
+
+	for _, songID := range result.MovedSongs {
+		u.invalidateSongCache(ctx, songID)
+	}
+	for _, conflict := range result.Conflicts {
+		u.invalidateSongCache(ctx, conflict.RemovedSongID)
+		u.invalidateSongCache(ctx, conflict.KeptSongID)
+	}
+
+	u.logger.Infof("merged group %d into %d: %d songs moved, %d conflicts", sourceID, merge.Into, len(result.MovedSongs), len(result.Conflicts))
+	return result, nil
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+)
+
+// maxImportLine максимальная длина строки NDJSON, текст песни целиком помещается в одну строку
+const maxImportLine = 10 << 20
+
+// importRecord строка файла импорта с номером строки и ошибкой разбора
+type importRecord struct {
+	line int
+	row  models.ImportRow
+	err  error
+}
+
+// ImportSongs импортирует песни из CSV или NDJSON.
+// Строки проверяются правилами validator, дубликаты внутри файла пропускаются,
+// сохранение идет пачками по cfg.Import.BatchSize строк в транзакции.
+// С opts.Enrich недостающие данные загружают воркеры обогащения, импорт их не ждет.
+func (u lyricsUseCase) ImportSongs(ctx context.Context, format string, r io.Reader, opts models.ImportOptions) (models.ImportReport, error) {
+	u.logger.Debugf("in usecase ImportSongs() format: %s, options: %+v", format, opts)
+
+	records, err := parseImport(format, r)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+
+	results := make([]models.ImportRowResult, len(records))
+	seen := map[string]int{}
+	batch := []int{}
+
+	// flush сохраняет накопленные строки и раскладывает результаты по их местам в отчете
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		rows := make([]models.ImportRow, 0, len(batch))
+		for _, i := range batch {
+			rows = append(rows, records[i].row)
+		}
+
+		saved, err := u.lyricsRepo.ImportSongs(ctx, rows, opts)
+		if err != nil {
+			return fmt.Errorf("saving import batch: %w", err)
+		}
+
+		for j, i := range batch {
+			saved[j].Line = records[i].line
+			results[i] = saved[j]
+
+			// Текст перезаписанной песни мог измениться
+			if saved[j].Status == models.ImportUpdated {
+				u.invalidateSongCache(ctx, *saved[j].SongID)
+			}
+		}
+
+		batch = batch[:0]
+		return nil
+	}
+
+	for i := range records {
+		record := &records[i]
+		record.row.Group, record.row.Song, record.row.Artists = songCredits(record.row.Group, record.row.Song, record.row.Artists)
+		results[i] = models.ImportRowResult{Line: record.line, Group: record.row.Group, Song: record.row.Song}
+
+		if record.err == nil {
+			record.err = utils.ValidateStruct(ctx, &record.row)
+		}
+		if record.err != nil {
+			results[i].Status = models.ImportFailed
+			results[i].Error = record.err.Error()
+			continue
+		}
+
+		// Строки, совпадающие без учета регистра и написания кавычек, - одна песня
+		key := utils.NameKey(record.row.Group) + "\x00" + utils.NameKey(record.row.Song)
+		if line, ok := seen[key]; ok {
+			results[i].Status = models.ImportSkipped
+			results[i].Error = fmt.Sprintf("duplicate of line %d", line)
+			continue
+		}
+		seen[key] = record.line
+
+		record.row.Source = models.ImportSource
+		record.row.Language = u.detectLanguage(record.row.Text)
+
+		batch = append(batch, i)
+		if len(batch) >= u.cfg.Import.BatchSize {
+			if err := flush(); err != nil {
+				return models.ImportReport{}, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return models.ImportReport{}, err
+	}
+
+	report := models.ImportReport{Rows: make([]models.ImportRowResult, 0, len(results))}
+	for _, result := range results {
+		report.Add(result)
+	}
+
+	u.logger.Infof("import finished: total %d, created %d, updated %d, skipped %d, failed %d",
+		report.Total, report.Created, report.Updated, report.Skipped, report.Failed)
+	return report, nil
+}
+
+// parseImport разбирает файл импорта; ошибки отдельных строк сохраняются в строках,
+// ошибка всего файла - неизвестный формат или отсутствие обязательных колонок
+func parseImport(format string, r io.Reader) ([]importRecord, error) {
+	switch format {
+	case models.ImportFormatCSV:
+		return parseImportCSV(r)
+	case models.ImportFormatNDJSON:
+		return parseImportNDJSON(r)
+	}
+	return nil, fmt.Errorf("%w: unsupported format %q, expected csv or ndjson", lyrics.ErrInvalidImportFile, format)
+}
+
+// parseImportCSV CSV с заголовком: group, song и необязательные release_date, text, link в любом порядке
+func parseImportCSV(r io.Reader) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading CSV header: %v", lyrics.ErrInvalidImportFile, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		name = strings.ReplaceAll(name, "_", "")
+		columns[name] = i
+	}
+	for _, required := range []string{"group", "song"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: CSV header has no %q column", lyrics.ErrInvalidImportFile, required)
+		}
+	}
+
+	column := func(fields []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return fields[i]
+	}
+
+	var records []importRecord
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("reading CSV: %w", err)
+			}
+			records = append(records, importRecord{line: parseErr.StartLine, err: err})
+			continue
+		}
+
+		records = append(records, importRecord{
+			line: line,
+			row: models.ImportRow{
+				Group:       column(fields, "group"),
+				Song:        column(fields, "song"),
+				ReleaseDate: strings.TrimSpace(column(fields, "releasedate")),
+				Text:        column(fields, "text"),
+				Link:        strings.TrimSpace(column(fields, "link")),
+			},
+		})
+	}
+
+	return records, nil
+}
+
+// parseImportNDJSON по одному JSON-объекту на строку, пустые строки пропускаются
+func parseImportNDJSON(r io.Reader) ([]importRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxImportLine)
+
+	var records []importRecord
+	for line := 1; scanner.Scan(); line++ {
+		data := strings.TrimSpace(scanner.Text())
+		if data == "" {
+			continue
+		}
+
+		record := importRecord{line: line}
+		if err := json.Unmarshal([]byte(data), &record.row); err != nil {
+			record.err = fmt.Errorf("invalid JSON: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading NDJSON: %v", lyrics.ErrInvalidImportFile, err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/langdetect"
+)
+
+// detectLanguage определяет язык текста песни.
+// Язык с уверенностью ниже cfg.Language.MinConfidence считается неопределенным.
+func (u lyricsUseCase) detectLanguage(text string) models.LanguageDetection {
+	result := langdetect.Detect(text)
+	if result.Language == "" || result.Confidence < u.cfg.Language.MinConfidence {
+		return models.LanguageDetection{}
+	}
+	return models.LanguageDetection{Language: &result.Language, Confidence: &result.Confidence}
+}
+
+// detectUpdatedLanguage определяет язык при обновлении песни: по новому тексту
+// или, если закрепленный язык снят пустым language, по сохраненному тексту
+func (u lyricsUseCase) detectUpdatedLanguage(ctx context.Context, updateData *models.UpdateTrackRequest) error {
+	if updateData.Language != nil && *updateData.Language != "" {
+		return nil
+	}
+
+	switch {
+	case updateData.Text != nil:
+		detected := u.detectLanguage(*updateData.Text)
+		updateData.DetectedLanguage = &detected
+	case updateData.Language != nil:
+		song, err := u.lyricsRepo.GetSongByID(ctx, updateData.ID)
+		if err != nil {
+			return err
+		}
+		detected := u.detectLanguage(song.Text)
+		updateData.DetectedLanguage = &detected
+	}
+
+	return nil
+}
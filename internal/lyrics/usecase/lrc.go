@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/lrc"
+)
+
+// GetSongLRC синхронизированный текст песни
+func (u lyricsUseCase) GetSongLRC(ctx context.Context, id uint) (models.SongLRC, error) {
+	u.logger.Debugf("in usecase GetSongLRC() ID:%d", id)
+
+	_, songLRC, err := u.songLyrics(ctx, id)
+	if err != nil {
+		return models.SongLRC{}, err
+	}
+	if songLRC == "" {
+		return models.SongLRC{}, lyrics.ErrNoLRC
+	}
+
+	parsed, err := lrc.Parse(songLRC)
+	if err != nil {
+		return models.SongLRC{}, fmt.Errorf("stored LRC of song %d: %w", id, err)
+	}
+
+	return toSongLRC(id, songLRC, parsed), nil
+}
+
+// SetSongLRC проверяет и сохраняет синхронизированный текст песни
+func (u lyricsUseCase) SetSongLRC(ctx context.Context, id uint, songLRC string) (models.SongLRC, error) {
+	u.logger.Debugf("in usecase SetSongLRC() ID:%d", id)
+
+	parsed, err := lrc.Parse(songLRC)
+	if err != nil {
+		return models.SongLRC{}, fmt.Errorf("%w: %v", lyrics.ErrInvalidLRC, err)
+	}
+
+	if err := u.lyricsRepo.SetSongLRC(ctx, id, &songLRC); err != nil {
+		return models.SongLRC{}, err
+	}
+
+	u.invalidateSongCache(ctx, id)
+	return toSongLRC(id, songLRC, parsed), nil
+}
+
+// DeleteSongLRC удаляет синхронизированный текст песни
+func (u lyricsUseCase) DeleteSongLRC(ctx context.Context, id uint) error {
+	u.logger.Debugf("in usecase DeleteSongLRC() ID:%d", id)
+
+	if err := u.lyricsRepo.SetSongLRC(ctx, id, nil); err != nil {
+		return err
+	}
+
+	u.invalidateSongCache(ctx, id)
+	return nil
+}
+
+func toSongLRC(id uint, raw string, parsed lrc.Lyrics) models.SongLRC {
+	songLRC := models.SongLRC{
+		SongID:   id,
+		Tags:     parsed.Tags,
+		OffsetMs: parsed.Offset.Milliseconds(),
+		Lines:    make([]models.LRCLine, 0, len(parsed.Lines)),
+		Raw:      raw,
+	}
+
+	for _, line := range parsed.Lines {
+		songLRC.Lines = append(songLRC.Lines, models.LRCLine{
+			TimeMs: line.Time.Milliseconds(),
+			Time:   lrc.FormatTime(line.Time),
+			Text:   line.Text,
+		})
+	}
+
+	return songLRC
+}
+
+// timeVerses сопоставляет строки куплетов со строками LRC.
+// Строки идут в одном порядке, поэтому для каждой строки куплета ищется первая совпадающая
+// строка LRC после предыдущего совпадения; повтор припева получает время своего повтора.
+// Строки, которых нет в LRC, остаются без времени; nil - LRC не удалось разобрать.
+func (u lyricsUseCase) timeVerses(id uint, verses []string, songLRC string) [][]models.VerseLine {
+	parsed, err := lrc.Parse(songLRC)
+	if err != nil {
+		u.logger.Errorf("stored LRC of song %d is invalid: %v", id, err)
+		return nil
+	}
+
+	timed := make([][]models.VerseLine, len(verses))
+
+	next := 0
+	for i, verse := range verses {
+		for _, line := range strings.Split(verse, "\n") {
+			verseLine := models.VerseLine{Text: line}
+
+			key := normalizeLyricsLine(line)
+			for j := next; j < len(parsed.Lines); j++ {
+				if normalizeLyricsLine(parsed.Lines[j].Text) != key {
+					continue
+				}
+
+				timeMs, timeText := parsed.Lines[j].Time.Milliseconds(), lrc.FormatTime(parsed.Lines[j].Time)
+				verseLine.TimeMs, verseLine.Time = &timeMs, &timeText
+				next = j + 1
+				break
+			}
+
+			timed[i] = append(timed[i], verseLine)
+		}
+	}
+
+	return timed
+}
+
+// normalizeLyricsLine строка для сравнения без учета регистра и лишних пробелов
+func normalizeLyricsLine(line string) string {
+	return strings.Join(strings.Fields(strings.ToLower(line)), " ")
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// refreshState общий для планировщика и ручного запуска: одновременно идет только один прогон
+type refreshState struct {
+	running sync.Mutex
+
+	mu   sync.Mutex
+	last *models.RefreshReport
+}
+
+// RefreshStaleSongs повторно загружает из источников до limit устаревших или неполных песен.
+// Если прогон уже идет, возвращает ErrRefreshInProgress.
+func (u lyricsUseCase) RefreshStaleSongs(ctx context.Context, limit int) (models.RefreshReport, error) {
+	if !u.refresh.running.TryLock() {
+		return models.RefreshReport{}, lyrics.ErrRefreshInProgress
+	}
+	defer u.refresh.running.Unlock()
+
+	return u.refreshStaleSongs(ctx, limit)
+}
+
+// TriggerRefresh запускает прогон в фоне, итог доступен через LastRefresh.
+// Прогон отменяется вместе с ctx, done закрывается по его окончании.
+func (u lyricsUseCase) TriggerRefresh(ctx context.Context, limit int) (<-chan struct{}, error) {
+	if !u.refresh.running.TryLock() {
+		return nil, lyrics.ErrRefreshInProgress
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer u.refresh.running.Unlock()
+		defer func() {
+			if r := recover(); r != nil {
+				u.logger.Errorf("Recovered from panic in refresh: %v", r)
+			}
+		}()
+
+		if _, err := u.refreshStaleSongs(ctx, limit); err != nil && ctx.Err() == nil {
+			u.logger.Errorf("refresh run failed: %v", err)
+		}
+	}()
+
+	return done, nil
+}
+
+// LastRefresh итог последнего или текущего прогона, false - прогонов еще не было
+func (u lyricsUseCase) LastRefresh() (models.RefreshReport, bool) {
+	u.refresh.mu.Lock()
+	defer u.refresh.mu.Unlock()
+
+	if u.refresh.last == nil {
+		return models.RefreshReport{}, false
+	}
+	return *u.refresh.last, true
+}
+
+func (u lyricsUseCase) refreshStaleSongs(ctx context.Context, limit int) (models.RefreshReport, error) {
+	if limit <= 0 {
+		limit = u.cfg.Refresh.BatchSize
+	}
+	u.logger.Debugf("in usecase refreshStaleSongs() limit: %d", limit)
+
+	report := models.RefreshReport{
+		Running:   true,
+		StartedAt: time.Now(),
+		Changes:   []models.SongChange{},
+		Errors:    []models.RefreshError{},
+	}
+	u.saveRefreshReport(report)
+
+	songs, err := u.lyricsRepo.GetSongsForRefresh(ctx, report.StartedAt.Add(-u.cfg.Refresh.MaxAge), report.StartedAt.Add(-u.cfg.Refresh.RetryAfter), limit)
+	if err != nil {
+		u.finishRefreshReport(report)
+		return report, err
+	}
+	report.Checked = len(songs)
+
+	// Ограничиваем частоту запросов к источникам
+	var throttle <-chan time.Time
+	if u.cfg.Refresh.RatePerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / u.cfg.Refresh.RatePerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	for i, song := range songs {
+		if i > 0 && throttle != nil {
+			select {
+			case <-ctx.Done():
+				u.finishRefreshReport(report)
+				return report, ctx.Err()
+			case <-throttle:
+			}
+		}
+
+		changes, err := u.refreshSong(ctx, song)
+		if err != nil {
+			u.logger.Warnf("refresh of track %d failed: %v", song.ID, err)
+			report.Failed++
+			report.Errors = append(report.Errors, models.RefreshError{SongID: song.ID, Error: err.Error()})
+			continue
+		}
+
+		if len(changes) == 0 {
+			report.Unchanged++
+			continue
+		}
+		report.Updated++
+		report.Changes = append(report.Changes, changes...)
+	}
+
+	report = u.finishRefreshReport(report)
+	u.logger.Infof("refresh finished: checked %d, updated %d, unchanged %d, failed %d",
+		report.Checked, report.Updated, report.Unchanged, report.Failed)
+
+	return report, nil
+}
+
+// refreshSong загружает песню из источников и сохраняет изменения
+func (u lyricsUseCase) refreshSong(ctx context.Context, song models.Song) ([]models.SongChange, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, u.cfg.API.APICtxTimeout)
+	songDetail, err := u.lyricsProvider.Fetch(fetchCtx, song.GroupName, song.SongName)
+	cancel()
+	if err != nil {
+		if err := u.lyricsRepo.MarkSongRefreshed(ctx, song.ID); err != nil {
+			u.logger.Errorf("failed to mark track %d as refreshed: %v", song.ID, err)
+		}
+		return nil, err
+	}
+
+	songDetail.Language = u.detectLanguage(songDetail.Text)
+
+	changes, err := u.lyricsRepo.ApplySongRefresh(ctx, song.ID, songDetail)
+	if err != nil {
+		return nil, fmt.Errorf("saving refreshed track: %w", err)
+	}
+
+	for _, change := range changes {
+		if change.Field == "text" {
+			u.refreshSongCache(ctx, song.ID, songDetail.Text)
+			break
+		}
+	}
+
+	return changes, nil
+}
+
+func (u lyricsUseCase) finishRefreshReport(report models.RefreshReport) models.RefreshReport {
+	finishedAt := time.Now()
+	report.Running = false
+	report.FinishedAt = &finishedAt
+	u.saveRefreshReport(report)
+	return report
+}
+
+func (u lyricsUseCase) saveRefreshReport(report models.RefreshReport) {
+	u.refresh.mu.Lock()
+	defer u.refresh.mu.Unlock()
+	u.refresh.last = &report
+}
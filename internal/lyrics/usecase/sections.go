@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// sectionMarkerRe строка-маркер части песни: [Chorus], [Verse 2], [Chorus: Artist], [Припев]
+var sectionMarkerRe = regexp.MustCompile(`^\[([^\[\]]+)\]$`)
+
+// sectionKeywords начало метки маркера и тип части; более длинные префиксы проверяются раньше
+var sectionKeywords = []struct {
+	prefix      string
+	sectionType string
+}{
+	{"pre-chorus", models.SectionPreChorus},
+	{"pre chorus", models.SectionPreChorus},
+	{"prechorus", models.SectionPreChorus},
+	{"предприпев", models.SectionPreChorus},
+	{"post-chorus", models.SectionPostChorus},
+	{"post chorus", models.SectionPostChorus},
+	{"postchorus", models.SectionPostChorus},
+	{"chorus", models.SectionChorus},
+	{"refrain", models.SectionChorus},
+	{"припев", models.SectionChorus},
+	{"verse", models.SectionVerse},
+	{"куплет", models.SectionVerse},
+	{"bridge", models.SectionBridge},
+	{"бридж", models.SectionBridge},
+	{"intro", models.SectionIntro},
+	{"вступление", models.SectionIntro},
+	{"outro", models.SectionOutro},
+	{"концовка", models.SectionOutro},
+	{"hook", models.SectionHook},
+	{"instrumental", models.SectionInstrumental},
+	{"solo", models.SectionInstrumental},
+	{"проигрыш", models.SectionInstrumental},
+	{"соло", models.SectionInstrumental},
+}
+
+// sectionType тип части по метке маркера, пустая строка - метка не похожа на маркер
+func sectionType(label string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	for _, keyword := range sectionKeywords {
+		if strings.HasPrefix(label, keyword.prefix) {
+			return keyword.sectionType
+		}
+	}
+	return ""
+}
+
+// songPart строфы после одного маркера части или одна строфа текста без маркеров
+type songPart struct {
+	label   string
+	stanzas []string
+}
+
+// splitSong делит текст на части. Строфы разделяются пустыми строками и маркерами,
+// маркер начинает новую часть и в текст строф не попадает. Строки в скобках, не похожие
+// на маркер ([смех], [laughs]), остаются частью текста. Без маркеров каждая строфа - отдельная часть.
+func splitSong(text string) ([]songPart, bool) {
+	parts := []songPart{{}}
+	marked := false
+
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			last := &parts[len(parts)-1]
+			last.stanzas = append(last.stanzas, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		if marker := sectionMarkerRe.FindStringSubmatch(trimmed); marker != nil && sectionType(marker[1]) != "" {
+			flush()
+			marked = true
+			parts = append(parts, songPart{label: strings.TrimSpace(marker[1])})
+			continue
+		}
+
+		current = append(current, line)
+	}
+	flush()
+
+	if !marked {
+		stanzas := parts[0].stanzas
+		parts = make([]songPart, 0, len(stanzas))
+		for _, stanza := range stanzas {
+			parts = append(parts, songPart{stanzas: []string{stanza}})
+		}
+		return parts, false
+	}
+
+	// Строф до первого маркера может не быть
+	if len(parts[0].stanzas) == 0 {
+		parts = parts[1:]
+	}
+	return parts, true
+}
+
+// buildSections структура песни. По маркерам тип берется из метки, строфы до первого маркера - куплет без метки.
+// Без маркеров повторяющиеся строфы считаются припевом, остальные - куплетами по порядку.
+func buildSections(text string) ([]models.Section, bool) {
+	parts, marked := splitSong(text)
+	sections := make([]models.Section, 0, len(parts))
+
+	// Без маркеров: сколько раз встречается каждая строфа
+	counts := map[string]int{}
+	if !marked {
+		for _, part := range parts {
+			counts[normalizeStanza(part.stanzas[0])]++
+		}
+	}
+	chorusLabels := map[string]string{}
+	verses := 0
+
+	page := 0
+	for _, part := range parts {
+		section := models.Section{
+			Index: len(sections) + 1,
+			Type:  models.SectionVerse,
+			Label: part.label,
+			Pages: []int{},
+			Text:  strings.Join(part.stanzas, "\n\n"),
+		}
+		for range part.stanzas {
+			page++
+			section.Pages = append(section.Pages, page)
+		}
+
+		switch key := normalizeStanza(section.Text); {
+		case marked && part.label != "":
+			section.Type = sectionType(part.label)
+		case marked:
+		case counts[key] > 1:
+			section.Type = models.SectionChorus
+			if _, ok := chorusLabels[key]; !ok {
+				chorusLabels[key] = "Chorus"
+				if len(chorusLabels) > 1 {
+					chorusLabels[key] += " " + strconv.Itoa(len(chorusLabels))
+				}
+			}
+			section.Label = chorusLabels[key]
+		default:
+			verses++
+			section.Label = "Verse " + strconv.Itoa(verses)
+		}
+
+		sections = append(sections, section)
+	}
+
+	markRepeatedSections(sections)
+	return sections, marked
+}
+
+// markRepeatedSections ссылается на первую часть с тем же текстом.
+// Маркер без текста ([Chorus] вместо повторного припева) ссылается на первую часть с той же меткой.
+func markRepeatedSections(sections []models.Section) {
+	first := map[string]int{}
+	firstLabel := map[string]int{}
+
+	for i := range sections {
+		section := &sections[i]
+		// Исполнитель после двоеточия не важен: [Chorus] повторяет [Chorus: Muse]
+		label, _, _ := strings.Cut(strings.ToLower(section.Label), ":")
+		label = strings.TrimSpace(label)
+
+		if key := normalizeStanza(section.Text); key != "" {
+			if index, ok := first[key]; ok {
+				section.RepeatOf = &index
+			} else {
+				first[key] = section.Index
+			}
+		} else if index, ok := firstLabel[label]; ok {
+			section.RepeatOf = &index
+		}
+
+		if _, ok := firstLabel[label]; !ok && label != "" && section.Text != "" {
+			firstLabel[label] = section.Index
+		}
+	}
+}
+
+// normalizeStanza строфа для сравнения без учета регистра и лишних пробелов
+func normalizeStanza(stanza string) string {
+	lines := strings.Split(stanza, "\n")
+	for i, line := range lines {
+		lines[i] = normalizeLyricsLine(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// sectionOfPage часть песни, в которую входит страница куплета
+func sectionOfPage(sections []models.Section, page int) (models.Section, bool) {
+	for _, section := range sections {
+		for _, p := range section.Pages {
+			if p == page {
+				return section, true
+			}
+		}
+	}
+	return models.Section{}, false
+}
+
+// GetSongSections структура песни: куплеты, припевы и другие части со страницами куплетов
+func (u lyricsUseCase) GetSongSections(ctx context.Context, id uint) (models.SongSections, error) {
+	u.logger.Debugf("in usecase GetSongSections() ID:%d", id)
+
+	songText, _, err := u.songLyrics(ctx, id)
+	if err != nil {
+		return models.SongSections{}, err
+	}
+
+	sections, marked := buildSections(songText)
+	return models.SongSections{SongID: id, Marked: marked, Sections: sections}, nil
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+func (u lyricsUseCase) GetTags(ctx context.Context, name string, page, limit int) ([]models.TagInfo, int, error) {
+	u.logger.Debugf("in usecase GetTags() name=%s, page=%d, limit=%d", name, page, limit)
+
+	offset := (page - 1) * limit
+
+	tags, total, err := u.lyricsRepo.GetTags(ctx, name, offset, limit)
+	if err != nil {
+		u.logger.Errorf("Error fetching tags from repository: %v", err)
+		return nil, 0, err
+	}
+
+	return tags, total, nil
+}
+
+func (u lyricsUseCase) DeleteTagByID(ctx context.Context, id uint) error {
+	u.logger.Debugf("in usecase DeleteTagByID() ID:%d", id)
+	return u.lyricsRepo.DeleteTagByID(ctx, id)
+}
+
+func (u lyricsUseCase) AttachTags(ctx context.Context, request models.SongTagsRequest) (models.ClassificationResult, error) {
+	u.logger.Debugf("in usecase AttachTags() songs: %d, tags: %v", len(request.SongIDs), request.Tags)
+
+	changed, err := u.lyricsRepo.AttachTags(ctx, request.SongIDs, request.Tags)
+	return models.ClassificationResult{Changed: changed}, err
+}
+
+func (u lyricsUseCase) DetachTags(ctx context.Context, request models.SongTagsRequest) (models.ClassificationResult, error) {
+	u.logger.Debugf("in usecase DetachTags() songs: %d, tags: %v", len(request.SongIDs), request.Tags)
+
+	changed, err := u.lyricsRepo.DetachTags(ctx, request.SongIDs, request.Tags)
+	return models.ClassificationResult{Changed: changed}, err
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// SaveTranslation добавляет или заменяет перевод песни, created - перевод добавлен
+func (u lyricsUseCase) SaveTranslation(ctx context.Context, songID uint, lang, text string) (models.SongTranslation, bool, error) {
+	u.logger.Debugf("in usecase SaveTranslation() songID:%d, lang: %s", songID, lang)
+
+	return u.lyricsRepo.SaveTranslation(ctx, songID, lang, text)
+}
+
+// GetTranslations переводы песни, sql.ErrNoRows - песни нет
+func (u lyricsUseCase) GetTranslations(ctx context.Context, songID uint) ([]models.SongTranslation, error) {
+	u.logger.Debugf("in usecase GetTranslations() songID:%d", songID)
+
+	if _, err := u.lyricsRepo.GetSongByID(ctx, songID); err != nil {
+		return nil, err
+	}
+
+	return u.lyricsRepo.GetTranslations(ctx, songID)
+}
+
+// GetTranslation перевод песни на язык
+func (u lyricsUseCase) GetTranslation(ctx context.Context, songID uint, lang string) (models.SongTranslation, error) {
+	return u.lyricsRepo.GetTranslation(ctx, songID, lang)
+}
+
+// DeleteTranslation удаляет перевод песни на язык
+func (u lyricsUseCase) DeleteTranslation(ctx context.Context, songID uint, lang string) error {
+	u.logger.Debugf("in usecase DeleteTranslation() songID:%d, lang: %s", songID, lang)
+
+	return u.lyricsRepo.DeleteTranslation(ctx, songID, lang)
+}
+
+// translationFor перевод для выдачи рядом с оригиналом; nil - язык не запрошен или перевода нет
+func (u lyricsUseCase) translationFor(ctx context.Context, songID uint, lang string) (*models.SongTranslation, error) {
+	if lang == "" {
+		return nil, nil
+	}
+
+	translation, err := u.lyricsRepo.GetTranslation(ctx, songID, lang)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			u.logger.Debugf("song %d has no %s translation, falling back to the original", songID, lang)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &translation, nil
+}
@@ -2,13 +2,10 @@ package usecase
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"strings"
-	"time"
 
 	"github.com/22Fariz22/musiclab/config"
 	"github.com/22Fariz22/musiclab/internal/lyrics"
@@ -18,24 +15,22 @@ import (
 )
 
 type lyricsUseCase struct {
-	cfg         *config.Config
-	lyricsRepo  lyrics.Repository
-	redisClient *redis.Client
-	logger      logger.Logger
-	httpClient  *http.Client
+	cfg            *config.Config
+	lyricsRepo     lyrics.Repository
+	lyricsProvider lyrics.LyricsProvider
+	redisClient    *redis.Client
+	logger         logger.Logger
+	refresh        *refreshState
 }
 
-func NewLyricsUseCase(cfg *config.Config, lyricsRepo lyrics.Repository, redisClient *redis.Client, logger logger.Logger) lyrics.UseCase {
-	httpClient := &http.Client{
-		Timeout: 5 * time.Second,
-	}
-
+func NewLyricsUseCase(cfg *config.Config, lyricsRepo lyrics.Repository, lyricsProvider lyrics.LyricsProvider, redisClient *redis.Client, logger logger.Logger) lyrics.UseCase {
 	return &lyricsUseCase{
-		cfg:         cfg,
-		lyricsRepo:  lyricsRepo,
-		redisClient: redisClient,
-		logger:      logger,
-		httpClient:  httpClient,
+		cfg:            cfg,
+		lyricsRepo:     lyricsRepo,
+		lyricsProvider: lyricsProvider,
+		redisClient:    redisClient,
+		logger:         logger,
+		refresh:        &refreshState{},
 	}
 }
 
@@ -53,212 +48,211 @@ func (u lyricsUseCase) Ping() error {
 	return nil
 }
 
-func (u lyricsUseCase) DeleteSongByID(ctx context.Context, ID uint) error {
-	u.logger.Debugf("in usecase DeleteSongByID. Deleting ID: %d\n", ID)
-	return u.lyricsRepo.DeleteSongByID(ctx, ID)
-}
-
-func (u lyricsUseCase) UpdateTrackByID(ctx context.Context, updateData models.UpdateTrackRequest) error {
-	u.logger.Debugf("in usecase UpdateTrackByID() ID:%d", updateData.ID)
-	return u.lyricsRepo.UpdateTrackByID(ctx, updateData)
-}
-
-func (u lyricsUseCase) CreateTrack(ctx context.Context, songRequest models.SongRequest) (models.SongDetail, error) {
-	u.logger.Debug("in usecase CreateTrack()\n")
-	ctx, cancel := context.WithTimeout(ctx, u.cfg.API.APICtxTimeout)
-	defer cancel()
-
-	fullURL, err := u.BuildAPIURL(songRequest.Group, songRequest.Song)
-	if err != nil {
-		u.logger.Errorf("failed to build API URL: %v", err)
-		return models.SongDetail{}, err
+// Health состояние базы данных и источников песен
+func (u lyricsUseCase) Health(ctx context.Context) models.Health {
+	health := models.Health{
+		Status:    models.HealthOK,
+		Database:  models.HealthUp,
+		Providers: u.lyricsProvider.Health(),
 	}
 
-	maxRetries := u.cfg.API.MaxRetries // Максимальное количество попыток
-	retryDelay := u.cfg.API.RetryDelay // Задержка между попытками
-
-	var songDetails models.SongDetail
-	var lastErr error
-
-	// Логика повторных попыток
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		select {
-		case <-ctx.Done():
-			u.logger.Warnf("context cancelled during fetch: %v", ctx.Err())
-			return models.SongDetail{}, fmt.Errorf("context cancelled: %w", ctx.Err())
-		default:
-			songDetails, err = u.FetchAPI(ctx, fullURL)
-			if err == nil {
-				break
-			}
-			lastErr = err
-			u.logger.Warnf("attempt %d/%d failed to fetch lyrics: %v", attempt, maxRetries, err)
-			time.Sleep(retryDelay)
+	for _, provider := range health.Providers {
+		if provider.Status != models.HealthUp {
+			health.Status = models.HealthDegraded
 		}
 	}
 
-	if lastErr != nil {
-		u.logger.Errorf("all attempts to fetch lyrics failed: %v", lastErr)
-		return models.SongDetail{}, fmt.Errorf("failed to fetch lyrics: %w", lastErr)
+	if err := u.lyricsRepo.Ping(); err != nil {
+		u.logger.Errorf("database is unavailable: %v", err)
+		health.Database = models.HealthDown
+		health.Status = models.HealthDown
 	}
 
-	if err := u.lyricsRepo.CreateTrack(ctx, songRequest, songDetails); err != nil {
-		u.logger.Errorf("failed to save track: %v", err)
-		return songDetails, fmt.Errorf("saving track: %w", err)
+	return health
+}
+
+func (u lyricsUseCase) DeleteSongByID(ctx context.Context, ID uint) error {
+	u.logger.Debugf("in usecase DeleteSongByID. Deleting ID: %d\n", ID)
+
+	err := u.lyricsRepo.DeleteSongByID(ctx, ID)
+	// Песни в базе уже нет - кэш в любом случае устарел
+	if err == nil || errors.Is(err, sql.ErrNoRows) {
+		u.invalidateSongCache(ctx, ID)
 	}
 
-	u.logger.Infof("track created successfully: %+v", songDetails)
-	return songDetails, nil
+	return err
 }
 
-func (u lyricsUseCase) BuildAPIURL(group, song string) (string, error) {
-	APIAddr := fmt.Sprintf("%s:%s%s", u.cfg.Server.BaseUrl, u.cfg.Server.Port, u.cfg.API.APIPath)
+func (u lyricsUseCase) UpdateTrackByID(ctx context.Context, updateData models.UpdateTrackRequest) error {
+	u.logger.Debugf("in usecase UpdateTrackByID() ID:%d", updateData.ID)
 
-	parsedURL, err := url.Parse(APIAddr)
-	if err != nil {
-		return "", fmt.Errorf("parsing URL: %w", err)
+	if updateData.GroupName != nil && updateData.SongName != nil {
+		group, song, artists := songCredits(*updateData.GroupName, *updateData.SongName, updateData.Artists)
+		updateData.GroupName, updateData.SongName, updateData.Artists = &group, &song, artists
 	}
 
-	q := parsedURL.Query()
-	q.Set("group", group)
-	q.Set("song", song)
-	parsedURL.RawQuery = q.Encode()
+	if err := u.detectUpdatedLanguage(ctx, &updateData); err != nil {
+		return err
+	}
 
-	u.logger.Debugf("APIAddr:%s", APIAddr)
-	u.logger.Debugf("parsedURL:%s", parsedURL.String())
+	if err := u.lyricsRepo.UpdateTrackByID(ctx, updateData); err != nil {
+		return err
+	}
 
-	return parsedURL.String(), nil
+	u.invalidateSongCache(ctx, updateData.ID)
+	return nil
 }
 
-// FetchAPI обращение к API
-func (u *lyricsUseCase) FetchAPI(ctx context.Context, url string) (models.SongDetail, error) {
-	u.logger.Debugf("UrlAPI: %s", url)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// GetSongByID песня со всеми метаданными и количеством куплетов.
+// С lang текст возвращается на языке перевода, если он есть, иначе оригинал.
+func (u lyricsUseCase) GetSongByID(ctx context.Context, id uint, lang string) (models.SongInfo, error) {
+	u.logger.Debugf("in usecase GetSongByID() ID:%d, lang: %s", id, lang)
+
+	song, err := u.lyricsRepo.GetSongByID(ctx, id)
 	if err != nil {
-		u.logger.Errorf("failed to create request: %v", err)
-		return models.SongDetail{}, fmt.Errorf("creating request: %w", err)
+		return models.SongInfo{}, err
 	}
 
-	resp, err := u.httpClient.Do(req)
+	translations, err := u.lyricsRepo.GetTranslations(ctx, id)
 	if err != nil {
-		u.logger.Errorf("request failed: %v", err)
-		return models.SongDetail{}, fmt.Errorf("making request: %w", err)
+		return models.SongInfo{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return models.SongDetail{}, fmt.Errorf("API returned %d: %s", resp.StatusCode, string(body))
+	info := models.SongInfo{
+		ID:          song.ID,
+		Group:       song.GroupName,
+		Artists:     song.Artists,
+		Song:        song.SongName,
+		ReleaseDate: song.ReleaseDate,
+		Link:        song.Link,
+		Source:      song.Source,
+		Status:      song.Status,
+		EnrichError: song.EnrichError,
+		AlbumID:     song.AlbumID,
+		AlbumTitle:  song.AlbumTitle,
+		TrackNumber: song.TrackNumber,
+		VerseCount:  len(prepareLyrics(song.Text)),
+		HasLRC:      song.LRC != nil,
+		CreatedAt:   song.CreatedAt,
+		UpdatedAt:   song.UpdatedAt,
+		Text:        song.Text,
+
+		OriginalLanguage:   song.Language,
+		LanguageConfidence: song.LanguageConfidence,
+		LanguageManual:     song.LanguageManual,
+
+		Genres: song.Genres,
+		Tags:   song.Tags,
 	}
-
-	var songDetail models.SongDetail
-	if err := json.NewDecoder(resp.Body).Decode(&songDetail); err != nil {
-		u.logger.Errorf("failed to decode response: %v", err)
-		return models.SongDetail{}, fmt.Errorf("decoding response: %w", err)
+	if info.Genres == nil {
+		info.Genres = []string{}
+	}
+	if info.Tags == nil {
+		info.Tags = []string{}
 	}
 
-	return songDetail, nil
+	info.Translations = make([]string, 0, len(translations))
+	for _, translation := range translations {
+		info.Translations = append(info.Translations, translation.Language)
+		if translation.Language == lang {
+			info.Text = translation.Text
+			info.Language = translation.Language
+		}
+	}
 
-	// return models.SongDetail{
-	// 	ReleaseDate: "01.03.89",
-	// 	Text:        "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\nYou caught me under false pretenses\nHow long before you let me go?\n\nOoh\nYou set my soul alight\nOoh\nYou set my soul alight",
-	// 	Link:        "yputube.com/sd2ff6ggf",
-	// }, nil
+	return info, nil
 }
 
-// GetSongVerseByPage
-func (u lyricsUseCase) GetSongVerseByID(ctx context.Context, id uint, page int) (string, error) {
-	u.logger.Debugf("in UC GetSongVerseByPage ID:%d, page:%d\n", id, page)
-
-	cacheKey := fmt.Sprintf("song:%d", id)
+// GetSongVerseByID страница куплетов песни выбранным способом разбиения, при наличии LRC - со временем начала строк
+func (u lyricsUseCase) GetSongVerseByID(ctx context.Context, id uint, page int, paging models.VersePaging, lang string) (models.Verse, error) {
+	u.logger.Debugf("in UC GetSongVerseByPage ID:%d, page:%d, paging: %+v, lang: %s\n", id, page, paging, lang)
 
-	// Проверяем кэш
-	cachedSong, err := u.redisClient.Get(ctx, cacheKey).Result()
-	if err != nil && err != redis.Nil {
-		u.logger.Errorf("Error fetching from Redis: %v", err)
-		cachedSong = ""
+	paging, err := versePagingOrDefault(paging)
+	if err != nil {
+		return models.Verse{}, err
 	}
 
-	var songText string
-	if cachedSong == "" {
-		u.logger.Debugf("Cache miss for key: %s. Fetching from database.", cacheKey)
-
-		// Если в кэше ничего нет, идём в базу данных
-		song, err := u.lyricsRepo.GetSongByID(ctx, id)
-		if err != nil {
-			u.logger.Debugf("error in uc u.lyricsRepo.GetSongByID():%v", err)
-			return "", fmt.Errorf("failed to get song from database: %w", err)
-		}
-
-		songText = song.Text
-
-		// Сохраняем песню в кэше
-		err = u.redisClient.Set(ctx, cacheKey, songText, u.cfg.Redis.SongTextCasheTTL).Err()
-		if err != nil {
-			u.logger.Errorf("Error caching song in Redis: %v", err)
-		}
-	} else {
-		u.logger.Debugf("Cache hit for key: %s", cacheKey)
-		songText = cachedSong
+	songText, songLRC, err := u.songLyrics(ctx, id)
+	if err != nil {
+		return models.Verse{}, err
 	}
 
-	u.logger.Debugf("text song:", songText)
+	// Перевод возвращается рядом с оригиналом, если он есть
+	translation, err := u.translationFor(ctx, id, lang)
+	if err != nil {
+		return models.Verse{}, err
+	}
 
-	// Разделяем текст на куплеты
-	verses := prepareLyrics(songText)
+	// Разделяем текст на страницы
+	pages := u.versePages(id, songText, songLRC, paging, translation)
 
 	// Проверяем, существует ли куплет для указанной страницы
-	if page <= 0 || page > len(verses) {
-		return "", fmt.Errorf("no verse available for page %d", page)
+	if page <= 0 || page > len(pages) {
+		return models.Verse{}, fmt.Errorf("no verse available for page %d", page)
 	}
 
 	// Возвращаем куплет по индексу (page - 1, так как индексация с 0)
-	return verses[page-1], nil
+	return pages[page-1], nil
 }
 
-// prepareLyrics делим песню на куплеты
+// prepareLyrics делим песню на куплеты: строфы между пустыми строками и маркерами частей, без самих маркеров
 func prepareLyrics(lyrics string) []string {
-	// lines := strings.Split(lyrics, "\\\n")
-	// // lines = strings.Split(lyrics, "\n")
-	// return lines
-
-	// Разделяем текст по строкам
-	lines := strings.Split(lyrics, "\n")
+	parts, _ := splitSong(lyrics)
 
 	var verses []string
-	var currentVerse []string
-
-	for _, line := range lines {
-		// Если строка пустая, завершаем текущий куплет
-		if strings.TrimSpace(line) == "" {
-			if len(currentVerse) > 0 {
-				verses = append(verses, strings.Join(currentVerse, "\n"))
-				currentVerse = []string{}
-			}
-		} else {
-			// Добавляем строку к текущему куплету
-			currentVerse = append(currentVerse, line)
-		}
+	for _, part := range parts {
+		verses = append(verses, part.stanzas...)
 	}
 
-	// Добавляем последний куплет, если он не пуст
-	if len(currentVerse) > 0 {
-		verses = append(verses, strings.Join(currentVerse, "\n"))
+	return verses
+}
+
+func (u lyricsUseCase) GetLibrary(ctx context.Context, filter models.LibraryFilter) (models.LibraryPage, error) {
+	u.logger.Debugf("Fetching library with filters: %+v", filter)
+
+	page, err := u.lyricsRepo.GetLibrary(ctx, filter)
+	if err != nil {
+		u.logger.Errorf("Error fetching library from repository: %v", err)
+		return models.LibraryPage{}, err
 	}
 
-	return verses
+	return page, nil
 }
 
-func (u lyricsUseCase) GetLibrary(ctx context.Context, group, song, text, releaseDate string, page, limit int) ([]models.Song, int, error) {
-	u.logger.Debugf("Fetching library with filters: group=%s, song=%s,text=%s, releaseDate=%s, page=%d, limit=%d", group, song, releaseDate, page, limit)
+// SearchLyrics полнотекстовый поиск по текстам песен
+func (u lyricsUseCase) SearchLyrics(ctx context.Context, query string, page, limit int) ([]models.SearchResult, int, error) {
+	u.logger.Debugf("in usecase SearchLyrics() query=%s, page=%d, limit=%d", query, page, limit)
+
+	if strings.TrimSpace(query) == "" {
+		return nil, 0, lyrics.ErrEmptySearchQuery
+	}
 
 	offset := (page - 1) * limit
 
-	songs, total, err := u.lyricsRepo.GetLibrary(ctx, group, song, text, releaseDate, offset, limit)
+	results, total, err := u.lyricsRepo.SearchLyrics(ctx, query, offset, limit)
 	if err != nil {
-		u.logger.Errorf("Error fetching library from repository: %v", err)
+		u.logger.Errorf("Error searching lyrics in repository: %v", err)
 		return nil, 0, err
 	}
 
-	return songs, total, nil
+	return results, total, nil
+}
+
+// Suggest подбирает похожие названия групп и песен для исправления опечаток
+func (u lyricsUseCase) Suggest(ctx context.Context, group, song string) (models.Suggestions, error) {
+	u.logger.Debugf("in usecase Suggest() group=%s, song=%s", group, song)
+
+	suggestions, err := u.lyricsRepo.Suggest(
+		ctx,
+		strings.TrimSpace(group),
+		strings.TrimSpace(song),
+		u.cfg.Search.SimilarityThreshold,
+		u.cfg.Search.SuggestLimit,
+	)
+	if err != nil {
+		u.logger.Errorf("Error fetching suggestions from repository: %v", err)
+		return models.Suggestions{}, err
+	}
+
+	return suggestions, nil
 }
@@ -1,2 +1,1161 @@
 package usecase_test
 
+import (
+	"context"
+	"database/sql"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/internal/lyrics/provider"
+	"github.com/22Fariz22/musiclab/internal/lyrics/usecase"
+	"github.com/22Fariz22/musiclab/internal/models"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis хранит значения в памяти и отвечает на команды через hook go-redis,
+// поэтому клиент никогда не открывает сетевое соединение
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newFakeRedis() (*redis.Client, *fakeRedis) {
+	fake := &fakeRedis{data: map[string]string{}}
+	client := redis.NewClient(&redis.Options{Addr: "fake:6379"})
+	client.AddHook(fake)
+	return client, fake
+}
+
+func (f *fakeRedis) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, net.ErrClosed
+	}
+}
+
+func (f *fakeRedis) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			f.process(cmd)
+		}
+		return nil
+	}
+}
+
+func (f *fakeRedis) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		f.process(cmd)
+		return cmd.Err()
+	}
+}
+
+func (f *fakeRedis) process(cmd redis.Cmder) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	args := cmd.Args()
+	switch c := cmd.(type) {
+	case *redis.StringCmd: // GET
+		val, ok := f.data[args[1].(string)]
+		if !ok {
+			c.SetErr(redis.Nil)
+			return
+		}
+		c.SetVal(val)
+	case *redis.StatusCmd: // SET
+		f.data[args[1].(string)] = toString(args[2])
+		c.SetVal("OK")
+	case *redis.IntCmd: // DEL
+		var deleted int64
+		for _, key := range args[1:] {
+			if _, ok := f.data[key.(string)]; ok {
+				delete(f.data, key.(string))
+				deleted++
+			}
+		}
+		c.SetVal(deleted)
+	}
+}
+
+func (f *fakeRedis) get(key string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	val, ok := f.data[key]
+	return val, ok
+}
+
+func (f *fakeRedis) set(key, val string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = val
+}
+
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	}
+	return ""
+}
+
+// fakeRepo хранит песни в памяти, нереализованные методы паникуют через встроенный интерфейс
+type fakeRepo struct {
+	lyrics.Repository
+
+	mu       sync.Mutex
+	songs    map[uint]models.Song
+	jobs     []models.EnrichmentJob
+	getCalls int
+
+	importBatches int
+
+	translations map[uint]map[string]models.SongTranslation
+
+	genres []models.GenreInfo
+
+	deleteGroupResult models.GroupDeleteResult
+	mergeResult       models.GroupMergeResult
+	mergeStrategy     string
+
+	exportFetchSize int
+
+	completeErr error
+
+	suggestArgs []interface{}
+}
+
+func newFakeRepo(songs ...models.Song) *fakeRepo {
+	r := &fakeRepo{songs: map[uint]models.Song{}}
+	for _, s := range songs {
+		r.songs[s.ID] = s
+	}
+	return r
+}
+
+func (r *fakeRepo) GetSongByID(ctx context.Context, id uint) (models.Song, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.getCalls++
+	song, ok := r.songs[id]
+	if !ok {
+		return models.Song{}, sql.ErrNoRows
+	}
+	return song, nil
+}
+
+func (r *fakeRepo) SaveTranslation(ctx context.Context, songID uint, language, text string) (models.SongTranslation, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.songs[songID]; !ok {
+		return models.SongTranslation{}, false, sql.ErrNoRows
+	}
+	if r.translations == nil {
+		r.translations = map[uint]map[string]models.SongTranslation{}
+	}
+	if r.translations[songID] == nil {
+		r.translations[songID] = map[string]models.SongTranslation{}
+	}
+	_, exists := r.translations[songID][language]
+	translation := models.SongTranslation{SongID: songID, Language: language, Text: text}
+	r.translations[songID][language] = translation
+	return translation, !exists, nil
+}
+
+func (r *fakeRepo) GetTranslations(ctx context.Context, songID uint) ([]models.SongTranslation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	translations := []models.SongTranslation{}
+	for _, translation := range r.translations[songID] {
+		translations = append(translations, translation)
+	}
+	return translations, nil
+}
+
+func (r *fakeRepo) GetTranslation(ctx context.Context, songID uint, language string) (models.SongTranslation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	translation, ok := r.translations[songID][language]
+	if !ok {
+		return models.SongTranslation{}, sql.ErrNoRows
+	}
+	return translation, nil
+}
+
+func (r *fakeRepo) UpdateTrackByID(ctx context.Context, updateData models.UpdateTrackRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	song, ok := r.songs[updateData.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if updateData.Text != nil {
+		song.Text = *updateData.Text
+	}
+	if updateData.Language != nil && *updateData.Language != "" {
+		song.Language, song.LanguageConfidence, song.LanguageManual = updateData.Language, nil, true
+	} else if detected := updateData.DetectedLanguage; detected != nil && (!song.LanguageManual || updateData.Language != nil) {
+		song.Language, song.LanguageConfidence, song.LanguageManual = detected.Language, detected.Confidence, false
+	}
+	r.songs[updateData.ID] = song
+	return nil
+}
+
+func (r *fakeRepo) DeleteSongByID(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.songs[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.songs, id)
+	return nil
+}
+
+func (r *fakeRepo) CreatePendingSong(ctx context.Context, song models.SongRequest) (models.EnrichmentJob, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, s := range r.songs {
+		if s.GroupName == song.Group && s.SongName == song.Song {
+			return models.EnrichmentJob{SongID: id}, false, nil
+		}
+	}
+
+	songID := uint(len(r.songs) + 1)
+	s := models.Song{ID: songID, GroupName: song.Group, SongName: song.Song, Status: models.SongStatusPending}
+	for _, artist := range song.Artists {
+		s.Artists = append(s.Artists, models.SongArtistInfo{Name: artist.Name, Role: artist.Role})
+	}
+	r.songs[songID] = s
+
+	job := models.EnrichmentJob{ID: uint(len(r.jobs) + 1), SongID: songID, Status: models.JobStatusQueued}
+	r.jobs = append(r.jobs, job)
+	return job, true, nil
+}
+
+func (r *fakeRepo) ClaimEnrichmentJob(ctx context.Context, staleAfter time.Duration, maxAttempts int) (models.EnrichmentJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, job := range r.jobs {
+		if job.Status == models.JobStatusQueued {
+			r.jobs[i].Status = models.JobStatusRunning
+			r.jobs[i].Attempts++
+			return r.jobs[i], nil
+		}
+	}
+	return models.EnrichmentJob{}, sql.ErrNoRows
+}
+
+func (r *fakeRepo) CompleteEnrichmentJob(ctx context.Context, job models.EnrichmentJob, songDetail models.SongDetail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.completeErr != nil {
+		return r.completeErr
+	}
+	s := r.songs[job.SongID]
+	if s.Text == "" {
+		s.Text = songDetail.Text
+	}
+	s.Source = &songDetail.Source
+	s.Status = models.SongStatusEnriched
+	r.songs[job.SongID] = s
+	r.jobs[job.ID-1].Status = models.JobStatusDone
+	return nil
+}
+
+func (r *fakeRepo) FailEnrichmentJob(ctx context.Context, job models.EnrichmentJob, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.songs[job.SongID]
+	s.Status = models.SongStatusFailed
+	s.EnrichError = &reason
+	r.songs[job.SongID] = s
+	r.jobs[job.ID-1].Status = models.JobStatusFailed
+	r.jobs[job.ID-1].Error = &reason
+	return nil
+}
+
+func (r *fakeRepo) GetSongsForRefresh(ctx context.Context, staleBefore, retryBefore time.Time, limit int) ([]models.Song, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	songs := []models.Song{}
+	for id := uint(1); id <= uint(len(r.songs)) && len(songs) < limit; id++ {
+		if s, ok := r.songs[id]; ok && s.Text == "" {
+			songs = append(songs, s)
+		}
+	}
+	return songs, nil
+}
+
+func (r *fakeRepo) ApplySongRefresh(ctx context.Context, songID uint, songDetail models.SongDetail) ([]models.SongChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.songs[songID]
+	if s.Text == songDetail.Text {
+		return nil, nil
+	}
+	s.Text = songDetail.Text
+	r.songs[songID] = s
+	return []models.SongChange{{SongID: songID, Field: "text", NewValue: &songDetail.Text}}, nil
+}
+
+func (r *fakeRepo) MarkSongRefreshed(ctx context.Context, songID uint) error {
+	return nil
+}
+
+func (r *fakeRepo) ImportSongs(ctx context.Context, rows []models.ImportRow, opts models.ImportOptions) ([]models.ImportRowResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.importBatches++
+
+	results := make([]models.ImportRowResult, 0, len(rows))
+	for _, row := range rows {
+		songID := uint(len(r.songs) + 1)
+		song := models.Song{ID: songID, GroupName: row.Group, SongName: row.Song, Text: row.Text, Source: ptr(row.Source),
+			Language: row.Language.Language, LanguageConfidence: row.Language.Confidence, Status: models.SongStatusEnriched}
+		result := models.ImportRowResult{Group: row.Group, Song: row.Song, Status: models.ImportCreated, SongID: &songID}
+
+		if opts.Enrich && (row.Text == "" || row.Link == "" || row.ReleaseDate == "") {
+			song.Status = models.SongStatusPending
+			job := models.EnrichmentJob{ID: uint(len(r.jobs) + 1), SongID: songID, Status: models.JobStatusQueued}
+			r.jobs = append(r.jobs, job)
+			result.JobID = &job.ID
+		}
+
+		r.songs[songID] = song
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (r *fakeRepo) SetSongLRC(ctx context.Context, id uint, lrc *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.songs[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	s.LRC = lrc
+	r.songs[id] = s
+	return nil
+}
+
+func (r *fakeRepo) DeleteGroupByID(ctx context.Context, id uint, cascade bool) (models.GroupDeleteResult, error) {
+	return r.deleteGroupResult, nil
+}
+
+func (r *fakeRepo) MergeGroups(ctx context.Context, sourceID, targetID uint, strategy string) (models.GroupMergeResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mergeStrategy = strategy
+	return r.mergeResult, nil
+}
+
+func (r *fakeRepo) GetGenres(ctx context.Context) ([]models.GenreInfo, error) {
+	return r.genres, nil
+}
+
+func (r *fakeRepo) ExportLibrary(ctx context.Context, filter models.LibraryFilter, fetchSize int, fn func(models.Song) error) error {
+	r.mu.Lock()
+	r.exportFetchSize = fetchSize
+	songs := []models.Song{}
+	for id := uint(1); id <= uint(len(r.songs)); id++ {
+		songs = append(songs, r.songs[id])
+	}
+	r.mu.Unlock()
+
+	for _, song := range songs {
+		if err := fn(song); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) Suggest(ctx context.Context, group, song string, threshold float64, limit int) (models.Suggestions, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.suggestArgs = []interface{}{group, song, threshold, limit}
+	return models.Suggestions{Groups: []models.Suggestion{}, Songs: []models.Suggestion{}}, nil
+}
+
+func (r *fakeRepo) song(id uint) models.Song {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.songs[id]
+}
+
+func (r *fakeRepo) calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.getCalls
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Redis:      config.RedisConfig{SongTextCasheTTL: time.Hour},
+		Enrichment: config.EnrichmentConfig{JobTimeout: time.Minute},
+		Language:   config.LanguageConfig{MinConfidence: 0.3},
+		API: config.APIConfig{
+			MaxRetries:    1,
+			RetryDelay:    time.Millisecond,
+			APIPath:       "/info",
+			APICtxTimeout: time.Second,
+		},
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestGetSongVerseByID_UsesCache(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, Text: "first verse\n\nsecond verse"})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	verse, err := uc.GetSongVerseByID(ctx, 1, 2, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "second verse", verse.Verse)
+
+	verse, err = uc.GetSongVerseByID(ctx, 1, 1, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "first verse", verse.Verse)
+	assert.Equal(t, 1, repo.calls(), "second read must be served from cache")
+}
+
+func TestUpdateTrackByID_InvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, Text: "old typo verse"})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	verse, err := uc.GetSongVerseByID(ctx, 1, 1, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "old typo verse", verse.Verse)
+
+	err = uc.UpdateTrackByID(ctx, models.UpdateTrackRequest{
+		ID:          1,
+		GroupName:   ptr("Muse"),
+		SongName:    ptr("Uprising"),
+		ReleaseDate: ptr("16.07.2006"),
+		Text:        ptr("fixed verse"),
+	})
+	require.NoError(t, err)
+
+	_, cached := fake.get("song:1")
+	assert.False(t, cached, "update must evict cached text")
+
+	verse, err = uc.GetSongVerseByID(ctx, 1, 1, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "fixed verse", verse.Verse)
+}
+
+func TestUpdateTrackByID_KeepsCacheOnError(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedis()
+	fake.set("song:2", "cached verse")
+	uc := usecase.NewLyricsUseCase(testConfig(), newFakeRepo(), provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	err := uc.UpdateTrackByID(ctx, models.UpdateTrackRequest{ID: 2, GroupName: ptr("Muse"), SongName: ptr("Uprising")})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, cached := fake.get("song:2")
+	assert.True(t, cached)
+}
+
+func TestDeleteSongByID_InvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, Text: "verse"})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	_, err := uc.GetSongVerseByID(ctx, 1, 1, models.VersePaging{}, "")
+	require.NoError(t, err)
+
+	require.NoError(t, uc.DeleteSongByID(ctx, 1))
+
+	_, cached := fake.get("song:1")
+	assert.False(t, cached, "delete must evict cached text")
+
+	_, err = uc.GetSongVerseByID(ctx, 1, 1, models.VersePaging{}, "")
+	assert.ErrorIs(t, err, sql.ErrNoRows, "deleted song must not be served from cache")
+}
+
+func TestDeleteSongByID_NotFoundStillEvicts(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedis()
+	fake.set("song:7", "stale verse")
+	uc := usecase.NewLyricsUseCase(testConfig(), newFakeRepo(), provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	err := uc.DeleteSongByID(ctx, 7)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, cached := fake.get("song:7")
+	assert.False(t, cached)
+}
+
+func TestCreateTrack_EnrichmentInvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+
+	lyricsProvider := provider.NewStaticProvider().Add("Muse", "Uprising", models.SongDetail{
+		ReleaseDate: "16.07.2006",
+		Text:        "fresh verse",
+		Link:        "https://example.com",
+	})
+
+	client, fake := newFakeRedis()
+	repo := newFakeRepo()
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewChain(utils.CreateTestLogger(), lyricsProvider), client, utils.CreateTestLogger())
+
+	job, created, err := uc.CreateTrack(ctx, models.SongRequest{Group: "Muse", Song: "Uprising"})
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, uint(1), job.SongID)
+	assert.Equal(t, models.JobStatusQueued, job.Status)
+	assert.Equal(t, models.SongStatusPending, repo.song(1).Status)
+
+	// Пустой текст попал в кэш, пока песня ждала в очереди
+	fake.set("song:1", "")
+
+	processed, err := uc.ProcessEnrichmentJob(ctx)
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	song := repo.song(1)
+	assert.Equal(t, models.SongStatusEnriched, song.Status)
+	require.NotNil(t, song.Source)
+	assert.Equal(t, provider.StaticName, *song.Source)
+	assert.Equal(t, "fresh verse", song.Text)
+
+	_, cached := fake.get("song:1")
+	assert.False(t, cached, "enrichment must drop cached text")
+
+	processed, err = uc.ProcessEnrichmentJob(ctx)
+	require.NoError(t, err)
+	assert.False(t, processed, "queue must be empty")
+}
+
+func TestCreateTrack_SkipsExistingSong(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, GroupName: "Muse", SongName: "Uprising", Text: "saved verse", Status: models.SongStatusEnriched})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	_, err := uc.GetSongVerseByID(ctx, 1, 1, models.VersePaging{}, "")
+	require.NoError(t, err)
+
+	job, created, err := uc.CreateTrack(ctx, models.SongRequest{Group: "Muse", Song: "Uprising"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, uint(1), job.SongID)
+	assert.Zero(t, job.ID, "existing song must not be queued")
+
+	song := repo.song(1)
+	assert.Equal(t, "saved verse", song.Text)
+	assert.Equal(t, models.SongStatusEnriched, song.Status)
+
+	cachedText, cached := fake.get("song:1")
+	require.True(t, cached, "unchanged song must keep its cache")
+	assert.Equal(t, "saved verse", cachedText)
+
+	processed, err := uc.ProcessEnrichmentJob(ctx)
+	require.NoError(t, err)
+	assert.False(t, processed, "queue must be empty")
+}
+
+func TestProcessEnrichmentJob_RecordsProviderFailure(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	repo := newFakeRepo()
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewChain(utils.CreateTestLogger(), provider.NewStaticProvider()), client, utils.CreateTestLogger())
+
+	job, _, err := uc.CreateTrack(ctx, models.SongRequest{Group: "Muse", Song: "Unknown"})
+	require.NoError(t, err)
+
+	processed, err := uc.ProcessEnrichmentJob(ctx)
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	song := repo.song(job.SongID)
+	assert.Equal(t, models.SongStatusFailed, song.Status)
+	require.NotNil(t, song.EnrichError)
+	assert.Contains(t, *song.EnrichError, lyrics.ErrLyricsNotFound.Error())
+}
+
+func TestProcessEnrichmentJob_DropsReclaimedJob(t *testing.T) {
+	ctx := context.Background()
+
+	lyricsProvider := provider.NewStaticProvider().Add("Muse", "Uprising", models.SongDetail{Text: "fresh verse"})
+	client, fake := newFakeRedis()
+	repo := newFakeRepo()
+	repo.completeErr = lyrics.ErrEnrichmentJobLost
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewChain(utils.CreateTestLogger(), lyricsProvider), client, utils.CreateTestLogger())
+
+	_, _, err := uc.CreateTrack(ctx, models.SongRequest{Group: "Muse", Song: "Uprising"})
+	require.NoError(t, err)
+	fake.set("song:1", "new owner verse")
+
+	processed, err := uc.ProcessEnrichmentJob(ctx)
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	cachedText, cached := fake.get("song:1")
+	require.True(t, cached, "lost job must not touch the cache of the new owner")
+	assert.Equal(t, "new owner verse", cachedText)
+}
+
+func TestRefreshStaleSongs_FillsMissingText(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedis()
+	fake.set("song:1", "")
+
+	repo := newFakeRepo(
+		models.Song{ID: 1, GroupName: "Muse", SongName: "Uprising"},
+		models.Song{ID: 2, GroupName: "Muse", SongName: "Unknown"},
+		models.Song{ID: 3, GroupName: "Muse", SongName: "Starlight", Text: "complete"},
+	)
+	lyricsProvider := provider.NewStaticProvider().Add("Muse", "Uprising", models.SongDetail{Text: "fresh verse"})
+
+	cfg := testConfig()
+	cfg.Refresh.RatePerSecond = 1000
+	uc := usecase.NewLyricsUseCase(cfg, repo, provider.NewChain(utils.CreateTestLogger(), lyricsProvider), client, utils.CreateTestLogger())
+
+	report, err := uc.RefreshStaleSongs(ctx, 10)
+	require.NoError(t, err)
+	assert.False(t, report.Running)
+	assert.Equal(t, 2, report.Checked)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Failed)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, "text", report.Changes[0].Field)
+	assert.Equal(t, uint(2), report.Errors[0].SongID)
+
+	cachedText, _ := fake.get("song:1")
+	assert.Equal(t, "fresh verse", cachedText)
+
+	last, ok := uc.LastRefresh()
+	require.True(t, ok)
+	assert.Equal(t, report.Updated, last.Updated)
+}
+
+func TestImportSongs_CSVReportsEachRow(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	repo := newFakeRepo()
+	lyricsProvider := provider.NewStaticProvider().Add("Muse", "Uprising", models.SongDetail{Text: "fetched verse", Link: "https://example.com"})
+
+	cfg := testConfig()
+	cfg.Import.BatchSize = 2
+	uc := usecase.NewLyricsUseCase(cfg, repo, provider.NewChain(utils.CreateTestLogger(), lyricsProvider), client, utils.CreateTestLogger())
+
+	file := "Song,Group,Release_Date\n" +
+		"Uprising,Muse,2009\n" +
+		"\"Multi\nline\",Muse,\n" +
+		",Muse,\n" +
+		"Uprising,Muse,\n" +
+		"Starlight,Muse,32.13.2006\n" +
+		"Hysteria,Muse,01.12.2003\n"
+
+	report, err := uc.ImportSongs(ctx, models.ImportFormatCSV, strings.NewReader(file), models.ImportOptions{Enrich: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, report.Total)
+	assert.Equal(t, 3, report.Created)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, 2, repo.importBatches)
+
+	lines := []int{}
+	for _, row := range report.Rows {
+		lines = append(lines, row.Line)
+	}
+	assert.Equal(t, []int{2, 3, 5, 6, 7, 8}, lines)
+	assert.Equal(t, "duplicate of line 2", report.Rows[3].Error)
+
+	// Данные загружаются воркерами после ответа: импорт только ставит задачи
+	require.NotNil(t, report.Rows[0].JobID)
+	uprising := repo.song(*report.Rows[0].SongID)
+	assert.Equal(t, models.SongStatusPending, uprising.Status)
+	assert.Empty(t, uprising.Text, "import must not wait for sources")
+
+	processed, err := uc.ProcessEnrichmentJob(ctx)
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	uprising = repo.song(*report.Rows[0].SongID)
+	assert.Equal(t, "fetched verse", uprising.Text)
+}
+
+func TestImportSongs_WithoutEnrichQueuesNothing(t *testing.T) {
+	client, _ := newFakeRedis()
+	repo := newFakeRepo()
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	report, err := uc.ImportSongs(context.Background(), models.ImportFormatCSV, strings.NewReader("group,song\nMuse,Uprising\n"), models.ImportOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, report.Rows, 1)
+	assert.Nil(t, report.Rows[0].JobID)
+	assert.Equal(t, models.SongStatusEnriched, repo.song(1).Status)
+	assert.Empty(t, repo.jobs)
+}
+
+func TestImportSongs_SkipsDuplicatesAfterNormalization(t *testing.T) {
+	client, _ := newFakeRedis()
+	repo := newFakeRepo()
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	file := "Song,Group\n" +
+		"Don’t  Stop,  Beyoncé\n" +
+		"DON'T STOP,beyoncé\n"
+
+	report, err := uc.ImportSongs(context.Background(), models.ImportFormatCSV, strings.NewReader(file), models.ImportOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, "duplicate of line 2", report.Rows[1].Error)
+	assert.Equal(t, "Beyoncé", report.Rows[0].Group)
+	assert.Equal(t, "Don't Stop", report.Rows[0].Song)
+}
+
+func TestCreateTrack_SplitsFeaturedArtists(t *testing.T) {
+	client, _ := newFakeRedis()
+	repo := newFakeRepo()
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	job, _, err := uc.CreateTrack(context.Background(), models.SongRequest{
+		Group: "Daft Punk feat. Pharrell Williams",
+		Song:  "Get Lucky (ft. Nile Rodgers)",
+		Artists: []models.ArtistCredit{
+			{Name: "Pharrell  Williams"},
+			{Name: "Daft Punk", Role: models.ArtistRoleComposer},
+			{Name: "daft punk", Role: models.ArtistRolePrimary},
+		},
+	})
+	require.NoError(t, err)
+
+	song := repo.song(job.SongID)
+	assert.Equal(t, "Daft Punk", song.GroupName)
+	assert.Equal(t, "Get Lucky", song.SongName)
+	assert.Equal(t, []models.SongArtistInfo{
+		{Name: "Pharrell Williams", Role: models.ArtistRoleFeatured},
+		{Name: "Daft Punk", Role: models.ArtistRoleComposer},
+		{Name: "Nile Rodgers", Role: models.ArtistRoleFeatured},
+	}, song.Artists)
+}
+
+func TestDeleteGroupByID_InvalidatesDeletedSongs(t *testing.T) {
+	client, fake := newFakeRedis()
+	repo := newFakeRepo()
+	repo.deleteGroupResult = models.GroupDeleteResult{DeletedSongs: []uint{1, 2}, DeletedAlbums: 1}
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	for _, key := range []string{"song:1", "song:1:lrc", "song:2", "song:3"} {
+		fake.set(key, "cached")
+	}
+
+	result, err := uc.DeleteGroupByID(context.Background(), 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, result.DeletedSongs)
+
+	for _, key := range []string{"song:1", "song:1:lrc", "song:2"} {
+		_, ok := fake.get(key)
+		assert.False(t, ok, "%s should be invalidated", key)
+	}
+	_, ok := fake.get("song:3")
+	assert.True(t, ok, "song of another group should stay cached")
+}
+
+func TestMergeGroups_InvalidatesMovedAndConflictingSongs(t *testing.T) {
+	client, fake := newFakeRedis()
+	repo := newFakeRepo()
+	repo.mergeResult = models.GroupMergeResult{
+		MovedSongs: []uint{1},
+		Conflicts:  []models.GroupMergeConflict{{Song: "Uprising", KeptSongID: 2, RemovedSongID: 3}},
+	}
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	for _, key := range []string{"song:1", "song:2", "song:3:lrc", "song:4"} {
+		fake.set(key, "cached")
+	}
+
+	_, err := uc.MergeGroups(context.Background(), 10, models.GroupMergeRequest{Into: 20})
+	require.NoError(t, err)
+	assert.Equal(t, models.MergeKeepTarget, repo.mergeStrategy)
+
+	for _, key := range []string{"song:1", "song:2", "song:3:lrc"} {
+		_, ok := fake.get(key)
+		assert.False(t, ok, "%s should be invalidated", key)
+	}
+	_, ok := fake.get("song:4")
+	assert.True(t, ok, "song outside the merge should stay cached")
+}
+
+func TestMergeGroups_PassesStrategy(t *testing.T) {
+	client, _ := newFakeRedis()
+	repo := newFakeRepo()
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	_, err := uc.MergeGroups(context.Background(), 10, models.GroupMergeRequest{Into: 20, Strategy: models.MergeKeepNewest})
+	require.NoError(t, err)
+	assert.Equal(t, models.MergeKeepNewest, repo.mergeStrategy)
+}
+
+func TestGetGenres_BuildsTree(t *testing.T) {
+	client, _ := newFakeRedis()
+	repo := newFakeRepo()
+	rock, metal := uint(1), uint(3)
+	repo.genres = []models.GenreInfo{
+		{ID: 4, Name: "Black Metal", ParentID: &metal},
+		{ID: 5, Name: "Jazz"},
+		{ID: 3, Name: "Metal", ParentID: &rock},
+		{ID: 2, Name: "Punk", ParentID: &rock},
+		{ID: 1, Name: "Rock"},
+	}
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	genres, err := uc.GetGenres(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, genres, 2)
+	assert.Equal(t, "Jazz", genres[0].Name)
+	assert.Empty(t, genres[0].Children)
+	assert.Equal(t, "Rock", genres[1].Name)
+	require.Len(t, genres[1].Children, 2)
+	assert.Equal(t, "Metal", genres[1].Children[0].Name)
+	assert.Equal(t, "Punk", genres[1].Children[1].Name)
+	require.Len(t, genres[1].Children[0].Children, 1)
+	assert.Equal(t, "Black Metal", genres[1].Children[0].Children[0].Name)
+}
+
+func TestImportSongs_RejectsCSVWithoutRequiredColumns(t *testing.T) {
+	client, _ := newFakeRedis()
+	uc := usecase.NewLyricsUseCase(testConfig(), newFakeRepo(), provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	_, err := uc.ImportSongs(context.Background(), models.ImportFormatCSV, strings.NewReader("group,title\nMuse,Uprising\n"), models.ImportOptions{})
+	assert.ErrorIs(t, err, lyrics.ErrInvalidImportFile)
+}
+
+func TestGetSongVerseByID_TimesLinesFromLRC(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, Text: "Paranoia is in bloom\nThey will not force us\n\nAnother line\nThey will not force us"})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	verse, err := uc.GetSongVerseByID(ctx, 1, 2, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Nil(t, verse.Lines, "song without LRC has no timed lines")
+
+	_, err = uc.SetSongLRC(ctx, 1, "[00:05.00]Paranoia is in bloom\n[00:10.00]plain text")
+	require.NoError(t, err)
+	_, err = uc.SetSongLRC(ctx, 1, "no timestamps")
+	assert.ErrorIs(t, err, lyrics.ErrInvalidLRC)
+
+	_, err = uc.SetSongLRC(ctx, 1, "[00:05.00]Paranoia is in bloom\n[00:08.50][00:20.00]they will  not force us\n")
+	require.NoError(t, err)
+	_, cached := fake.get("song:1:lrc")
+	assert.False(t, cached, "saving LRC must evict cached LRC")
+
+	verse, err = uc.GetSongVerseByID(ctx, 1, 2, models.VersePaging{}, "")
+	require.NoError(t, err)
+	require.Len(t, verse.Lines, 2)
+	assert.Nil(t, verse.Lines[0].TimeMs, "line missing from LRC stays untimed")
+	require.NotNil(t, verse.Lines[1].TimeMs)
+	assert.Equal(t, int64(20000), *verse.Lines[1].TimeMs, "repeated line takes the time of its repeat")
+	assert.Equal(t, "00:20.00", *verse.Lines[1].Time)
+
+	require.NoError(t, uc.DeleteSongLRC(ctx, 1))
+	_, err = uc.GetSongLRC(ctx, 1)
+	assert.ErrorIs(t, err, lyrics.ErrNoLRC)
+}
+
+func TestGetSongSections_FromMarkers(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	text := "[Verse 1]\nfirst line\n[laughs]\n\nsecond stanza\n[Chorus: Muse]\nthey will not force us\n\n[Verse 2]\nanother verse\n\n[Chorus]\n"
+	repo := newFakeRepo(models.Song{ID: 1, Text: text})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	structure, err := uc.GetSongSections(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, structure.Marked)
+	require.Len(t, structure.Sections, 4)
+
+	assert.Equal(t, models.SectionVerse, structure.Sections[0].Type)
+	assert.Equal(t, []int{1, 2}, structure.Sections[0].Pages)
+	assert.Equal(t, "first line\n[laughs]\n\nsecond stanza", structure.Sections[0].Text)
+	assert.Equal(t, models.SectionChorus, structure.Sections[1].Type)
+	assert.Equal(t, "Chorus: Muse", structure.Sections[1].Label)
+	assert.Empty(t, structure.Sections[3].Pages)
+	require.NotNil(t, structure.Sections[3].RepeatOf)
+	assert.Equal(t, 2, *structure.Sections[3].RepeatOf)
+
+	verse, err := uc.GetSongVerseByID(ctx, 1, 3, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "they will not force us", verse.Verse)
+	assert.Equal(t, models.SectionChorus, verse.SectionType)
+}
+
+func TestGetSongSections_DetectsRepeatedChorus(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	text := "verse one\n\nla la la\nhey\n\nverse two\n\nLa la  la\nhey"
+	repo := newFakeRepo(models.Song{ID: 1, Text: text})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	structure, err := uc.GetSongSections(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, structure.Marked)
+
+	labels := []string{}
+	for _, section := range structure.Sections {
+		labels = append(labels, section.Label)
+	}
+	assert.Equal(t, []string{"Verse 1", "Chorus", "Verse 2", "Chorus"}, labels)
+	require.NotNil(t, structure.Sections[3].RepeatOf)
+	assert.Equal(t, 2, *structure.Sections[3].RepeatOf)
+
+	verse, err := uc.GetSongVerseByID(ctx, 1, 4, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.SectionChorus, verse.SectionType)
+	assert.Equal(t, "Chorus", verse.SectionLabel)
+}
+
+func TestGetSongVerseByID_PagingModes(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, Text: "one\ntwo\nthree\n\n[Chorus]\nfour\nfive"})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	verse, err := uc.GetSongVerseByID(ctx, 1, 1, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\nthree", verse.Verse)
+	assert.Equal(t, 2, verse.TotalPages)
+	assert.True(t, verse.HasNext)
+
+	verse, err = uc.GetSongVerseByID(ctx, 1, 2, models.VersePaging{Mode: models.VerseModeLines, Size: 2}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "three\n\nfour", verse.Verse)
+	assert.Equal(t, models.SectionVerse, verse.SectionType, "section comes from the first line of the page")
+	assert.Equal(t, 3, verse.TotalPages)
+
+	verse, err = uc.GetSongVerseByID(ctx, 1, 1, models.VersePaging{Mode: models.VerseModeVerses, Size: 5}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\nthree\n\nfour\nfive", verse.Verse)
+	assert.False(t, verse.HasNext)
+
+	// "one\ntwo\nthree" - 13 символов и не помещается в бюджет 11, пустая строка между строфами тоже считается
+	verse, err = uc.GetSongVerseByID(ctx, 1, 2, models.VersePaging{Mode: models.VerseModeChars, Size: 11}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "three\n\nfour", verse.Verse)
+	assert.Equal(t, 3, verse.TotalPages)
+
+	_, err = uc.GetSongVerseByID(ctx, 1, 4, models.VersePaging{Mode: models.VerseModeLines, Size: 2}, "")
+	assert.Error(t, err)
+}
+
+func TestGetSongVerses_ReturnsAllPages(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, Text: "first\n\nsecond\n\nthird"}, models.Song{ID: 2})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	verses, err := uc.GetSongVerses(ctx, 1, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, verses.Total)
+	require.Len(t, verses.Verses, 3)
+	assert.Equal(t, 3, verses.Verses[2].Page)
+	assert.Equal(t, "third", verses.Verses[2].Verse)
+	assert.Equal(t, 3, verses.Verses[0].TotalPages)
+
+	empty, err := uc.GetSongVerses(ctx, 2, models.VersePaging{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, empty.Total)
+	assert.NotNil(t, empty.Verses, "empty song returns an empty array, not null")
+
+	_, err = uc.GetSongVerses(ctx, 3, models.VersePaging{}, "")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestGetSongVerseByID_ReturnsTranslationSideBySide(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, Text: "one\ntwo\n\nthree\nfour"})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	_, created, err := uc.SaveTranslation(ctx, 1, "de", "eins\nzwei\n\ndrei\nvier")
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	verse, err := uc.GetSongVerseByID(ctx, 1, 2, models.VersePaging{}, "de")
+	require.NoError(t, err)
+	assert.Equal(t, "three\nfour", verse.Verse)
+	require.NotNil(t, verse.Translation)
+	assert.Equal(t, "drei\nvier", verse.Translation.Verse)
+
+	// Строки перевода совпадают по номерам, если страница режет строфу
+	verse, err = uc.GetSongVerseByID(ctx, 1, 2, models.VersePaging{Mode: models.VerseModeLines, Size: 3}, "de")
+	require.NoError(t, err)
+	assert.Equal(t, "four", verse.Verse)
+	assert.Equal(t, "vier", verse.Translation.Verse)
+
+	// Без перевода на запрошенный язык возвращается только оригинал
+	verse, err = uc.GetSongVerseByID(ctx, 1, 1, models.VersePaging{}, "fr")
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo", verse.Verse)
+	assert.Nil(t, verse.Translation)
+
+	song, err := uc.GetSongByID(ctx, 1, "de")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"de"}, song.Translations)
+	assert.Equal(t, "de", song.Language)
+	assert.Equal(t, "eins\nzwei\n\ndrei\nvier", song.Text)
+}
+
+func TestUpdateTrackByID_DetectsLanguageUnlessOverridden(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, Text: "old"})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	update := models.UpdateTrackRequest{ID: 1, GroupName: ptr("Muse"), SongName: ptr("Uprising"),
+		Text: ptr("They will not force us, they will stop degrading us, and we will be victorious")}
+	require.NoError(t, uc.UpdateTrackByID(ctx, update))
+	require.NotNil(t, repo.song(1).Language)
+	assert.Equal(t, "en", *repo.song(1).Language)
+	assert.NotNil(t, repo.song(1).LanguageConfidence)
+
+	// Закрепленный вручную язык не меняется вместе с текстом
+	update.Text, update.Language = nil, ptr("fr")
+	require.NoError(t, uc.UpdateTrackByID(ctx, update))
+	update.Text, update.Language = ptr("I know that you and me are in love, and it's all that I want"), nil
+	require.NoError(t, uc.UpdateTrackByID(ctx, update))
+	assert.Equal(t, "fr", *repo.song(1).Language)
+	assert.True(t, repo.song(1).LanguageManual)
+
+	// Пустой язык снимает закрепление и определяет язык по сохраненному тексту
+	update.Text, update.Language = nil, ptr("")
+	require.NoError(t, uc.UpdateTrackByID(ctx, update))
+	assert.Equal(t, "en", *repo.song(1).Language)
+	assert.False(t, repo.song(1).LanguageManual)
+}
+
+func TestImportSongs_DetectsLanguage(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedis()
+	repo := newFakeRepo()
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	file := "group,song,text\nKino,Gruppa krovi,\"Теплое место, но улицы ждут отпечатков наших ног, и я не знаю, что мне делать\"\nMuse,Intro,la la\n"
+	report, err := uc.ImportSongs(ctx, models.ImportFormatCSV, strings.NewReader(file), models.ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Created)
+
+	require.NotNil(t, repo.song(*report.Rows[0].SongID).Language)
+	assert.Equal(t, "ru", *repo.song(*report.Rows[0].SongID).Language)
+	assert.Nil(t, repo.song(*report.Rows[1].SongID).Language, "too short to detect")
+}
+
+func TestTriggerRefresh_StopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, _ := newFakeRedis()
+
+	repo := newFakeRepo(
+		models.Song{ID: 1, GroupName: "Muse", SongName: "Uprising"},
+		models.Song{ID: 2, GroupName: "Muse", SongName: "Starlight"},
+	)
+
+	// Второй песни прогон ждет почти 1000 секунд и завершается только отменой ctx
+	cfg := testConfig()
+	cfg.Refresh.RatePerSecond = 0.001
+	uc := usecase.NewLyricsUseCase(cfg, repo, provider.NewChain(utils.CreateTestLogger(), provider.NewStaticProvider()), client, utils.CreateTestLogger())
+
+	done, err := uc.TriggerRefresh(ctx, 10)
+	require.NoError(t, err)
+
+	_, err = uc.TriggerRefresh(ctx, 10)
+	assert.ErrorIs(t, err, lyrics.ErrRefreshInProgress)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("refresh did not stop after context cancellation")
+	}
+
+	last, ok := uc.LastRefresh()
+	require.True(t, ok)
+	assert.False(t, last.Running)
+}
+
+func TestExportLibrary_FetchSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		fetchSize int
+		want      int
+	}{
+		{name: "configured", fetchSize: 50, want: 50},
+		{name: "zero", fetchSize: 0, want: 1000},
+		{name: "negative", fetchSize: -5, want: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newFakeRedis()
+			repo := newFakeRepo(models.Song{ID: 1, GroupName: "Muse", SongName: "Uprising"})
+
+			cfg := testConfig()
+			cfg.Export.FetchSize = tt.fetchSize
+			uc := usecase.NewLyricsUseCase(cfg, repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+			exported := 0
+			err := uc.ExportLibrary(context.Background(), models.LibraryFilter{}, func(models.Song) error {
+				exported++
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 1, exported)
+			assert.Equal(t, tt.want, repo.exportFetchSize)
+		})
+	}
+}
+
+func TestSuggest_TrimsQueryAndPassesSearchConfig(t *testing.T) {
+	client, _ := newFakeRedis()
+	repo := newFakeRepo()
+
+	cfg := testConfig()
+	cfg.Search = config.SearchConfig{SimilarityThreshold: 0.4, SuggestLimit: 3}
+	uc := usecase.NewLyricsUseCase(cfg, repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	_, err := uc.Suggest(context.Background(), "  Muse\t", "   ")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"Muse", "", 0.4, 3}, repo.suggestArgs)
+}
+
+func TestGetSongByID_NotFound(t *testing.T) {
+	client, _ := newFakeRedis()
+	repo := newFakeRepo(models.Song{ID: 1, GroupName: "Muse", SongName: "Uprising"})
+	uc := usecase.NewLyricsUseCase(testConfig(), repo, provider.NewStaticProvider(), client, utils.CreateTestLogger())
+
+	// Обработчик отвечает 404 по sql.ErrNoRows, поэтому usecase не должен терять эту ошибку
+	_, err := uc.GetSongByID(context.Background(), 2, "")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	song, err := uc.GetSongByID(context.Background(), 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, "Uprising", song.Song)
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/22Fariz22/musiclab/internal/models"
+)
+
+// GetSongVerses все страницы куплетов песни выбранным способом разбиения
+// lang - язык перевода, который возвращается рядом с оригиналом; без перевода возвращается только оригинал.
+func (u lyricsUseCase) GetSongVerses(ctx context.Context, id uint, paging models.VersePaging, lang string) (models.SongVerses, error) {
+	u.logger.Debugf("in usecase GetSongVerses() ID:%d, paging: %+v, lang: %s", id, paging, lang)
+
+	paging, err := versePagingOrDefault(paging)
+	if err != nil {
+		return models.SongVerses{}, err
+	}
+
+	songText, songLRC, err := u.songLyrics(ctx, id)
+	if err != nil {
+		return models.SongVerses{}, err
+	}
+
+	translation, err := u.translationFor(ctx, id, lang)
+	if err != nil {
+		return models.SongVerses{}, err
+	}
+
+	verses := models.SongVerses{
+		SongID: id,
+		Mode:   paging.Mode,
+		Size:   paging.Size,
+		Verses: u.versePages(id, songText, songLRC, paging, translation),
+	}
+	verses.Total = len(verses.Verses)
+	if translation != nil {
+		verses.Language = translation.Language
+	}
+
+	return verses, nil
+}
+
+// versePagingOrDefault не заданный способ разбиения - одна строфа на странице
+func versePagingOrDefault(paging models.VersePaging) (models.VersePaging, error) {
+	if paging.Mode != "" && paging.Size > 0 {
+		return paging, nil
+	}
+	return models.ParseVersePaging(paging.Mode, "")
+}
+
+// lyricsLine строка текста и ее место: номер строфы и строки в строфе
+type lyricsLine struct {
+	stanza int
+	line   int
+	text   string
+}
+
+// versePages разбивает текст на страницы выбранным способом.
+// Строфы на странице разделяются пустой строкой, тип и метка части берутся по первой строке страницы.
+// С переводом каждая страница получает переведенные строфы с теми же номерами.
+func (u lyricsUseCase) versePages(id uint, songText, songLRC string, paging models.VersePaging, translation *models.SongTranslation) []models.Verse {
+	stanzas := prepareLyrics(songText)
+	sections, _ := buildSections(songText)
+
+	var translated [][]string
+	if translation != nil {
+		for _, stanza := range prepareLyrics(translation.Text) {
+			translated = append(translated, strings.Split(stanza, "\n"))
+		}
+	}
+
+	var timed [][]models.VerseLine
+	if songLRC != "" {
+		timed = u.timeVerses(id, stanzas, songLRC)
+	}
+
+	var lines []lyricsLine
+	for i, stanza := range stanzas {
+		for j, text := range strings.Split(stanza, "\n") {
+			lines = append(lines, lyricsLine{stanza: i, line: j, text: text})
+		}
+	}
+
+	chunks := chunkLines(lines, paging)
+	pages := make([]models.Verse, 0, len(chunks))
+	for i, chunk := range chunks {
+		page := models.Verse{
+			Page:       i + 1,
+			Mode:       paging.Mode,
+			Size:       paging.Size,
+			TotalPages: len(chunks),
+			HasNext:    i+1 < len(chunks),
+		}
+
+		var text strings.Builder
+		for k, line := range chunk {
+			if k > 0 {
+				if line.stanza != chunk[k-1].stanza {
+					text.WriteString("\n\n")
+				} else {
+					text.WriteString("\n")
+				}
+			}
+			text.WriteString(line.text)
+
+			if timed != nil {
+				page.Lines = append(page.Lines, timed[line.stanza][line.line])
+			}
+		}
+		page.Verse = text.String()
+
+		if translation != nil {
+			page.Translation = &models.VerseTranslation{
+				Language: translation.Language,
+				Verse:    translatePage(chunk, stanzas, translated),
+			}
+		}
+
+		// Страницы частей считаются по строфам
+		if section, ok := sectionOfPage(sections, chunk[0].stanza+1); ok {
+			page.SectionType = section.Type
+			page.SectionLabel = section.Label
+		}
+
+		pages = append(pages, page)
+	}
+
+	return pages
+}
+
+// translatePage перевод страницы. Строфы, попавшие на страницу целиком, переводятся целиком,
+// даже если в переводе другое число строк; от частично попавших строф берутся строки с теми же номерами.
+func translatePage(chunk []lyricsLine, stanzas []string, translated [][]string) string {
+	var result []string
+	for start := 0; start < len(chunk); {
+		stanza := chunk[start].stanza
+		end := start
+		for end < len(chunk) && chunk[end].stanza == stanza {
+			end++
+		}
+
+		if stanza < len(translated) {
+			if end-start == strings.Count(stanzas[stanza], "\n")+1 {
+				result = append(result, strings.Join(translated[stanza], "\n"))
+			} else {
+				var lines []string
+				for _, line := range chunk[start:end] {
+					if line.line < len(translated[stanza]) {
+						lines = append(lines, translated[stanza][line.line])
+					}
+				}
+				if len(lines) > 0 {
+					result = append(result, strings.Join(lines, "\n"))
+				}
+			}
+		}
+
+		start = end
+	}
+
+	return strings.Join(result, "\n\n")
+}
+
+// chunkLines делит строки на страницы: по size строф, по size строк или по бюджету в size символов.
+// В режиме chars строка длиннее бюджета занимает страницу целиком.
+func chunkLines(lines []lyricsLine, paging models.VersePaging) [][]lyricsLine {
+	var chunks [][]lyricsLine
+	var current []lyricsLine
+	var stanzas, chars int
+
+	for _, line := range lines {
+		switch paging.Mode {
+		case models.VerseModeLines:
+			if len(current) == paging.Size {
+				chunks, current = append(chunks, current), nil
+			}
+		case models.VerseModeChars:
+			// Переводы строк между строками и строфами тоже занимают место
+			separator := 0
+			if len(current) > 0 {
+				separator = 1
+				if line.stanza != current[len(current)-1].stanza {
+					separator = 2
+				}
+			}
+
+			length := utf8.RuneCountInString(line.text)
+			if len(current) > 0 && chars+separator+length > paging.Size {
+				chunks, current = append(chunks, current), nil
+				chars, separator = 0, 0
+			}
+			chars += separator + length
+		default:
+			if len(current) == 0 || line.stanza != current[len(current)-1].stanza {
+				if stanzas == paging.Size {
+					chunks, current = append(chunks, current), nil
+					stanzas = 0
+				}
+				stanzas++
+			}
+		}
+
+		current = append(current, line)
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
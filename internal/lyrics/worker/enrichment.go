@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/pkg/logger"
+)
+
+// EnrichmentPool воркеры, загружающие данные песен из очереди задач
+type EnrichmentPool struct {
+	cfg           *config.Config
+	lyricsUsecase lyrics.UseCase
+	logger        logger.Logger
+	wg            sync.WaitGroup
+}
+
+// NewEnrichmentPool пул из cfg.Enrichment.Workers воркеров
+func NewEnrichmentPool(cfg *config.Config, lyricsUsecase lyrics.UseCase, logger logger.Logger) *EnrichmentPool {
+	return &EnrichmentPool{cfg: cfg, lyricsUsecase: lyricsUsecase, logger: logger}
+}
+
+// Start запускает воркеры, они работают до отмены ctx
+func (p *EnrichmentPool) Start(ctx context.Context) {
+	p.logger.Infof("starting %d enrichment workers", p.cfg.Enrichment.Workers)
+
+	for i := 1; i <= p.cfg.Enrichment.Workers; i++ {
+		p.wg.Add(1)
+		go p.run(ctx, i)
+	}
+}
+
+// Wait ждет, пока воркеры закончат текущие задачи после отмены ctx
+func (p *EnrichmentPool) Wait() {
+	p.wg.Wait()
+}
+
+// run обрабатывает задачи подряд, пока очередь не опустеет, затем ждет PollInterval
+func (p *EnrichmentPool) run(ctx context.Context, id int) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.Enrichment.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		processed, err := p.process(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.logger.Errorf("enrichment worker %d: %v", id, err)
+		}
+
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			p.logger.Debugf("enrichment worker %d stopped", id)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// process обрабатывает одну задачу, паника не останавливает воркер
+func (p *EnrichmentPool) process(ctx context.Context) (processed bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			processed, err = true, fmt.Errorf("recovered from panic: %v", r)
+		}
+	}()
+
+	return p.lyricsUsecase.ProcessEnrichmentJob(ctx)
+}
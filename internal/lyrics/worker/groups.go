@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/pkg/logger"
+)
+
+// GroupCleanupScheduler периодически удаляет группы, у которых не осталось песен
+type GroupCleanupScheduler struct {
+	cfg           *config.Config
+	lyricsUsecase lyrics.UseCase
+	logger        logger.Logger
+	wg            sync.WaitGroup
+}
+
+// NewGroupCleanupScheduler планировщик с интервалом cfg.Groups.CleanupInterval
+func NewGroupCleanupScheduler(cfg *config.Config, lyricsUsecase lyrics.UseCase, logger logger.Logger) *GroupCleanupScheduler {
+	return &GroupCleanupScheduler{cfg: cfg, lyricsUsecase: lyricsUsecase, logger: logger}
+}
+
+// Start запускает планировщик до отмены ctx, нулевой интервал отключает его
+func (s *GroupCleanupScheduler) Start(ctx context.Context) {
+	if s.cfg.Groups.CleanupInterval <= 0 {
+		s.logger.Info("group cleanup scheduler is disabled")
+		return
+	}
+	s.logger.Infof("starting group cleanup scheduler, interval %s", s.cfg.Groups.CleanupInterval)
+
+	s.wg.Add(1)
+	go s.run(ctx)
+}
+
+// Wait ждет завершения текущей очистки после отмены ctx
+func (s *GroupCleanupScheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *GroupCleanupScheduler) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.Groups.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Debug("group cleanup scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.lyricsUsecase.CleanupOrphanGroups(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Errorf("scheduled group cleanup failed: %v", err)
+		}
+	}
+}
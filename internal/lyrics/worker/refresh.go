@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics"
+	"github.com/22Fariz22/musiclab/pkg/logger"
+)
+
+// RefreshScheduler периодически обновляет устаревшие и неполные песни
+type RefreshScheduler struct {
+	cfg           *config.Config
+	lyricsUsecase lyrics.UseCase
+	logger        logger.Logger
+	wg            sync.WaitGroup
+
+	// ctx время жизни сервера для ручных прогонов, stopped - после Wait новые прогоны не запускаются
+	mu      sync.Mutex
+	ctx     context.Context
+	stopped bool
+}
+
+// NewRefreshScheduler планировщик с интервалом cfg.Refresh.Interval
+func NewRefreshScheduler(cfg *config.Config, lyricsUsecase lyrics.UseCase, logger logger.Logger) *RefreshScheduler {
+	return &RefreshScheduler{cfg: cfg, lyricsUsecase: lyricsUsecase, logger: logger}
+}
+
+// Start запускает планировщик до отмены ctx, нулевой интервал отключает его.
+// Ручной запуск через Trigger доступен и при отключенном расписании.
+func (s *RefreshScheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	if s.cfg.Refresh.Interval <= 0 {
+		s.logger.Info("refresh scheduler is disabled")
+		return
+	}
+	s.logger.Infof("starting refresh scheduler, interval %s", s.cfg.Refresh.Interval)
+
+	s.wg.Add(1)
+	go s.run(ctx)
+}
+
+// Trigger запускает прогон вне расписания с контекстом из Start, Wait дожидается и его
+func (s *RefreshScheduler) Trigger(limit int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx == nil || s.stopped {
+		return lyrics.ErrRefreshUnavailable
+	}
+
+	done, err := s.lyricsUsecase.TriggerRefresh(s.ctx, limit)
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		<-done
+	}()
+	return nil
+}
+
+// Wait ждет завершения текущего прогона, в том числе ручного, после отмены ctx
+func (s *RefreshScheduler) Wait() {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *RefreshScheduler) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.Refresh.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Debug("refresh scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+
+		_, err := s.lyricsUsecase.RefreshStaleSongs(ctx, s.cfg.Refresh.BatchSize)
+		switch {
+		case errors.Is(err, lyrics.ErrRefreshInProgress):
+			s.logger.Debug("refresh is already running, skipping scheduled run")
+		case err != nil && ctx.Err() == nil:
+			s.logger.Errorf("scheduled refresh failed: %v", err)
+		}
+	}
+}
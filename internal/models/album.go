@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// AlbumRequest создание или изменение альбома
+// @Description Request payload for creating or updating an album
+type AlbumRequest struct {
+	// Group name of the album
+	// Required: true
+	// Min length: 1
+	Group string `json:"group" validate:"required,min=1"`
+
+	// Album title
+	// Required: true
+	// Min length: 1
+	Title string `json:"title" validate:"required,min=1"`
+
+	// Release date of the album: dd.mm.yyyy, yyyy-mm-dd or yyyy
+	ReleaseDate string `json:"release_date" validate:"omitempty,release_date"`
+}
+
+// AlbumTrackRequest песня и ее номер в альбоме
+// @Description Song to attach to an album with its track number
+type AlbumTrackRequest struct {
+	// ID of the song
+	// Required: true
+	SongID uint `json:"song_id" validate:"required"`
+
+	// Track number on the album, starting from 1
+	// Required: true
+	TrackNumber int `json:"track_number" validate:"required,min=1"`
+}
+
+// AlbumTracksRequest привязка песен к альбому
+// @Description Request payload for attaching songs to an album
+type AlbumTracksRequest struct {
+	// Songs with track numbers
+	// Required: true
+	Tracks []AlbumTrackRequest `json:"tracks" validate:"required,min=1,dive"`
+}
+
+// AlbumTrack песня в составе альбома
+// @Description Song of an album in track order
+type AlbumTrack struct {
+	// ID of the song
+	SongID uint `json:"song_id" db:"song_id"`
+
+	// Name of the song
+	SongName string `json:"song" db:"song_name"`
+
+	// Track number on the album
+	TrackNumber *int `json:"track_number" db:"track_number"`
+}
+
+// Album модель базы данных
+// @Description Database model for an album
+type Album struct {
+	// ID of the album
+	// Required: true
+	ID uint `gorm:"primaryKey" db:"id" json:"id"`
+
+	// ID of the associated group
+	// Required: true
+	GroupID uint `gorm:"not null;index;uniqueIndex:idx_group_album,priority:1" db:"group_id" json:"group_id"`
+
+	// Associated group
+	Group Group `gorm:"foreignKey:GroupID" json:"-"`
+
+	// Group name
+	GroupName string `gorm:"-" db:"group_name" json:"group"`
+
+	// Album title
+	// Required: true
+	Title string `gorm:"type:varchar(255);not null;uniqueIndex:idx_group_album,priority:2;index" db:"title" json:"title"`
+
+	// Release date of the album
+	ReleaseDate *Date `gorm:"type:date" db:"release_date" json:"release_date"`
+
+	// Number of songs on the album
+	TrackCount int `gorm:"-" db:"track_count" json:"track_count"`
+
+	// Songs in track order
+	Tracks []AlbumTrack `gorm:"-" json:"tracks,omitempty"`
+
+	// Creation timestamp
+	// Required: true
+	CreatedAt time.Time `gorm:"index" db:"created_at" json:"created_at"`
+
+	// Update timestamp
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Роли участников песни
+const (
+	// ArtistRolePrimary исполнитель; основной исполнитель песни - ее группа
+	ArtistRolePrimary = "primary"
+
+	// ArtistRoleFeatured приглашенный участник (feat.)
+	ArtistRoleFeatured = "featured"
+
+	// ArtistRoleComposer композитор
+	ArtistRoleComposer = "composer"
+
+	// ArtistRoleLyricist автор текста
+	ArtistRoleLyricist = "lyricist"
+)
+
+// ArtistCredit участник песни в запросе
+// @Description Artist credited on a song in addition to its group
+type ArtistCredit struct {
+	// Artist (group) name, created if it does not exist
+	// Required: true
+	Name string `json:"name" validate:"required,min=1,max=255"`
+
+	// Role: primary, featured (default), composer or lyricist
+	Role string `json:"role,omitempty" validate:"omitempty,oneof=primary featured composer lyricist"`
+}
+
+// SongArtist участник песни помимо ее группы
+// @Description Database model for an artist credited on a song
+type SongArtist struct {
+	// ID of the song
+	SongID uint `gorm:"primaryKey;autoIncrement:false" db:"song_id"`
+
+	// ID of the credited group
+	GroupID uint `gorm:"primaryKey;autoIncrement:false;index" db:"group_id"`
+
+	// Role of the artist on the song
+	Role string `gorm:"primaryKey;type:varchar(20)" db:"role"`
+
+	// Order of the credit on the song
+	Position int `gorm:"not null;default:0" db:"position"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// SongArtistInfo участник песни в ответе
+// @Description Artist credited on a song
+type SongArtistInfo struct {
+	// ID of the group
+	GroupID uint `json:"group_id" db:"group_id"`
+
+	// Name of the group
+	Name string `json:"name" db:"name"`
+
+	// Role: primary, featured, composer or lyricist
+	Role string `json:"role" db:"role"`
+}
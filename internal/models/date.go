@@ -0,0 +1,73 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateLayout формат даты в ответах API
+const DateLayout = "2006-01-02"
+
+// Date дата без времени (колонка DATE), в JSON передается как YYYY-MM-DD
+type Date struct {
+	time.Time
+}
+
+// NewDate дата без учета времени и часового пояса
+func NewDate(t time.Time) Date {
+	return Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return err
+	}
+
+	*d = NewDate(t)
+	return nil
+}
+
+// Scan implements sql.Scanner
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = NewDate(v)
+	case string:
+		return d.parse(v)
+	case []byte:
+		return d.parse(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into Date", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer
+func (d Date) Value() (driver.Value, error) {
+	return d.Time, nil
+}
+
+func (d *Date) parse(value string) error {
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return err
+	}
+	*d = NewDate(t)
+	return nil
+}
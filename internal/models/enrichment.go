@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Состояния песни: данные о песне загружаются из источников в фоне
+const (
+	SongStatusPending  = "pending"
+	SongStatusEnriched = "enriched"
+	SongStatusFailed   = "failed"
+)
+
+// Состояния задачи обогащения
+const (
+	JobStatusQueued  = "queued"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+// EnrichmentJob задача загрузки данных песни из источников
+// @Description Background job that fetches song data from lyrics providers
+type EnrichmentJob struct {
+	// ID of the job
+	ID uint `gorm:"primaryKey" db:"id" json:"id"`
+
+	// ID of the song being enriched
+	SongID uint `gorm:"not null;index" db:"song_id" json:"song_id"`
+
+	// queued, running, done or failed
+	Status string `gorm:"type:varchar(20);not null;index:idx_enrichment_jobs_claim,priority:1" db:"status" json:"status"`
+
+	// Number of times a worker picked the job up
+	Attempts int `gorm:"not null;default:0" db:"attempts" json:"attempts"`
+
+	// Error of the last attempt
+	Error *string `gorm:"type:text" db:"error" json:"error,omitempty"`
+
+	// When a worker picked the job up
+	StartedAt *time.Time `gorm:"index:idx_enrichment_jobs_claim,priority:2" db:"started_at" json:"started_at,omitempty"`
+
+	// When the job finished
+	FinishedAt *time.Time `db:"finished_at" json:"finished_at,omitempty"`
+
+	// Creation timestamp
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	// Update timestamp
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// Форматы выгрузки библиотеки
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatJSON   = "json"
+)
+
+// exportCSVColumns колонки CSV для полей параметра fields, в порядке LibraryFields
+var exportCSVColumns = map[string][]string{
+	"id":           {"id"},
+	"group":        {"group_id", "group"},
+	"song":         {"song"},
+	"release_date": {"release_date"},
+	"text":         {"text"},
+	"link":         {"link"},
+	"album":        {"album_id", "album", "track_number"},
+	"source":       {"source"},
+	"language":     {"language", "language_confidence", "language_manual"},
+	"status":       {"status", "enrich_error"},
+	"created_at":   {"created_at"},
+	"updated_at":   {"updated_at"},
+}
+
+// ExportCSVHeader заголовок CSV для запрошенных полей, без полей - все
+func ExportCSVHeader(fields []string) []string {
+	if len(fields) == 0 {
+		fields = LibraryFields
+	}
+
+	header := []string{}
+	for _, field := range fields {
+		header = append(header, exportCSVColumns[field]...)
+	}
+	return header
+}
+
+// CSVRecord строка CSV в порядке колонок ExportCSVHeader, пустые значения - пустые ячейки
+func (s Song) CSVRecord(fields []string) []string {
+	if len(fields) == 0 {
+		fields = LibraryFields
+	}
+
+	record := []string{}
+	for _, field := range fields {
+		switch field {
+		case "id":
+			record = append(record, strconv.FormatUint(uint64(s.ID), 10))
+		case "group":
+			record = append(record, strconv.FormatUint(uint64(s.GroupID), 10), s.GroupName)
+		case "song":
+			record = append(record, s.SongName)
+		case "release_date":
+			releaseDate := ""
+			if s.ReleaseDate != nil {
+				releaseDate = s.ReleaseDate.String()
+			}
+			record = append(record, releaseDate)
+		case "text":
+			record = append(record, s.Text)
+		case "link":
+			record = append(record, stringValue(s.Link))
+		case "album":
+			albumID, trackNumber := "", ""
+			if s.AlbumID != nil {
+				albumID = strconv.FormatUint(uint64(*s.AlbumID), 10)
+			}
+			if s.TrackNumber != nil {
+				trackNumber = strconv.Itoa(*s.TrackNumber)
+			}
+			record = append(record, albumID, stringValue(s.AlbumTitle), trackNumber)
+		case "source":
+			record = append(record, stringValue(s.Source))
+		case "language":
+			confidence := ""
+			if s.LanguageConfidence != nil {
+				confidence = strconv.FormatFloat(*s.LanguageConfidence, 'f', -1, 64)
+			}
+			record = append(record, stringValue(s.Language), confidence, strconv.FormatBool(s.LanguageManual))
+		case "status":
+			record = append(record, s.Status, stringValue(s.EnrichError))
+		case "created_at":
+			record = append(record, s.CreatedAt.Format(time.RFC3339))
+		case "updated_at":
+			record = append(record, s.UpdatedAt.Format(time.RFC3339))
+		}
+	}
+	return record
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,112 @@
+package models
+
+import "time"
+
+// Genre жанр из справочника, жанры образуют дерево
+// @Description Database model for a genre
+type Genre struct {
+	ID uint `gorm:"primaryKey" db:"id"`
+
+	// Name of the genre
+	Name string `gorm:"type:varchar(100);not null" db:"name"`
+
+	// Normalized name without case, unique across genres
+	NameKey string `gorm:"type:text;not null;uniqueIndex" db:"name_key"`
+
+	// Parent genre, nil for a top-level genre
+	ParentID *uint `gorm:"index" db:"parent_id"`
+
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// SongGenre жанр песни
+type SongGenre struct {
+	SongID  uint `gorm:"primaryKey;autoIncrement:false" db:"song_id"`
+	GenreID uint `gorm:"primaryKey;autoIncrement:false;index" db:"genre_id"`
+}
+
+// GenreRequest создание или изменение жанра
+// @Description Request payload for creating or changing a genre
+type GenreRequest struct {
+	// Genre name
+	// Required: true
+	Name string `json:"name" validate:"required,min=1,max=100"`
+
+	// Parent genre; without it the genre is top-level
+	ParentID *uint `json:"parent_id,omitempty"`
+}
+
+// GenreInfo жанр с количеством песен и поджанрами
+// @Description Genre with its song count and subgenres
+type GenreInfo struct {
+	// ID of the genre
+	ID uint `json:"id" db:"id"`
+
+	// Name of the genre
+	Name string `json:"name" db:"name"`
+
+	// Parent genre, null for a top-level genre
+	ParentID *uint `json:"parent_id" db:"parent_id"`
+
+	// Number of songs with the genre itself, without subgenres
+	SongCount int `json:"song_count" db:"song_count"`
+
+	// Subgenres
+	Children []GenreInfo `json:"children" db:"-"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Update timestamp
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SongGenresRequest добавление или удаление жанров у нескольких песен
+// @Description Request payload for attaching or detaching genres of songs in bulk
+type SongGenresRequest struct {
+	// IDs of the songs
+	// Required: true
+	SongIDs []uint `json:"song_ids" validate:"required,min=1,max=1000,dive,required"`
+
+	// IDs of the genres
+	// Required: true
+	GenreIDs []uint `json:"genre_ids" validate:"required,min=1,max=100,dive,required"`
+}
+
+// ClassificationResult итог изменения жанров или меток у песен
+// @Description Result of attaching or detaching genres or tags in bulk
+type ClassificationResult struct {
+	// Number of song-genre or song-tag links added or removed
+	Changed int `json:"changed"`
+}
+
+// GenreTree собирает дерево жанров из плоского списка, порядок внутри уровня сохраняется
+func GenreTree(genres []GenreInfo) []GenreInfo {
+	children := map[uint][]GenreInfo{}
+	known := map[uint]bool{}
+	for _, genre := range genres {
+		known[genre.ID] = true
+	}
+
+	var roots []GenreInfo
+	for _, genre := range genres {
+		if genre.ParentID != nil && known[*genre.ParentID] {
+			children[*genre.ParentID] = append(children[*genre.ParentID], genre)
+		} else {
+			roots = append(roots, genre)
+		}
+	}
+
+	var build func(level []GenreInfo) []GenreInfo
+	build = func(level []GenreInfo) []GenreInfo {
+		result := make([]GenreInfo, len(level))
+		for i, genre := range level {
+			genre.Children = build(children[genre.ID])
+			result[i] = genre
+		}
+		return result
+	}
+
+	return build(roots)
+}
@@ -0,0 +1,139 @@
+package models
+
+import "time"
+
+// GroupRequest создание или изменение группы
+// @Description Request payload for creating, renaming or describing a group
+type GroupRequest struct {
+	// Group name
+	// Required: true
+	// Min length: 1
+	Name string `json:"name" validate:"required,min=1,max=255"`
+
+	// Description of the group
+	Description *string `json:"description,omitempty"`
+}
+
+// GroupInfo группа с количеством песен и альбомов
+// @Description Group (artist) with its song and album counts
+type GroupInfo struct {
+	// ID of the group
+	ID uint `json:"id" db:"id"`
+
+	// Name of the group
+	Name string `json:"name" db:"name"`
+
+	// Description of the group
+	Description *string `json:"description" db:"description"`
+
+	// Number of songs of the group
+	SongCount int `json:"song_count" db:"song_count"`
+
+	// Number of albums of the group
+	AlbumCount int `json:"album_count" db:"album_count"`
+
+	// Alternative spellings that resolve to the group
+	Aliases []string `json:"aliases" db:"-"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Update timestamp
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// GroupDeleteResult итог удаления группы
+// @Description Result of deleting a group
+type GroupDeleteResult struct {
+	// IDs of the songs deleted together with the group
+	DeletedSongs []uint `json:"deleted_songs"`
+
+	// Number of albums deleted together with the group
+	DeletedAlbums int `json:"deleted_albums"`
+}
+
+// GroupAlias другое написание имени группы
+// @Description Alternative spelling that resolves to a canonical group
+type GroupAlias struct {
+	ID uint `gorm:"primaryKey" db:"id" json:"-"`
+
+	// ID of the canonical group
+	GroupID uint `gorm:"not null;index" db:"group_id" json:"group_id"`
+
+	// Alternative name, unique across all aliases
+	Alias string `gorm:"type:varchar(255);not null;uniqueIndex" db:"alias" json:"alias"`
+
+	// Normalized alias without case
+	AliasKey string `gorm:"type:text;not null;uniqueIndex" db:"alias_key" json:"-"`
+
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// GroupAliasRequest добавление другого написания имени группы
+// @Description Request payload for adding a group alias
+type GroupAliasRequest struct {
+	// Alternative name of the group
+	// Required: true
+	Alias string `json:"alias" validate:"required,min=1,max=255"`
+}
+
+// Способы разрешить конфликт песен с одинаковым названием при слиянии групп
+const (
+	// MergeKeepTarget остается песня группы, в которую идет слияние
+	MergeKeepTarget = "keep_target"
+
+	// MergeKeepSource остается песня объединяемой группы
+	MergeKeepSource = "keep_source"
+
+	// MergeKeepNewest остается песня, измененная последней
+	MergeKeepNewest = "keep_newest"
+)
+
+// GroupMergeRequest слияние группы с другой
+// @Description Request payload for merging a group into another one
+type GroupMergeRequest struct {
+	// ID of the group that receives the songs
+	// Required: true
+	Into uint `json:"into" validate:"required"`
+
+	// How to resolve songs with the same name in both groups: keep_target (default), keep_source or keep_newest
+	Strategy string `json:"strategy" validate:"omitempty,oneof=keep_target keep_source keep_newest"`
+}
+
+// GroupMergeConflict песня с одинаковым названием в обеих группах
+// @Description Song present in both merged groups and how it was resolved
+type GroupMergeConflict struct {
+	// Name of the song
+	Song string `json:"song"`
+
+	// ID of the song that stays in the library
+	KeptSongID uint `json:"kept_song_id"`
+
+	// ID of the deleted duplicate
+	RemovedSongID uint `json:"removed_song_id"`
+
+	// Strategy that chose the kept song
+	Strategy string `json:"strategy"`
+
+	// Translations moved from the deleted duplicate
+	MovedTranslations int `json:"moved_translations"`
+}
+
+// GroupMergeResult итог слияния групп
+// @Description Result of merging a group into another one
+type GroupMergeResult struct {
+	// Resulting group
+	Group GroupInfo `json:"group"`
+
+	// IDs of the songs moved to the group
+	MovedSongs []uint `json:"moved_songs"`
+
+	// Number of albums moved to the group
+	MovedAlbums int `json:"moved_albums"`
+
+	// Number of albums merged into an album with the same title
+	MergedAlbums int `json:"merged_albums"`
+
+	// Songs present in both groups
+	Conflicts []GroupMergeConflict `json:"conflicts"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	// HealthOK все зависимости доступны
+	HealthOK = "ok"
+	// HealthDegraded чтение работает, но часть источников песен недоступна
+	HealthDegraded = "degraded"
+	// HealthUp зависимость доступна
+	HealthUp = "up"
+	// HealthDown зависимость недоступна
+	HealthDown = "down"
+)
+
+// Health состояние сервиса и его зависимостей
+// @Description Service health with database and lyrics providers status
+type Health struct {
+	// ok, degraded or down
+	Status string `json:"status"`
+
+	// Database status: up or down
+	Database string `json:"database"`
+
+	// Lyrics providers in priority order
+	Providers []ProviderHealth `json:"providers"`
+}
+
+// ProviderHealth состояние источника песен
+type ProviderHealth struct {
+	// Provider name
+	Name string `json:"name"`
+
+	// up or down
+	Status string `json:"status"`
+
+	// Circuit breaker of the provider, if it has one
+	Circuit *CircuitHealth `json:"circuit,omitempty"`
+}
+
+// CircuitHealth состояние автоматического выключателя
+type CircuitHealth struct {
+	// closed, open or half-open
+	State string `json:"state"`
+
+	// Failed requests in a row
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// When an open circuit lets the next probe request through
+	OpenUntil *time.Time `json:"open_until,omitempty"`
+}
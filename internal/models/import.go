@@ -0,0 +1,131 @@
+package models
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// Форматы файла импорта
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+// DetectImportFormat определяет формат по расширению файла, затем по Content-Type.
+// Пустая строка - формат определить не удалось.
+func DetectImportFormat(filename, contentType string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return ImportFormatCSV
+	case ".ndjson", ".jsonl":
+		return ImportFormatNDJSON
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return ImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return ImportFormatNDJSON
+	}
+
+	return ""
+}
+
+// ImportSource источник данных песен, загруженных импортом без обогащения
+const ImportSource = "import"
+
+// Результаты импорта строки
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportRow строка файла импорта (CSV или NDJSON)
+// @Description Song row of a bulk import file
+type ImportRow struct {
+	// Group name
+	Group string `json:"group" validate:"required,min=1"`
+
+	// Song name
+	Song string `json:"song" validate:"required,min=1"`
+
+	// Release date: dd.mm.yyyy, yyyy-mm-dd or yyyy
+	ReleaseDate string `json:"release_date" validate:"omitempty,release_date"`
+
+	// Lyrics or text of the song
+	Text string `json:"text"`
+
+	// External link to the song
+	Link string `json:"link"`
+
+	// Other credited artists
+	Artists []ArtistCredit `json:"artists,omitempty" validate:"omitempty,dive"`
+
+	// Provider that supplied the data: import or the provider used for enrichment
+	Source string `json:"-"`
+
+	// Language detected from the text
+	Language LanguageDetection `json:"-"`
+}
+
+// ImportOptions настройки импорта
+type ImportOptions struct {
+	// Enrich поставить в очередь загрузку недостающих текста, ссылки и даты выхода
+	Enrich bool
+
+	// Overwrite перезаписать уже существующие песни, иначе они пропускаются
+	Overwrite bool
+}
+
+// ImportRowResult результат импорта строки
+type ImportRowResult struct {
+	// Line number in the file
+	Line int `json:"line"`
+
+	Group string `json:"group"`
+	Song  string `json:"song"`
+
+	// created, updated, skipped or failed
+	Status string `json:"status"`
+
+	// ID of the created or existing song
+	SongID *uint `json:"song_id,omitempty"`
+
+	// Why the row failed or was skipped
+	Error string `json:"error,omitempty"`
+
+	// Enrichment job queued for the song, its status is available at /lyrics/jobs/{id}
+	JobID *uint `json:"job_id,omitempty"`
+}
+
+// ImportReport итог импорта
+// @Description Per-row report of a bulk import
+type ImportReport struct {
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+
+	Rows []ImportRowResult `json:"rows"`
+}
+
+// Add учитывает результат строки в счетчиках
+func (r *ImportReport) Add(result ImportRowResult) {
+	r.Total++
+	switch result.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	case ImportSkipped:
+		r.Skipped++
+	case ImportFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
@@ -0,0 +1,31 @@
+package models
+
+// LanguageUndetermined код языка песен, язык которых не определен (BCP 47).
+// Используется в фильтре и фасете библиотеки вместо NULL.
+const LanguageUndetermined = "und"
+
+// LanguageDetection язык текста, определенный автоматически
+type LanguageDetection struct {
+	// Language код языка, nil - язык не определен
+	Language *string
+
+	// Confidence уверенность от 0 до 1
+	Confidence *float64
+}
+
+// FacetCount значение фасета и количество песен с ним
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// LibraryFacets количество песен по значениям полей с учетом остальных фильтров
+type LibraryFacets struct {
+	Language []FacetCount `json:"language"`
+
+	// Songs per genre including its subgenres
+	Genre []FacetCount `json:"genre"`
+
+	// Most used tags
+	Tag []FacetCount `json:"tag"`
+}
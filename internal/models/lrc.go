@@ -0,0 +1,38 @@
+package models
+
+// LRCRequest синхронизированный текст песни
+// @Description Timed lyrics in LRC format
+type LRCRequest struct {
+	// Lyrics with [mm:ss.xx] timestamps
+	LRC string `json:"lrc" validate:"required"`
+}
+
+// LRCLine строка синхронизированного текста
+// @Description Lyrics line with its start time
+type LRCLine struct {
+	// Start time in milliseconds
+	TimeMs int64 `json:"time_ms"`
+
+	// Start time as mm:ss.xx
+	Time string `json:"time"`
+
+	Text string `json:"text"`
+}
+
+// SongLRC синхронизированный текст песни
+// @Description Timed lyrics of a song
+type SongLRC struct {
+	SongID uint `json:"song_id"`
+
+	// Header tags: ar, ti, al, by, ...
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Offset from the [offset:] tag, already applied to the lines
+	OffsetMs int64 `json:"offset_ms,omitempty"`
+
+	// Lines ordered by start time
+	Lines []LRCLine `json:"lines"`
+
+	// Raw LRC as stored
+	Raw string `json:"-"`
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // SongRequest для добавления песни
 // @Description Request payload for adding a new song
@@ -13,6 +17,9 @@ type SongRequest struct {
 	// Required: true
 	// Min length: 1
 	Song string `json:"song" validate:"required,min=1"`
+
+	// Other credited artists; "feat." in the group or song name adds featured artists
+	Artists []ArtistCredit `json:"artists,omitempty" validate:"omitempty,dive"`
 }
 
 // SongDetail для ответа
@@ -29,6 +36,12 @@ type SongDetail struct {
 	// External link to the song
 	// Required: true
 	Link string `json:"link" validate:"required"`
+
+	// Provider that supplied the song data
+	Source string `json:"source,omitempty"`
+
+	// Language detected from the text, filled by the service
+	Language LanguageDetection `json:"-"`
 }
 
 // UpdateTrackRequest обновление информации
@@ -48,15 +61,24 @@ type UpdateTrackRequest struct {
 	// Min length: 1
 	SongName *string `json:"song" validate:"required,min=1"`
 
-	// Release date
+	// Release date: dd.mm.yyyy, yyyy-mm-dd or yyyy
 	// Required: true
-	ReleaseDate *string `json:"release_date" validate:"required,min=1"`
+	ReleaseDate *string `json:"release_date" validate:"required,min=1,release_date"`
 
 	// Lyrics or text of the song
 	Text *string `json:"text,omitempty"`
 
 	// External link to the song
 	Link *string `json:"link,omitempty"`
+
+	// Language override (BCP 47: en, de, pt-BR); empty string - detect from the text again
+	Language *string `json:"language,omitempty"`
+
+	// Other credited artists; omitted - credits are kept, empty list - credits are removed
+	Artists []ArtistCredit `json:"artists,omitempty" validate:"omitempty,dive"`
+
+	// Language detected from the text, filled by the service
+	DetectedLanguage *LanguageDetection `json:"-"`
 }
 
 // Group модель базы данных
@@ -70,6 +92,12 @@ type Group struct {
 	// Required: true
 	Name string `gorm:"type:varchar(255);not null;unique;index" db:"name"`
 
+	// Normalized name without case, unique across groups
+	NameKey string `gorm:"type:text;not null;uniqueIndex" db:"name_key" json:"-"`
+
+	// Description of the group
+	Description *string `gorm:"type:text" db:"description"`
+
 	// Creation timestamp
 	// Required: true
 	CreatedAt time.Time `gorm:"index" db:"created_at"`
@@ -97,10 +125,22 @@ type Song struct {
 
 	// Name of the song
 	// Required: true
-	SongName string `gorm:"type:varchar(255);not null;uniqueIndex:idx_group_song,priority:2;index" db:"song_name"` // добавляем отдельный индекс, если часто ищем по имени
+	SongName string `gorm:"type:varchar(255);not null;index" db:"song_name"` // добавляем отдельный индекс, если часто ищем по имени
+
+	// Normalized song name without case, unique within the group
+	SongKey string `gorm:"type:text;not null;uniqueIndex:idx_group_song,priority:2" db:"song_key" json:"-"`
+
+	// ID of the album
+	AlbumID *uint `gorm:"index;uniqueIndex:idx_album_track,priority:1" db:"album_id"`
+
+	// Album title
+	AlbumTitle *string `gorm:"-" db:"album_title"`
+
+	// Track number on the album
+	TrackNumber *int `gorm:"uniqueIndex:idx_album_track,priority:2" db:"track_number"`
 
 	// Release date of the song
-	ReleaseDate string `db:"release_date"`
+	ReleaseDate *Date `gorm:"type:date;index" db:"release_date"`
 
 	// Lyrics or text of the song
 	Text string `gorm:"type:text" db:"text"`
@@ -108,6 +148,40 @@ type Song struct {
 	// External link to the song
 	Link *string `gorm:"index" db:"link"`
 
+	// Timed lyrics in LRC format
+	LRC *string `gorm:"type:text" db:"lrc"`
+
+	// Provider that supplied the song data (http, file, ...)
+	Source *string `gorm:"type:varchar(50)" db:"source"`
+
+	// Language of the text (BCP 47), nil - not determined
+	Language *string `gorm:"type:varchar(35);index" db:"language"`
+
+	// Confidence of the detected language from 0 to 1, nil for a manual override
+	LanguageConfidence *float64 `db:"language_confidence"`
+
+	// Language was set manually and is not detected again when the text changes
+	LanguageManual bool `gorm:"not null;default:false" db:"language_manual"`
+
+	// Artists credited in addition to the group
+	Artists []SongArtistInfo `gorm:"-" db:"-"`
+
+	// Genre and tag names of the song
+	Genres []string `gorm:"-" db:"-"`
+	Tags   []string `gorm:"-" db:"-"`
+
+	// Enrichment status: pending, enriched or failed
+	Status string `gorm:"type:varchar(20);not null;default:enriched;index" db:"status"`
+
+	// Why the last enrichment failed
+	EnrichError *string `gorm:"type:text" db:"enrich_error"`
+
+	// When the background refresh last checked the song
+	RefreshedAt *time.Time `gorm:"index" db:"refreshed_at"`
+
+	// When an editor last changed the release date, text or link; refresh then only fills empty fields
+	EditedAt *time.Time `db:"edited_at"`
+
 	// Creation timestamp
 	// Required: true
 	CreatedAt time.Time `gorm:"index" db:"created_at"`
@@ -115,3 +189,195 @@ type Song struct {
 	// Update timestamp
 	UpdatedAt time.Time `db:"updated_at"`
 }
+
+// LibraryFilter фильтры, сортировка и пагинация библиотеки
+type LibraryFilter struct {
+	Group        string
+	Song         string
+	Text         string
+	ReleaseDate  *time.Time
+	ReleasedFrom *time.Time
+	ReleasedTo   *time.Time
+	Year         int
+	Album        string
+	AlbumID      uint
+	Language     string
+	Sort         []SortField
+
+	// Genres жанры, жанр включает свои поджанры; GenreMatch - any (хотя бы один) или all (все)
+	Genres     []string
+	GenreMatch string
+
+	// Tags метки; TagMatch - any (хотя бы одна) или all (все)
+	Tags     []string
+	TagMatch string
+
+	Fields []string
+	Page   int
+	Limit  int
+
+	// After курсор keyset-пагинации, nil - постраничный режим (page/limit).
+	// Пустая строка - первая страница в режиме курсора.
+	After *string
+
+	// WithTotal считать общее количество песен
+	WithTotal bool
+}
+
+// Способ сочетания нескольких жанров или меток в фильтре библиотеки
+const (
+	// MatchAny песня подходит, если есть хотя бы одно значение
+	MatchAny = "any"
+
+	// MatchAll песня подходит, если есть все значения
+	MatchAll = "all"
+)
+
+// LibraryPage страница библиотеки
+type LibraryPage struct {
+	Songs []Song
+
+	// Total общее количество песен, nil - если подсчет не запрашивали
+	Total *int
+
+	// NextCursor курсор следующей страницы, пустой - страниц больше нет
+	NextCursor string
+
+	// Facets фасеты, nil - если подсчет не запрашивали
+	Facets *LibraryFacets
+}
+
+// Offset смещение для текущей страницы
+func (f LibraryFilter) Offset() int {
+	return (f.Page - 1) * f.Limit
+}
+
+// SortField поле сортировки библиотеки
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// librarySortFields поля, по которым разрешено сортировать библиотеку
+var librarySortFields = map[string]bool{
+	"id":           true,
+	"group":        true,
+	"song":         true,
+	"release_date": true,
+	"created_at":   true,
+	"updated_at":   true,
+}
+
+// ParseLibrarySort разбирает параметр сортировки: поля через запятую,
+// направление задается минусом в начале (-release_date) или суффиксом :asc / :desc (release_date:desc)
+func ParseLibrarySort(value string) ([]SortField, error) {
+	var sort []SortField
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field := SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field = SortField{Field: strings.TrimPrefix(part, "-"), Desc: true}
+		} else if name, direction, found := strings.Cut(part, ":"); found {
+			switch strings.ToLower(direction) {
+			case "asc":
+				field = SortField{Field: name}
+			case "desc":
+				field = SortField{Field: name, Desc: true}
+			default:
+				return nil, fmt.Errorf("unsupported sort direction: %q", direction)
+			}
+		}
+
+		if !librarySortFields[field.Field] {
+			return nil, fmt.Errorf("unsupported sort field: %q", field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("duplicate sort field: %q", field.Field)
+		}
+		seen[field.Field] = true
+
+		sort = append(sort, field)
+	}
+
+	return sort, nil
+}
+
+// LibraryFields поля песни, которые можно запросить у библиотеки через параметр fields
+var LibraryFields = []string{"id", "group", "song", "release_date", "text", "link", "album", "source", "language", "status", "created_at", "updated_at"}
+
+// ParseLibraryFields разбирает список полей через запятую, id возвращается всегда.
+// Пустой параметр - все поля (nil).
+func ParseLibraryFields(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	allowed := map[string]bool{}
+	for _, field := range LibraryFields {
+		allowed[field] = true
+	}
+
+	fields := []string{"id"}
+	seen := map[string]bool{"id": true}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || seen[part] {
+			continue
+		}
+		if !allowed[part] {
+			return nil, fmt.Errorf("unsupported field: %q", part)
+		}
+		seen[part] = true
+		fields = append(fields, part)
+	}
+
+	return fields, nil
+}
+
+// Project оставляет в ответе только запрошенные поля песни, ключи совпадают с полной выдачей Song
+func (s Song) Project(fields []string) map[string]interface{} {
+	projection := map[string]interface{}{}
+
+	for _, field := range fields {
+		switch field {
+		case "id":
+			projection["ID"] = s.ID
+		case "group":
+			projection["GroupID"] = s.GroupID
+			projection["GroupName"] = s.GroupName
+		case "song":
+			projection["SongName"] = s.SongName
+		case "release_date":
+			projection["ReleaseDate"] = s.ReleaseDate
+		case "text":
+			projection["Text"] = s.Text
+		case "link":
+			projection["Link"] = s.Link
+		case "album":
+			projection["AlbumID"] = s.AlbumID
+			projection["AlbumTitle"] = s.AlbumTitle
+			projection["TrackNumber"] = s.TrackNumber
+		case "source":
+			projection["Source"] = s.Source
+		case "language":
+			projection["Language"] = s.Language
+			projection["LanguageConfidence"] = s.LanguageConfidence
+			projection["LanguageManual"] = s.LanguageManual
+		case "status":
+			projection["Status"] = s.Status
+			projection["EnrichError"] = s.EnrichError
+		case "created_at":
+			projection["CreatedAt"] = s.CreatedAt
+		case "updated_at":
+			projection["UpdatedAt"] = s.UpdatedAt
+		}
+	}
+
+	return projection
+}
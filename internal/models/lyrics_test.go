@@ -0,0 +1,81 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLibrarySort(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []SortField
+		wantErr string
+	}{
+		{name: "empty", value: "", want: nil},
+		{name: "only commas and spaces", value: " , ,", want: nil},
+		{name: "ascending by default", value: "song", want: []SortField{{Field: "song"}}},
+		{name: "minus prefix", value: "-song", want: []SortField{{Field: "song", Desc: true}}},
+		{name: "desc suffix", value: "song:desc", want: []SortField{{Field: "song", Desc: true}}},
+		{name: "asc suffix", value: "song:asc", want: []SortField{{Field: "song"}}},
+		{name: "suffix is case insensitive", value: "song:DESC", want: []SortField{{Field: "song", Desc: true}}},
+		{
+			name:  "several fields keep order",
+			value: "group, -release_date ,id:asc",
+			want:  []SortField{{Field: "group"}, {Field: "release_date", Desc: true}, {Field: "id"}},
+		},
+		{name: "unknown field", value: "title", wantErr: `unsupported sort field: "title"`},
+		{name: "field names are case sensitive", value: "Song", wantErr: `unsupported sort field: "Song"`},
+		{name: "unknown direction", value: "song:down", wantErr: `unsupported sort direction: "down"`},
+		{name: "empty direction", value: "song:", wantErr: `unsupported sort direction: ""`},
+		{name: "minus and suffix together", value: "-song:desc", wantErr: `unsupported sort field: "song:desc"`},
+		{name: "double minus", value: "--song", wantErr: `unsupported sort field: "-song"`},
+		{name: "duplicate field", value: "song,-song", wantErr: `duplicate sort field: "song"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := ParseLibrarySort(tt.value)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, sort)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sort)
+		})
+	}
+}
+
+func TestParseLibraryFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr string
+	}{
+		{name: "empty means all fields", value: "", want: nil},
+		{name: "whitespace means all fields", value: "  ", want: nil},
+		{name: "id is always first", value: "song", want: []string{"id", "song"}},
+		{name: "explicit id is not repeated", value: "song,id", want: []string{"id", "song"}},
+		{name: "spaces and duplicates are skipped", value: " song , text,song,, ", want: []string{"id", "song", "text"}},
+		{name: "only commas", value: ",,", want: []string{"id"}},
+		{name: "unknown field", value: "lyrics", wantErr: `unsupported field: "lyrics"`},
+		{name: "unknown field after known", value: "song,Group", wantErr: `unsupported field: "Group"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseLibraryFields(tt.value)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, fields)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
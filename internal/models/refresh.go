@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// SongChange изменение поля песни при повторной загрузке данных
+// @Description Field of a song changed by a background refresh
+type SongChange struct {
+	// ID of the change
+	ID uint `gorm:"primaryKey" db:"id" json:"id"`
+
+	// ID of the song
+	SongID uint `gorm:"not null;index" db:"song_id" json:"song_id"`
+
+	// Changed field: release_date, text, link or source
+	Field string `gorm:"type:varchar(50);not null" db:"field" json:"field"`
+
+	// Value before the refresh
+	OldValue *string `gorm:"type:text" db:"old_value" json:"old_value"`
+
+	// Value after the refresh
+	NewValue *string `gorm:"type:text" db:"new_value" json:"new_value"`
+
+	// Creation timestamp
+	CreatedAt time.Time `gorm:"index" db:"created_at" json:"created_at"`
+}
+
+// RefreshError песня, которую не удалось обновить
+type RefreshError struct {
+	SongID uint   `json:"song_id"`
+	Error  string `json:"error"`
+}
+
+// RefreshReport итог прогона повторной загрузки устаревших и неполных песен
+// @Description Result of a background refresh run
+type RefreshReport struct {
+	// Run is still in progress
+	Running bool `json:"running"`
+
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// Songs selected for the refresh
+	Checked int `json:"checked"`
+
+	// Songs with at least one changed field
+	Updated int `json:"updated"`
+
+	// Songs whose data did not change
+	Unchanged int `json:"unchanged"`
+
+	// Songs the providers failed to return
+	Failed int `json:"failed"`
+
+	Changes []SongChange   `json:"changes"`
+	Errors  []RefreshError `json:"errors"`
+}
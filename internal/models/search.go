@@ -0,0 +1,48 @@
+package models
+
+// SearchResult результат полнотекстового поиска по текстам песен
+// @Description Full-text search hit with relevance rank and highlighted snippet
+type SearchResult struct {
+	// ID of the song
+	ID uint `json:"id" db:"id"`
+
+	// Group name
+	GroupName string `json:"group" db:"group_name"`
+
+	// Name of the song
+	SongName string `json:"song" db:"song_name"`
+
+	// Relevance rank (ts_rank), higher is better
+	Rank float64 `json:"rank" db:"rank"`
+
+	// Fragment of the lyrics with matches wrapped in <mark></mark>
+	Headline string `json:"headline" db:"headline"`
+}
+
+// Suggestion похожее название группы или песни
+// @Description Similar group or song name found by trigram similarity
+type Suggestion struct {
+	// ID of the group or song
+	ID uint `json:"id" db:"id"`
+
+	// Group or song name
+	Name string `json:"name" db:"name"`
+
+	// Group name of the suggested song
+	GroupName string `json:"group,omitempty" db:"group_name"`
+
+	// Trigram similarity from 0 to 1
+	Similarity float64 `json:"similarity" db:"similarity"`
+}
+
+// Suggestions блок "возможно, вы имели в виду"
+// @Description Did-you-mean suggestions for misspelled group and song names
+type Suggestions struct {
+	Groups []Suggestion `json:"groups"`
+	Songs  []Suggestion `json:"songs"`
+}
+
+// Empty нет ни одной подсказки
+func (s Suggestions) Empty() bool {
+	return len(s.Groups) == 0 && len(s.Songs) == 0
+}
@@ -0,0 +1,46 @@
+package models
+
+// Типы частей песни
+const (
+	SectionVerse        = "verse"
+	SectionChorus       = "chorus"
+	SectionPreChorus    = "pre-chorus"
+	SectionPostChorus   = "post-chorus"
+	SectionBridge       = "bridge"
+	SectionIntro        = "intro"
+	SectionOutro        = "outro"
+	SectionHook         = "hook"
+	SectionInstrumental = "instrumental"
+)
+
+// Section часть песни: куплет, припев, бридж и т.п.
+// @Description Part of the song structure
+type Section struct {
+	// Position in the song, from 1
+	Index int `json:"index"`
+
+	// verse, chorus, pre-chorus, post-chorus, bridge, intro, outro, hook or instrumental
+	Type string `json:"type"`
+
+	// Label from the marker ("Verse 2") or assigned on detection
+	Label string `json:"label,omitempty"`
+
+	// Verse pages of the section
+	Pages []int `json:"pages"`
+
+	// Index of the earlier section with the same text
+	RepeatOf *int `json:"repeat_of,omitempty"`
+
+	Text string `json:"text"`
+}
+
+// SongSections структура песни
+// @Description Song structure
+type SongSections struct {
+	SongID uint `json:"song_id"`
+
+	// Sections come from markers like [Chorus] in the text, otherwise repeated stanzas are detected as choruses
+	Marked bool `json:"marked"`
+
+	Sections []Section `json:"sections"`
+}
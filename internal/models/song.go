@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// SongInfo песня со всеми метаданными
+// @Description Song with all its metadata and full text
+type SongInfo struct {
+	// ID of the song
+	ID uint `json:"id"`
+
+	// Group name
+	Group string `json:"group"`
+
+	// All credited artists, the group first
+	Artists []SongArtistInfo `json:"artists"`
+
+	// Song name
+	Song string `json:"song"`
+
+	// Release date of the song
+	ReleaseDate *Date `json:"release_date"`
+
+	// External link to the song
+	Link *string `json:"link"`
+
+	// Album the song belongs to
+	AlbumID    *uint   `json:"album_id,omitempty"`
+	AlbumTitle *string `json:"album,omitempty"`
+
+	// Track number on the album
+	TrackNumber *int `json:"track_number,omitempty"`
+
+	// Provider that supplied the song data
+	Source *string `json:"source"`
+
+	// Enrichment status: pending, enriched or failed
+	Status string `json:"status"`
+
+	// Why the last enrichment failed
+	EnrichError *string `json:"enrich_error,omitempty"`
+
+	// Number of verses in the text
+	VerseCount int `json:"verse_count"`
+
+	// Song has timed lyrics (LRC)
+	HasLRC bool `json:"has_lrc"`
+
+	// Language of the original text (BCP 47), null - not determined
+	OriginalLanguage *string `json:"original_language"`
+
+	// Confidence of the detected language from 0 to 1, null for a manual override
+	LanguageConfidence *float64 `json:"language_confidence"`
+
+	// Language was set manually
+	LanguageManual bool `json:"language_manual"`
+
+	// Genres of the song
+	Genres []string `json:"genres"`
+
+	// Tags of the song
+	Tags []string `json:"tags"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at"`
+
+	// Update timestamp
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Languages the song is translated to
+	Translations []string `json:"translations"`
+
+	// Language of the translation in text, empty - text is the original
+	Language string `json:"language,omitempty"`
+
+	// Lyrics or text of the song
+	Text string `json:"text"`
+}
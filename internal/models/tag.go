@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Tag произвольная метка, создается при первой привязке к песне
+// @Description Database model for a free-form tag
+type Tag struct {
+	ID uint `gorm:"primaryKey" db:"id"`
+
+	// Name of the tag
+	Name string `gorm:"type:varchar(100);not null" db:"name"`
+
+	// Normalized name without case, unique across tags
+	NameKey string `gorm:"type:text;not null;uniqueIndex" db:"name_key"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// SongTag метка песни
+type SongTag struct {
+	SongID uint `gorm:"primaryKey;autoIncrement:false" db:"song_id"`
+	TagID  uint `gorm:"primaryKey;autoIncrement:false;index" db:"tag_id"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// TagInfo метка с количеством песен
+// @Description Tag with its song count
+type TagInfo struct {
+	// ID of the tag
+	ID uint `json:"id" db:"id"`
+
+	// Name of the tag
+	Name string `json:"name" db:"name"`
+
+	// Number of songs with the tag
+	SongCount int `json:"song_count" db:"song_count"`
+
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// SongTagsRequest добавление или удаление меток у нескольких песен
+// @Description Request payload for attaching or detaching tags of songs in bulk
+type SongTagsRequest struct {
+	// IDs of the songs
+	// Required: true
+	SongIDs []uint `json:"song_ids" validate:"required,min=1,max=1000,dive,required"`
+
+	// Tag names; missing tags are created on attach
+	// Required: true
+	Tags []string `json:"tags" validate:"required,min=1,max=100,dive,required,max=100"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// SongTranslation перевод текста песни
+// @Description Translation of the song lyrics
+type SongTranslation struct {
+	ID uint `gorm:"primaryKey" db:"id" json:"-"`
+
+	// ID of the song
+	SongID uint `gorm:"not null;uniqueIndex:idx_song_translation_language,priority:1" db:"song_id" json:"song_id"`
+
+	// Language code (BCP 47): en, de, pt-BR
+	Language string `gorm:"type:varchar(35);not null;uniqueIndex:idx_song_translation_language,priority:2" db:"language" json:"language"`
+
+	// Translated lyrics, stanzas in the same order as the original
+	Text string `gorm:"type:text;not null" db:"text" json:"text"`
+
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TranslationRequest текст перевода
+// @Description Request payload for adding or updating a translation
+type TranslationRequest struct {
+	// Translated lyrics, stanzas separated by blank lines like in the original
+	Text string `json:"text" validate:"required"`
+}
+
+// NormalizeLanguage проверяет код языка и приводит его к каноническому виду (pt-br -> pt-BR)
+func NormalizeLanguage(code string) (string, error) {
+	tag, err := language.Parse(code)
+	if err != nil || tag == language.Und {
+		return "", fmt.Errorf("invalid language code: %q", code)
+	}
+	return tag.String(), nil
+}
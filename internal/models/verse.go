@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Verse куплет песни
+// @Description Verse of a song
+type Verse struct {
+	// Page number
+	Page int `json:"page"`
+
+	// Verse text
+	Verse string `json:"verse"`
+
+	// Pagination mode: verses, lines or chars
+	Mode string `json:"mode"`
+
+	// Verses, lines or characters per page
+	Size int `json:"size"`
+
+	// Number of pages in the song for this mode and size
+	TotalPages int `json:"total_pages"`
+
+	// There is a page after this one
+	HasNext bool `json:"has_next"`
+
+	// Section the verse belongs to: verse, chorus, bridge, ...
+	SectionType string `json:"section_type"`
+
+	// Section label, e.g. "Verse 2" or "Chorus"
+	SectionLabel string `json:"section_label,omitempty"`
+
+	// Verse lines with start times, only when the song has timed lyrics
+	Lines []VerseLine `json:"lines,omitempty"`
+
+	// Translated verse, only when a translation to the requested language exists
+	Translation *VerseTranslation `json:"translation,omitempty"`
+}
+
+// VerseTranslation перевод куплета
+// @Description Translated verse aligned with the original
+type VerseTranslation struct {
+	// Language code
+	Language string `json:"language"`
+
+	// Translated verse text
+	Verse string `json:"verse"`
+}
+
+// VerseLine строка куплета
+// @Description Verse line with its start time
+type VerseLine struct {
+	Text string `json:"text"`
+
+	// Start time in milliseconds, absent when the line is not found in the timed lyrics
+	TimeMs *int64 `json:"time_ms,omitempty"`
+
+	// Start time as mm:ss.xx
+	Time *string `json:"time,omitempty"`
+}
+
+// Способы разбиения текста на страницы куплетов
+const (
+	// VerseModeVerses size строф на странице, по умолчанию одна строфа - текущее поведение
+	VerseModeVerses = "verses"
+
+	// VerseModeLines size строк на странице
+	VerseModeLines = "lines"
+
+	// VerseModeChars страница не длиннее size символов, строки не разрываются
+	VerseModeChars = "chars"
+)
+
+// verseModeSizes размер страницы по умолчанию и максимальный для каждого способа
+var verseModeSizes = map[string]struct{ def, max int }{
+	VerseModeVerses: {def: 1, max: 100},
+	VerseModeLines:  {def: 4, max: 500},
+	VerseModeChars:  {def: 500, max: 20000},
+}
+
+// VersePaging способ разбиения текста на страницы
+type VersePaging struct {
+	Mode string
+	Size int
+}
+
+// ParseVersePaging разбирает параметры mode и size, пустые параметры - значения по умолчанию
+func ParseVersePaging(mode, size string) (VersePaging, error) {
+	if mode == "" {
+		mode = VerseModeVerses
+	}
+	sizes, ok := verseModeSizes[mode]
+	if !ok {
+		return VersePaging{}, fmt.Errorf("unsupported mode: %q, expected verses, lines or chars", mode)
+	}
+
+	paging := VersePaging{Mode: mode, Size: sizes.def}
+	if size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n <= 0 || n > sizes.max {
+			return VersePaging{}, fmt.Errorf("invalid size for mode %s, expected 1..%d", mode, sizes.max)
+		}
+		paging.Size = n
+	}
+
+	return paging, nil
+}
+
+// SongVerses все страницы куплетов песни
+// @Description All verse pages of a song
+type SongVerses struct {
+	SongID uint `json:"song_id"`
+
+	// Pagination mode: verses, lines or chars
+	Mode string `json:"mode"`
+
+	// Verses, lines or characters per page
+	Size int `json:"size"`
+
+	// Number of pages
+	Total int `json:"total"`
+
+	// Language of the translations in the verses, empty when the song has no translation to the requested language
+	Language string `json:"language,omitempty"`
+
+	Verses []Verse `json:"verses"`
+}
@@ -6,8 +6,10 @@ import (
 
 	_ "github.com/22Fariz22/musiclab/docs"
 	lyricsHTTP "github.com/22Fariz22/musiclab/internal/lyrics/delivery/http"
+	lyricsProvider "github.com/22Fariz22/musiclab/internal/lyrics/provider"
 	lyricsRepository "github.com/22Fariz22/musiclab/internal/lyrics/repository"
 	lyricsUseCase "github.com/22Fariz22/musiclab/internal/lyrics/usecase"
+	"github.com/22Fariz22/musiclab/internal/lyrics/worker"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
@@ -24,11 +26,22 @@ func (s *Server) MapHandlers(e *echo.Echo) error {
 	// Init repositories
 	lyricsRepo := lyricsRepository.NewLyricsRepository(s.db, s.logger)
 
+	// Init lyrics providers
+	lyricsProviders, err := lyricsProvider.NewFromConfig(s.cfg, s.logger)
+	if err != nil {
+		return err
+	}
+
 	// Init useCases
-	lyricsUC := lyricsUseCase.NewLyricsUseCase(s.cfg, lyricsRepo, s.redisClient, s.logger)
+	lyricsUC := lyricsUseCase.NewLyricsUseCase(s.cfg, lyricsRepo, lyricsProviders, s.redisClient, s.logger)
+
+	// Init background workers
+	s.enrichmentPool = worker.NewEnrichmentPool(s.cfg, lyricsUC, s.logger)
+	s.refresh = worker.NewRefreshScheduler(s.cfg, lyricsUC, s.logger)
+	s.groupCleanup = worker.NewGroupCleanupScheduler(s.cfg, lyricsUC, s.logger)
 
 	// Init handlers
-	lyricsHandler := lyricsHTTP.NewLyricsHandler(s.cfg, lyricsUC, s.logger)
+	lyricsHandler := lyricsHTTP.NewLyricsHandler(s.cfg, lyricsUC, s.refresh, s.logger)
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 	e.Static("/swagger", "./docs")
@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"github.com/22Fariz22/musiclab/config"
+	"github.com/22Fariz22/musiclab/internal/lyrics/worker"
 	"github.com/22Fariz22/musiclab/pkg/logger"
+	"github.com/22Fariz22/musiclab/pkg/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
@@ -19,11 +21,14 @@ import (
 
 // Server struct
 type Server struct {
-	echo        *echo.Echo
-	cfg         *config.Config
-	db          *sqlx.DB
-	redisClient *redis.Client
-	logger      logger.Logger
+	echo           *echo.Echo
+	cfg            *config.Config
+	db             *sqlx.DB
+	redisClient    *redis.Client
+	logger         logger.Logger
+	enrichmentPool *worker.EnrichmentPool
+	refresh        *worker.RefreshScheduler
+	groupCleanup   *worker.GroupCleanupScheduler
 }
 
 // CustomValidator wraps validator
@@ -41,7 +46,7 @@ func NewServer(cfg *config.Config, db *sqlx.DB, redisClient *redis.Client, logge
 	e := echo.New()
 
 	// Устанавливаем кастомный валидатор
-	e.Validator = &CustomValidator{Validator: validator.New()}
+	e.Validator = &CustomValidator{Validator: utils.NewValidator()}
 
 	return &Server{echo: e, cfg: cfg, db: db, redisClient: redisClient, logger: logger}
 }
@@ -72,11 +77,22 @@ func (s *Server) Run() error {
 		return err
 	}
 
+	// Фоновая загрузка и обновление данных песен
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	s.enrichmentPool.Start(workersCtx)
+	s.refresh.Start(workersCtx)
+	s.groupCleanup.Start(workersCtx)
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 	<-quit
 
+	stopWorkers()
+	s.enrichmentPool.Wait()
+	s.refresh.Wait()
+	s.groupCleanup.Wait()
+
 	ctx, shutdown := context.WithTimeout(context.Background(), s.cfg.Server.CtxTimeout)
 	defer shutdown()
 
@@ -3,10 +3,113 @@ package migrate
 import (
 	"github.com/22Fariz22/musiclab/internal/models"
 	"github.com/22Fariz22/musiclab/pkg/logger"
+	"github.com/22Fariz22/musiclab/pkg/utils"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// sqlMigrations изменения схемы, которые нельзя описать тегами gorm
+var sqlMigrations = []string{
+	// Полнотекстовый поиск: название песни важнее текста
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS text_search tsvector
+        GENERATED ALWAYS AS (
+            setweight(to_tsvector('simple', coalesce(song_name, '')), 'A') ||
+            setweight(to_tsvector('simple', coalesce(text, '')), 'B')
+        ) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_songs_text_search ON songs USING GIN (text_search)`,
+
+	// Нечеткий поиск по названиям групп и песен
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS idx_groups_name_trgm ON groups USING GIN (name gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_songs_song_name_trgm ON songs USING GIN (song_name gin_trgm_ops)`,
+
+	// Подсказки ищут и по другим написаниям имен групп
+	`CREATE INDEX IF NOT EXISTS idx_group_aliases_alias_trgm ON group_aliases USING GIN (alias gin_trgm_ops)`,
+
+	// Другие написания имени удаляются вместе с группой
+	`DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_group_aliases_group') THEN
+            ALTER TABLE group_aliases ADD CONSTRAINT fk_group_aliases_group
+                FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE;
+        END IF;
+    END $$`,
+
+	// При удалении альбома песни остаются в библиотеке без альбома
+	`DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_songs_album') THEN
+            ALTER TABLE songs ADD CONSTRAINT fk_songs_album
+                FOREIGN KEY (album_id) REFERENCES albums (id) ON DELETE SET NULL;
+        END IF;
+    END $$`,
+
+	// Задачи обогащения удаляются вместе с песней
+	`DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_enrichment_jobs_song') THEN
+            ALTER TABLE enrichment_jobs ADD CONSTRAINT fk_enrichment_jobs_song
+                FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE;
+        END IF;
+    END $$`,
+
+	// История изменений удаляется вместе с песней
+	`DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_song_changes_song') THEN
+            ALTER TABLE song_changes ADD CONSTRAINT fk_song_changes_song
+                FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE;
+        END IF;
+    END $$`,
+
+	// Участники удаляются вместе с песней и с группой участника
+	`DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_song_artists_song') THEN
+            ALTER TABLE song_artists ADD CONSTRAINT fk_song_artists_song
+                FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE;
+        END IF;
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_song_artists_group') THEN
+            ALTER TABLE song_artists ADD CONSTRAINT fk_song_artists_group
+                FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE CASCADE;
+        END IF;
+    END $$`,
+
+	// Жанры и метки отвязываются при удалении песни, жанра или метки
+	`DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_genres_parent') THEN
+            ALTER TABLE genres ADD CONSTRAINT fk_genres_parent
+                FOREIGN KEY (parent_id) REFERENCES genres (id) ON DELETE SET NULL;
+        END IF;
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_song_genres_song') THEN
+            ALTER TABLE song_genres ADD CONSTRAINT fk_song_genres_song
+                FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE;
+        END IF;
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_song_genres_genre') THEN
+            ALTER TABLE song_genres ADD CONSTRAINT fk_song_genres_genre
+                FOREIGN KEY (genre_id) REFERENCES genres (id) ON DELETE CASCADE;
+        END IF;
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_song_tags_song') THEN
+            ALTER TABLE song_tags ADD CONSTRAINT fk_song_tags_song
+                FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE;
+        END IF;
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_song_tags_tag') THEN
+            ALTER TABLE song_tags ADD CONSTRAINT fk_song_tags_tag
+                FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE;
+        END IF;
+    END $$`,
+
+	// Переводы удаляются вместе с песней
+	`DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_song_translations_song') THEN
+            ALTER TABLE song_translations ADD CONSTRAINT fk_song_translations_song
+                FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE;
+        END IF;
+    END $$`,
+}
+
 // Migrate applies database migrations
 func Migrate(logger logger.Logger, dsn string) error {
 	// Инициализация GORM с использованием только для миграций
@@ -16,6 +119,96 @@ func Migrate(logger logger.Logger, dsn string) error {
 		return err
 	}
 
+	// Текстовые даты выхода нужно перевести в DATE до AutoMigrate,
+	// иначе GORM попытается привести тип колонки без разбора старых форматов
+	for _, table := range []string{"songs", "albums"} {
+		if err := convertReleaseDates(db, logger, table); err != nil {
+			logger.Debugf("Error converting %s.release_date: %v", table, err)
+			return err
+		}
+	}
+
+	// Ключи нормализованных имен нужно заполнить до AutoMigrate, который строит по ним уникальные индексы
+	if err := fillNameKeys(db, logger); err != nil {
+		logger.Debugf("Error filling name keys: %v", err)
+		return err
+	}
+
 	// Выполнение миграций
-	return db.AutoMigrate(&models.Group{}, &models.Song{})
+	if err := db.AutoMigrate(&models.Group{}, &models.GroupAlias{}, &models.Album{}, &models.Song{}, &models.EnrichmentJob{}, &models.SongChange{}, &models.SongTranslation{}, &models.SongArtist{},
+		&models.Genre{}, &models.SongGenre{}, &models.Tag{}, &models.SongTag{}); err != nil {
+		return err
+	}
+
+	for _, migration := range sqlMigrations {
+		if err := db.Exec(migration).Error; err != nil {
+			logger.Debugf("Error in sql migration %q: %v", migration, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertReleaseDates переводит текстовую колонку release_date в DATE.
+// Значения разбираются в форматах dd.mm.yyyy, yyyy-mm-dd и yyyy, неразобранные даты становятся NULL и попадают в лог.
+func convertReleaseDates(db *gorm.DB, logger logger.Logger, table string) error {
+	var dataType string
+	err := db.Raw(
+		`SELECT data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = 'release_date'`,
+		table,
+	).Scan(&dataType).Error
+	if err != nil {
+		return err
+	}
+
+	// Таблицы еще нет или колонка уже переведена
+	if dataType == "" || dataType == "date" {
+		return nil
+	}
+
+	logger.Infof("Converting %s.release_date from %s to date", table, dataType)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN release_date_parsed date`).Error; err != nil {
+			return err
+		}
+
+		var rows []struct {
+			ID          uint
+			ReleaseDate *string
+		}
+		if err := tx.Raw(`SELECT id, release_date FROM ` + table).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		var converted, skipped int
+		for _, row := range rows {
+			if row.ReleaseDate == nil || *row.ReleaseDate == "" {
+				continue
+			}
+
+			date, err := utils.ParseReleaseDate(*row.ReleaseDate)
+			if err != nil {
+				logger.Warnf("%s id=%d: cannot parse release date %q, leaving it empty", table, row.ID, *row.ReleaseDate)
+				skipped++
+				continue
+			}
+
+			if err := tx.Exec(`UPDATE `+table+` SET release_date_parsed = ? WHERE id = ?`, date, row.ID).Error; err != nil {
+				return err
+			}
+			converted++
+		}
+
+		if err := tx.Exec(`ALTER TABLE ` + table + ` DROP COLUMN release_date`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`ALTER TABLE ` + table + ` RENAME COLUMN release_date_parsed TO release_date`).Error; err != nil {
+			return err
+		}
+
+		logger.Infof("Converted %s.release_date: %d parsed, %d left empty", table, converted, skipped)
+		return nil
+	})
 }
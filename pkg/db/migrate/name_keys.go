@@ -0,0 +1,241 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/22Fariz22/musiclab/pkg/logger"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// nameKeyColumn колонка с нормализованным ключом имени, scope - колонка, в пределах которой ключ уникален,
+// uniqueName - само имя тоже уникально в таблице
+type nameKeyColumn struct {
+	table      string
+	name       string
+	key        string
+	scope      string
+	uniqueName bool
+}
+
+var nameKeyColumns = []nameKeyColumn{
+	{table: "groups", name: "name", key: "name_key", uniqueName: true},
+	{table: "group_aliases", name: "alias", key: "alias_key", uniqueName: true},
+	{table: "songs", name: "song_name", key: "song_key", scope: "group_id"},
+}
+
+// nameKeyRow запись таблицы; KeyValue заполнен у записей, созданных после появления ключа
+type nameKeyRow struct {
+	ID       uint
+	Name     string
+	Scope    uint
+	KeyValue *string
+}
+
+// nameKeyUpdate нормализованное имя и ключ записи без ключа
+type nameKeyUpdate struct {
+	row  nameKeyRow
+	name string
+	key  string
+}
+
+// nameKeyCollision записи, совпадающие после нормализации: первая получает ключ без суффикса
+type nameKeyCollision struct {
+	key     string
+	rows    []nameKeyRow
+	updates map[uint]nameKeyUpdate
+}
+
+// fillNameKeys заполняет ключи имен у существующих записей до AutoMigrate, который делает ключи уникальными.
+// Имена всех записей нормализуются. Из записей, совпадающих после нормализации, первая получает ключ,
+// остальные - ключ с суффиксом #id, а если имя уникально в таблице, то и имя с суффиксом " #id".
+// Каждое совпадение попадает в лог с ID записей, чтобы дубликаты можно было слить
+// (POST /lyrics/groups/{id}/merge) или переименовать.
+func fillNameKeys(db *gorm.DB, logger logger.Logger) error {
+	collisions := 0
+	for _, column := range nameKeyColumns {
+		n, err := fillNameKeyColumn(db, logger, column)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", column.table, column.key, err)
+		}
+		collisions += n
+	}
+
+	if collisions > 0 {
+		logger.Warnf("%d names collide after normalization, merge (POST /lyrics/groups/{id}/merge) or rename the duplicates listed above", collisions)
+	}
+
+	// Уникальность песни в группе теперь проверяется по ключу, индекс пересоздаст AutoMigrate
+	var indexDef string
+	err := db.Raw(`SELECT indexdef FROM pg_indexes WHERE schemaname = current_schema() AND indexname = 'idx_group_song'`).Scan(&indexDef).Error
+	if err != nil {
+		return err
+	}
+	if strings.Contains(indexDef, "song_name") {
+		logger.Infof("Recreating idx_group_song on songs.song_key")
+		return db.Exec(`DROP INDEX idx_group_song`).Error
+	}
+
+	return nil
+}
+
+// fillNameKeyColumn заполняет пустые ключи одной таблицы, возвращает количество найденных совпадений
+func fillNameKeyColumn(db *gorm.DB, logger logger.Logger, column nameKeyColumn) (int, error) {
+	var tables int64
+	err := db.Raw(
+		`SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?`,
+		column.table,
+	).Scan(&tables).Error
+	if err != nil || tables == 0 {
+		// Таблицы еще нет, AutoMigrate создаст ее вместе с ключом
+		return 0, err
+	}
+
+	scope := "0"
+	if column.scope != "" {
+		scope = column.scope
+	}
+
+	collisions := 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE ` + column.table + ` ADD COLUMN IF NOT EXISTS ` + column.key + ` text`).Error; err != nil {
+			return err
+		}
+
+		var missing int64
+		if err := tx.Raw(`SELECT COUNT(*) FROM ` + column.table + ` WHERE ` + column.key + ` IS NULL`).Scan(&missing).Error; err != nil {
+			return err
+		}
+		if missing == 0 {
+			return nil
+		}
+
+		logger.Infof("Filling %s.%s for %d rows", column.table, column.key, missing)
+
+		var rows []nameKeyRow
+		query := `SELECT id, ` + column.name + ` AS name, ` + scope + ` AS scope, ` + column.key + ` AS key_value
+                  FROM ` + column.table + ` ORDER BY id`
+		if err := tx.Raw(query).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		updates, found := planNameKeys(rows, column.uniqueName)
+
+		update := `UPDATE ` + column.table + ` SET ` + column.name + ` = ?, ` + column.key + ` = ? WHERE id = ?`
+		for _, u := range updates {
+			if err := tx.Exec(update, u.name, u.key, u.row.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, collision := range found {
+			logger.Warnf("%s: %d rows have the same name after normalization (key %q): %s",
+				column.table, len(collision.rows), collision.key, collision.describe())
+			collisions += len(collision.rows) - 1
+		}
+
+		return nil
+	})
+
+	return collisions, err
+}
+
+// planNameKeys нормализованные имена и ключи записей без ключа и найденные совпадения.
+// Первой в совпадении идет запись с уже заполненным ключом (она не меняется), затем запись с уже нормализованным именем,
+// затем запись с меньшим ID. Если имя уникально, запись, чье нормализованное имя уже занято, получает имя
+// с суффиксом " #id", чтобы UPDATE не нарушил уникальность имени.
+func planNameKeys(rows []nameKeyRow, uniqueName bool) ([]nameKeyUpdate, []nameKeyCollision) {
+	keys := make([]string, len(rows))
+	byKey := map[string][]int{}
+	var order []string
+	for i, row := range rows {
+		keys[i] = utils.NameKey(row.Name)
+		if row.KeyValue != nil {
+			keys[i] = *row.KeyValue
+		}
+
+		k := fmt.Sprintf("%d\x00%s", row.Scope, keys[i])
+		if _, ok := byKey[k]; !ok {
+			order = append(order, k)
+		}
+		if row.KeyValue != nil {
+			byKey[k] = append([]int{i}, byKey[k]...)
+		} else {
+			byKey[k] = append(byKey[k], i)
+		}
+	}
+
+	var updates []nameKeyUpdate
+	var collisions []nameKeyCollision
+	for _, k := range order {
+		same := byKey[k]
+
+		// Без записи с ключом ключ без суффикса получает запись, имя которой уже нормализовано
+		if rows[same[0]].KeyValue == nil {
+			for j, i := range same {
+				if rows[i].Name == utils.NormalizeName(rows[i].Name) {
+					same = append([]int{i}, append(same[:j:j], same[j+1:]...)...)
+					break
+				}
+			}
+		}
+
+		// Имена, которые уже заняты записями этого совпадения
+		taken := map[string]bool{}
+		if uniqueName {
+			for _, i := range same {
+				if rows[i].KeyValue != nil || rows[i].Name == utils.NormalizeName(rows[i].Name) {
+					taken[rows[i].Name] = true
+				}
+			}
+		}
+
+		collision := nameKeyCollision{key: keys[same[0]], updates: map[uint]nameKeyUpdate{}}
+		for n, i := range same {
+			row := rows[i]
+			collision.rows = append(collision.rows, row)
+			if row.KeyValue != nil {
+				continue
+			}
+
+			u := nameKeyUpdate{row: row, name: utils.NormalizeName(row.Name), key: keys[i]}
+			if n > 0 {
+				u.key = fmt.Sprintf("%s#%d", keys[i], row.ID)
+			}
+			if uniqueName && u.name != row.Name {
+				if taken[u.name] {
+					u.name = fmt.Sprintf("%s #%d", u.name, row.ID)
+				}
+				taken[u.name] = true
+			}
+
+			updates = append(updates, u)
+			collision.updates[row.ID] = u
+		}
+
+		if len(same) > 1 {
+			collisions = append(collisions, collision)
+		}
+	}
+
+	return updates, collisions
+}
+
+// describe записи совпадения для лога: ID, имя до и после нормализации и ключ
+func (c nameKeyCollision) describe() string {
+	parts := make([]string, 0, len(c.rows))
+	for _, row := range c.rows {
+		u, ok := c.updates[row.ID]
+		if !ok {
+			parts = append(parts, fmt.Sprintf("id=%d %q (key %q, unchanged)", row.ID, row.Name, *row.KeyValue))
+			continue
+		}
+		if u.name != row.Name {
+			parts = append(parts, fmt.Sprintf("id=%d %q renamed to %q (key %q)", row.ID, row.Name, u.name, u.key))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("id=%d %q (key %q)", row.ID, row.Name, u.key))
+	}
+	return strings.Join(parts, ", ")
+}
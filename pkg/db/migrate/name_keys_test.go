@@ -0,0 +1,81 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanNameKeys(t *testing.T) {
+	key := func(s string) *string { return &s }
+
+	tests := []struct {
+		name       string
+		rows       []nameKeyRow
+		uniqueName bool
+		want       []nameKeyUpdate
+		wantLogs   []string
+	}{
+		{
+			name: "no collisions",
+			rows: []nameKeyRow{{ID: 1, Name: "Muse "}, {ID: 2, Name: "Radiohead"}},
+			want: []nameKeyUpdate{
+				{row: nameKeyRow{ID: 1, Name: "Muse "}, name: "Muse", key: "muse"},
+				{row: nameKeyRow{ID: 2, Name: "Radiohead"}, name: "Radiohead", key: "radiohead"},
+			},
+		},
+		{
+			name:       "unique names collide",
+			rows:       []nameKeyRow{{ID: 1, Name: "Muse "}, {ID: 2, Name: "MUSE"}, {ID: 3, Name: "Muse"}},
+			uniqueName: true,
+			want: []nameKeyUpdate{
+				{row: nameKeyRow{ID: 2, Name: "MUSE"}, name: "MUSE", key: "muse"},
+				{row: nameKeyRow{ID: 1, Name: "Muse "}, name: "Muse #1", key: "muse#1"},
+				{row: nameKeyRow{ID: 3, Name: "Muse"}, name: "Muse", key: "muse#3"},
+			},
+			wantLogs: []string{`id=2 "MUSE" (key "muse"), id=1 "Muse " renamed to "Muse #1" (key "muse#1"), id=3 "Muse" (key "muse#3")`},
+		},
+		{
+			name:       "first row gets the normalized name when nobody has it",
+			rows:       []nameKeyRow{{ID: 1, Name: "Muse "}, {ID: 2, Name: " Muse"}},
+			uniqueName: true,
+			want: []nameKeyUpdate{
+				{row: nameKeyRow{ID: 1, Name: "Muse "}, name: "Muse", key: "muse"},
+				{row: nameKeyRow{ID: 2, Name: " Muse"}, name: "Muse #2", key: "muse#2"},
+			},
+			wantLogs: []string{`id=1 "Muse " renamed to "Muse" (key "muse"), id=2 " Muse" renamed to "Muse #2" (key "muse#2")`},
+		},
+		{
+			name:       "row with a key stays first",
+			rows:       []nameKeyRow{{ID: 1, Name: "Muse  "}, {ID: 2, Name: "Muse", KeyValue: key("muse")}},
+			uniqueName: true,
+			want: []nameKeyUpdate{
+				{row: nameKeyRow{ID: 1, Name: "Muse  "}, name: "Muse #1", key: "muse#1"},
+			},
+			wantLogs: []string{`id=2 "Muse" (key "muse", unchanged), id=1 "Muse  " renamed to "Muse #1" (key "muse#1")`},
+		},
+		{
+			name: "song names are not unique",
+			rows: []nameKeyRow{{ID: 1, Name: "Uprising", Scope: 1}, {ID: 2, Name: "Uprising ", Scope: 1}, {ID: 3, Name: "Uprising ", Scope: 2}},
+			want: []nameKeyUpdate{
+				{row: nameKeyRow{ID: 1, Name: "Uprising", Scope: 1}, name: "Uprising", key: "uprising"},
+				{row: nameKeyRow{ID: 2, Name: "Uprising ", Scope: 1}, name: "Uprising", key: "uprising#2"},
+				{row: nameKeyRow{ID: 3, Name: "Uprising ", Scope: 2}, name: "Uprising", key: "uprising"},
+			},
+			wantLogs: []string{`id=1 "Uprising" (key "uprising"), id=2 "Uprising " renamed to "Uprising" (key "uprising#2")`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, collisions := planNameKeys(tt.rows, tt.uniqueName)
+			assert.Equal(t, tt.want, updates)
+
+			require.Len(t, collisions, len(tt.wantLogs))
+			for i, collision := range collisions {
+				assert.Equal(t, tt.wantLogs[i], collision.describe())
+			}
+		})
+	}
+}
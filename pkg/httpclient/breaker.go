@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/22Fariz22/musiclab/pkg/logger"
+)
+
+// ErrCircuitOpen запрос не отправлялся: upstream недавно падал и автомат разомкнут
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// State состояние автомата
+type State string
+
+const (
+	// StateClosed запросы проходят
+	StateClosed State = "closed"
+	// StateOpen запросы отклоняются сразу до конца паузы
+	StateOpen State = "open"
+	// StateHalfOpen пауза прошла, пропускается один пробный запрос
+	StateHalfOpen State = "half-open"
+)
+
+// BreakerStats снимок состояния автомата для health и логов
+type BreakerStats struct {
+	State               State
+	ConsecutiveFailures int
+	OpenUntil           time.Time
+}
+
+// Breaker автоматический выключатель: после threshold неудач подряд размыкается на cooldown,
+// затем пропускает один пробный запрос - успех замыкает его, неудача снова размыкает
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	logger    logger.Logger
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// NewBreaker автомат для upstream с именем name, threshold <= 0 - автомат никогда не размыкается
+func NewBreaker(name string, threshold int, cooldown time.Duration, logger logger.Logger) *Breaker {
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		logger:    logger,
+		state:     StateClosed,
+	}
+}
+
+// Allow можно ли отправить запрос; при разрешении вызывающий обязан сообщить результат через Success или Failure
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Now().Before(b.openUntil) {
+			return ErrCircuitOpen
+		}
+		b.setState(StateHalfOpen)
+		b.probing = true
+		return nil
+	case StateHalfOpen:
+		// Пробный запрос уже в пути
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	}
+
+	return nil
+}
+
+// Success upstream ответил
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	if b.state != StateClosed {
+		b.setState(StateClosed)
+	}
+}
+
+// Failure upstream недоступен или вернул ошибку сервера (5xx)
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+
+	if b.state == StateHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+		b.openUntil = time.Now().Add(b.cooldown)
+		if b.state != StateOpen {
+			b.setState(StateOpen)
+		}
+	}
+}
+
+// Release результат попытки ничего не говорит о здоровье upstream: запрос прерван вызывающим
+// или upstream просит снизить частоту (429)
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// Stats текущее состояние. Автомат переходит в half-open только на следующем Allow,
+// поэтому после паузы разомкнутый автомат показывается как half-open.
+func (b *Breaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := BreakerStats{State: b.state, ConsecutiveFailures: b.failures}
+	if b.state == StateOpen {
+		if time.Now().Before(b.openUntil) {
+			stats.OpenUntil = b.openUntil
+		} else {
+			stats.State = StateHalfOpen
+		}
+	}
+	return stats
+}
+
+func (b *Breaker) setState(state State) {
+	switch state {
+	case StateOpen:
+		b.logger.Warnf("circuit breaker %s opened after %d consecutive failures, retry after %s", b.name, b.failures, b.openUntil.Format(time.RFC3339))
+	case StateHalfOpen:
+		b.logger.Infof("circuit breaker %s half-open, sending probe request", b.name)
+	case StateClosed:
+		b.logger.Infof("circuit breaker %s closed", b.name)
+	}
+	b.state = state
+}
@@ -0,0 +1,178 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/22Fariz22/musiclab/pkg/logger"
+)
+
+// Config настройки повторов и автомата
+type Config struct {
+	// MaxRetries общее количество попыток, минимум одна
+	MaxRetries int
+	// BaseDelay пауза перед второй попыткой, дальше удваивается
+	BaseDelay time.Duration
+	// MaxDelay верхняя граница паузы, в том числе из Retry-After
+	MaxDelay time.Duration
+	// Timeout таймаут одной попытки
+	Timeout time.Duration
+	// BreakerThreshold неудач подряд до размыкания автомата
+	BreakerThreshold int
+	// BreakerCooldown на сколько размыкается автомат
+	BreakerCooldown time.Duration
+}
+
+// Client HTTP-клиент с повторами по экспоненте с джиттером и автоматическим выключателем.
+// Повторяются только сетевые ошибки, 5xx и 429; ответы 4xx возвращаются сразу.
+type Client struct {
+	name       string
+	cfg        Config
+	httpClient *http.Client
+	breaker    *Breaker
+	logger     logger.Logger
+}
+
+// New клиент для upstream с именем name, имя попадает в логи и health
+func New(name string, cfg Config, logger logger.Logger) *Client {
+	if cfg.MaxRetries < 1 {
+		cfg.MaxRetries = 1
+	}
+
+	return &Client{
+		name:       name,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		breaker:    NewBreaker(name, cfg.BreakerThreshold, cfg.BreakerCooldown, logger),
+		logger:     logger,
+	}
+}
+
+// Breaker состояние автомата клиента
+func (c *Client) Breaker() BreakerStats {
+	return c.breaker.Stats()
+}
+
+// Do отправляет запрос с повторами. Тело запроса должно перечитываться через GetBody
+// (http.NewRequest делает это для bytes/strings Reader). Если попытки кончились на 5xx или 429,
+// возвращается последний ответ - статус проверяет вызывающий.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		if err := c.breaker.Allow(); err != nil {
+			return nil, fmt.Errorf("%s: %w", c.name, err)
+		}
+
+		resp, err := c.httpClient.Do(c.attemptRequest(req, attempt))
+		retryable, wait := c.classify(ctx, resp, err, attempt)
+
+		if !retryable || attempt >= c.cfg.MaxRetries {
+			return resp, err
+		}
+
+		if err != nil {
+			c.logger.Warnf("%s: attempt %d/%d failed: %v, retrying in %s", c.name, attempt, c.cfg.MaxRetries, err, wait)
+		} else {
+			c.logger.Warnf("%s: attempt %d/%d returned %d, retrying in %s", c.name, attempt, c.cfg.MaxRetries, resp.StatusCode, wait)
+			// Соединение можно переиспользовать, только если тело дочитано
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, fmt.Errorf("%s: waiting for retry: %w", c.name, err)
+		}
+	}
+}
+
+// attemptRequest копия запроса со свежим телом для повторной попытки
+func (c *Client) attemptRequest(req *http.Request, attempt int) *http.Request {
+	if attempt == 1 || req.GetBody == nil {
+		return req
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return req
+	}
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	return retry
+}
+
+// classify сообщает автомату результат попытки и решает, повторять ли ее и сколько ждать
+func (c *Client) classify(ctx context.Context, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if err != nil {
+		// Запрос отменен или истек дедлайн вызывающего - не вина upstream
+		if ctx.Err() != nil {
+			c.breaker.Release()
+			return false, 0
+		}
+		c.breaker.Failure()
+		return true, c.backoff(attempt)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		// 429 - upstream жив и просит подождать, размыкать автомат из-за этого не нужно
+		if resp.StatusCode == http.StatusTooManyRequests {
+			c.breaker.Release()
+		} else {
+			c.breaker.Failure()
+		}
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return true, min(wait, c.cfg.MaxDelay)
+		}
+		return true, c.backoff(attempt)
+	}
+
+	c.breaker.Success()
+	return false, 0
+}
+
+// backoff экспоненциальная пауза с полным джиттером: случайное значение от 0 до BaseDelay * 2^(attempt-1)
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.cfg.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > c.cfg.MaxDelay {
+		ceiling = c.cfg.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// retryAfter разбирает заголовок Retry-After: количество секунд или HTTP-дата
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// sleep пауза, прерываемая отменой контекста
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
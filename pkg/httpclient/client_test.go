@@ -0,0 +1,156 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/22Fariz22/musiclab/pkg/httpclient"
+	"github.com/22Fariz22/musiclab/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() httpclient.Config {
+	return httpclient.Config{
+		MaxRetries:       3,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         10 * time.Millisecond,
+		Timeout:          time.Second,
+		BreakerThreshold: 100,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+// statusServer отвечает статусами по очереди, последний повторяется
+func statusServer(t *testing.T, calls *int32, statuses ...int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(calls, 1))
+		status := statuses[min(n, len(statuses))-1]
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, ctx context.Context, client *httpclient.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	return client.Do(req)
+}
+
+func TestDo_RetriesServerErrorsAndTooManyRequests(t *testing.T) {
+	var calls int32
+	server := statusServer(t, &calls, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+	client := httpclient.New("test", testConfig(), utils.CreateTestLogger())
+
+	resp, err := get(t, context.Background(), client, server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	assert.Equal(t, httpclient.StateClosed, client.Breaker().State)
+}
+
+func TestDo_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := statusServer(t, &calls, http.StatusNotFound)
+	client := httpclient.New("test", testConfig(), utils.CreateTestLogger())
+
+	resp, err := get(t, context.Background(), client, server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestDo_WaitIsCancelledWithContext(t *testing.T) {
+	var calls int32
+	server := statusServer(t, &calls, http.StatusInternalServerError)
+	cfg := testConfig()
+	cfg.BaseDelay, cfg.MaxDelay = time.Hour, time.Hour
+	client := httpclient.New("test", cfg, utils.CreateTestLogger())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	_, err := get(t, ctx, client, server.URL)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestDo_BreakerOpensAndRejects(t *testing.T) {
+	var calls int32
+	server := statusServer(t, &calls, http.StatusBadGateway)
+	cfg := testConfig()
+	cfg.MaxRetries = 1
+	cfg.BreakerThreshold = 2
+	client := httpclient.New("test", cfg, utils.CreateTestLogger())
+
+	for i := 0; i < 2; i++ {
+		resp, err := get(t, context.Background(), client, server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, httpclient.StateOpen, client.Breaker().State)
+
+	_, err := get(t, context.Background(), client, server.URL)
+	assert.ErrorIs(t, err, httpclient.ErrCircuitOpen)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls), "open circuit must not reach upstream")
+}
+
+func TestDo_HalfOpenProbeClosesBreaker(t *testing.T) {
+	var calls int32
+	server := statusServer(t, &calls, http.StatusBadGateway, http.StatusOK)
+	cfg := testConfig()
+	cfg.MaxRetries = 1
+	cfg.BreakerThreshold = 1
+	cfg.BreakerCooldown = 10 * time.Millisecond
+	client := httpclient.New("test", cfg, utils.CreateTestLogger())
+
+	resp, err := get(t, context.Background(), client, server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, httpclient.StateOpen, client.Breaker().State)
+
+	time.Sleep(20 * time.Millisecond)
+	stats := client.Breaker()
+	assert.Equal(t, httpclient.StateHalfOpen, stats.State, "cooldown is over, next request is a probe")
+	assert.True(t, stats.OpenUntil.IsZero())
+
+	resp, err = get(t, context.Background(), client, server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, httpclient.StateClosed, client.Breaker().State)
+}
+
+func TestDo_TooManyRequestsDoesNotOpenBreaker(t *testing.T) {
+	var calls int32
+	server := statusServer(t, &calls, http.StatusTooManyRequests)
+	cfg := testConfig()
+	cfg.MaxRetries = 1
+	cfg.BreakerThreshold = 1
+	client := httpclient.New("test", cfg, utils.CreateTestLogger())
+
+	for i := 0; i < 3; i++ {
+		resp, err := get(t, context.Background(), client, server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	}
+
+	stats := client.Breaker()
+	assert.Equal(t, httpclient.StateClosed, stats.State)
+	assert.Zero(t, stats.ConsecutiveFailures)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,192 @@
+// Package langdetect определяет язык текста без обращения к сети.
+//
+// Языки с собственной письменностью (греческий, корейский, японский, ...) определяются по алфавиту.
+// Для латиницы и кириллицы считаются частые служебные слова и характерные буквы каждого языка,
+// поэтому короткие тексты и тексты на смеси языков получают низкую уверенность.
+package langdetect
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Undetermined код языка, который не удалось определить (BCP 47)
+const Undetermined = "und"
+
+const (
+	// minLetters меньше букв - язык не определяется
+	minLetters = 10
+
+	// minHits столько совпадений со словарем языка дают полную уверенность
+	minHits = 5
+)
+
+// Result язык текста и уверенность от 0 до 1, пустой Language - язык не определен
+type Result struct {
+	Language   string
+	Confidence float64
+}
+
+// Письменности, язык которых выбирается по словарям
+const (
+	latin    = "latin"
+	cyrillic = "cyrillic"
+	han      = "han"
+)
+
+// scripts письменности и их языки; иероглифы без каны считаются китайским
+var scripts = []struct {
+	table *unicode.RangeTable
+	name  string
+}{
+	{unicode.Latin, latin},
+	{unicode.Cyrillic, cyrillic},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, han},
+	{unicode.Thai, "th"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Georgian, "ka"},
+	{unicode.Armenian, "hy"},
+}
+
+// profile частые слова и характерные буквы языка
+type profile struct {
+	language string
+	words    map[string]bool
+	letters  string
+}
+
+func newProfile(language, words, letters string) profile {
+	p := profile{language: language, words: map[string]bool{}, letters: letters}
+	for _, word := range strings.Fields(words) {
+		p.words[word] = true
+	}
+	return p
+}
+
+var latinProfiles = []profile{
+	newProfile("en", "the and you i to a me my it in of is that on your we for be all with this love don't i'm it's not what so just no oh can know are was but when like", ""),
+	newProfile("de", "und ich die der das nicht du ist ein eine es mit sie wir zu den auf dich mich mir für sind was wie auch dann noch nur im ja nein mein dein", "ßäü"),
+	newProfile("fr", "le la les et je tu de des un une est pas que qui dans pour mon ma mes ton ta moi toi il elle nous vous sur c'est j'ai ne au avec plus", "èêùçœ"),
+	newProfile("es", "el la los las y que de en un una es no me mi tu te por con para lo se yo amor como pero más del al quiero eres todo", "ñ"),
+	newProfile("pt", "o a os as e que de do da em um uma é não me eu você meu minha com para se mais por tu te no na coração sem", "ãõç"),
+	newProfile("it", "il la lo le gli e che di un una è non mi ti io tu per con sono ma come ho del della nel più amore cuore sei", "òù"),
+	newProfile("nl", "de het een en ik je is niet van dat op in met wat zijn maar mijn jij we er voor ook als nog naar", ""),
+	newProfile("sv", "och jag du det att en är som inte på med för min har den vi så kan till mig dig", "å"),
+	newProfile("pl", "i w nie się na że to jest z do ja ty mnie mi jak co tak ale po czy już tylko być", "łąęśźżńć"),
+	newProfile("tr", "ve bir bu ben sen o ne de da çok için gibi ama beni seni var yok mi değil daha", "ğşı"),
+}
+
+var cyrillicProfiles = []profile{
+	newProfile("ru", "и в не я на что ты с как это мне меня а но по все всё так тебя мы он она за же ещё еще из у бы только", "ыэё"),
+	newProfile("uk", "і в не я на що ти з як це мені мене а але по все так тебе ми він вона за же ще із у б тільки", "іїєґ"),
+	newProfile("bg", "и в не аз на че ти с как това ме мен а но по всичко така те ние той тя за да е съм си", ""),
+}
+
+// Detect определяет язык текста
+func Detect(text string) Result {
+	counts := map[string]int{}
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range scripts {
+			if unicode.Is(s.table, r) {
+				counts[s.name]++
+				break
+			}
+		}
+	}
+	if letters < minLetters {
+		return Result{}
+	}
+
+	// Японский текст пишется кандзи вместе с каной
+	if counts["ja"] > 0 && counts["ja"]*10 >= counts["ja"]+counts[han] {
+		counts["ja"] += counts[han]
+	} else {
+		counts["zh"] += counts[han]
+	}
+	delete(counts, han)
+
+	dominant := ""
+	for name, count := range counts {
+		if dominant == "" || count > counts[dominant] || (count == counts[dominant] && name < dominant) {
+			dominant = name
+		}
+	}
+	share := float64(counts[dominant]) / float64(letters)
+
+	switch dominant {
+	case latin:
+		return detectByProfiles(text, latinProfiles, share)
+	case cyrillic:
+		return detectByProfiles(text, cyrillicProfiles, share)
+	case "":
+		return Result{}
+	}
+
+	return Result{Language: dominant, Confidence: round(share)}
+}
+
+// detectByProfiles выбирает язык с наибольшим числом совпадений.
+// Уверенность падает, если у второго языка почти столько же совпадений или совпадений мало.
+func detectByProfiles(text string, profiles []profile, share float64) Result {
+	hits := make([]int, len(profiles))
+	for _, word := range words(text) {
+		for i, p := range profiles {
+			if p.words[word] || (p.letters != "" && strings.ContainsAny(word, p.letters)) {
+				hits[i]++
+			}
+		}
+	}
+
+	best, second := -1, -1
+	for i := range hits {
+		if best == -1 || hits[i] > hits[best] {
+			best, second = i, best
+		} else if second == -1 || hits[i] > hits[second] {
+			second = i
+		}
+	}
+	if hits[best] == 0 {
+		return Result{}
+	}
+
+	ratio := 1.0
+	if second != -1 {
+		ratio = float64(hits[best]) / float64(hits[best]+hits[second])
+	}
+	evidence := math.Min(1, float64(hits[best])/minHits)
+
+	return Result{Language: profiles[best].language, Confidence: round(share * ratio * evidence)}
+}
+
+// words слова текста в нижнем регистре, апострофы остаются частью слова (don't, c'est)
+func words(text string) []string {
+	text = strings.ToLower(strings.ReplaceAll(text, "’", "'"))
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	result := fields[:0]
+	for _, field := range fields {
+		if field = strings.Trim(field, "'"); field != "" {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// round уверенность с точностью до сотых
+func round(confidence float64) float64 {
+	return math.Round(confidence*100) / 100
+}
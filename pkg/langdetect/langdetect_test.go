@@ -0,0 +1,48 @@
+package langdetect_test
+
+import (
+	"testing"
+
+	"github.com/22Fariz22/musiclab/pkg/langdetect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "Paranoia is in bloom, the PR transmissions will resume\nThey'll try to push drugs that keep us all dumbed down and hope that we will never see the truth around", "en"},
+		{"russian", "Я хочу быть с тобой, и я буду с тобой.\nВ комнате с белым потолком, с правом на надежду, ты всё ещё не знаешь, как мне жаль", "ru"},
+		{"ukrainian", "Я не знаю, що це таке, але ти мені потрібна.\nЇї очі світяться, і він тільки чекає на неї", "uk"},
+		{"german", "Du hast mich gefragt und ich hab nichts gesagt.\nWillst du bis der Tod euch scheidet treu ihr sein für alle Tage", "de"},
+		{"spanish", "Quiero saber si tú me quieres como yo te quiero a ti, mi amor, porque sin ti no puedo vivir", "es"},
+		{"japanese", "君の名前を呼んでいた夜に、僕はただ空を見上げていた", "ja"},
+		{"korean", "너를 사랑해 오늘도 내일도 영원히 함께 있고 싶어", "ko"},
+		{"greek", "Σ' αγαπώ και θα σ' αγαπώ για πάντα μέσα στην καρδιά μου", "el"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := langdetect.Detect(tt.text)
+			assert.Equal(t, tt.want, result.Language)
+			assert.Greater(t, result.Confidence, 0.3)
+			assert.LessOrEqual(t, result.Confidence, 1.0)
+		})
+	}
+}
+
+func TestDetect_Undetermined(t *testing.T) {
+	for _, text := range []string{"", "la la", "1234 5678 !!!", "xyzzy plugh qwrtp grbl"} {
+		assert.Equal(t, langdetect.Result{}, langdetect.Detect(text), text)
+	}
+}
+
+func TestDetect_ShortTextHasLowConfidence(t *testing.T) {
+	short := langdetect.Detect("hello, and goodbye now")
+	long := langdetect.Detect("I know that you and me are in love, and it's all that I want, so don't let me go when the night is over")
+
+	assert.Equal(t, "en", short.Language)
+	assert.Equal(t, "en", long.Language)
+	assert.Less(t, short.Confidence, long.Confidence)
+}
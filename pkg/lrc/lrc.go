@@ -0,0 +1,172 @@
+// Package lrc разбирает синхронизированные тексты песен в формате LRC:
+//
+//	[ar:Muse]
+//	[offset:+250]
+//	[00:12.30]Paranoia is in bloom
+//	[00:45.00][01:30.50]They will not force us
+//
+// Строка может иметь несколько меток времени (повторяющийся припев),
+// метки слов расширенного формата (<00:12.50>) из текста удаляются.
+package lrc
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Line строка текста и момент ее начала
+type Line struct {
+	Time time.Duration
+	Text string
+}
+
+// Lyrics разобранный LRC
+type Lyrics struct {
+	// Tags теги заголовка ([ar:], [ti:], [al:], ...) без offset
+	Tags map[string]string
+
+	// Offset сдвиг из тега [offset:], уже учтен во времени строк
+	Offset time.Duration
+
+	// Lines строки по возрастанию времени
+	Lines []Line
+}
+
+// ParseError ошибка в строке LRC
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// ParseErrors все ошибки разбора, чтобы исправить файл за один раз
+type ParseErrors []ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+var (
+	// timeTagRe метка времени [mm:ss], [mm:ss.x], [mm:ss.xx] или [mm:ss.xxx] в начале строки
+	timeTagRe = regexp.MustCompile(`^\[(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+
+	// infoTagRe тег заголовка [key:value]
+	infoTagRe = regexp.MustCompile(`^\[([A-Za-z#]+):(.*)\]$`)
+
+	// wordTagRe метка слова расширенного LRC
+	wordTagRe = regexp.MustCompile(`<\d{1,3}:\d{1,2}(?:[.:]\d{1,3})?>`)
+)
+
+// Parse разбирает и проверяет LRC. Пустые строки пропускаются,
+// строка текста без метки времени и некорректная метка - ошибки.
+func Parse(text string) (Lyrics, error) {
+	lyrics := Lyrics{Tags: map[string]string{}}
+	var errs ParseErrors
+
+	for i, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		lineNumber := i + 1
+		line := strings.TrimSpace(strings.TrimPrefix(raw, "\ufeff"))
+		if line == "" {
+			continue
+		}
+
+		if !timeTagRe.MatchString(line) {
+			tag := infoTagRe.FindStringSubmatch(line)
+			if tag == nil {
+				errs = append(errs, ParseError{Line: lineNumber, Msg: "line has no timestamp"})
+				continue
+			}
+
+			key, value := strings.ToLower(tag[1]), strings.TrimSpace(tag[2])
+			if key != "offset" {
+				lyrics.Tags[key] = value
+				continue
+			}
+
+			offset, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, ParseError{Line: lineNumber, Msg: fmt.Sprintf("invalid offset %q", value)})
+				continue
+			}
+			lyrics.Offset = time.Duration(offset) * time.Millisecond
+			continue
+		}
+
+		// Все метки времени в начале строки относятся к одному тексту
+		var times []time.Duration
+		valid := true
+		for {
+			match := timeTagRe.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			line = line[len(match[0]):]
+
+			t, err := parseTime(match[1], match[2], match[3])
+			if err != nil {
+				errs = append(errs, ParseError{Line: lineNumber, Msg: err.Error()})
+				valid = false
+				break
+			}
+			times = append(times, t)
+		}
+		if !valid {
+			continue
+		}
+
+		lineText := strings.TrimSpace(wordTagRe.ReplaceAllString(line, ""))
+		for _, t := range times {
+			lyrics.Lines = append(lyrics.Lines, Line{Time: t, Text: lineText})
+		}
+	}
+
+	if len(errs) > 0 {
+		return Lyrics{}, errs
+	}
+	if len(lyrics.Lines) == 0 {
+		return Lyrics{}, ParseErrors{{Line: 0, Msg: "no timestamped lines"}}
+	}
+
+	// Положительный offset показывает текст раньше
+	for i := range lyrics.Lines {
+		lyrics.Lines[i].Time = max(lyrics.Lines[i].Time-lyrics.Offset, 0)
+	}
+	sort.SliceStable(lyrics.Lines, func(i, j int) bool {
+		return lyrics.Lines[i].Time < lyrics.Lines[j].Time
+	})
+
+	return lyrics, nil
+}
+
+// parseTime переводит части метки в длительность, доли секунды дополняются нулями справа (.5 = 500 мс)
+func parseTime(minutes, seconds, fraction string) (time.Duration, error) {
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(seconds)
+	if s >= 60 {
+		return 0, fmt.Errorf("invalid timestamp %s:%s: seconds must be less than 60", minutes, seconds)
+	}
+
+	var ms int
+	if fraction != "" {
+		ms, _ = strconv.Atoi(fraction + strings.Repeat("0", 3-len(fraction)))
+	}
+
+	return time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
+
+// FormatTime метка времени в виде mm:ss.xx
+func FormatTime(t time.Duration) string {
+	centiseconds := t.Milliseconds() / 10
+	return fmt.Sprintf("%02d:%02d.%02d", centiseconds/6000, centiseconds/100%60, centiseconds%100)
+}
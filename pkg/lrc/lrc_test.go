@@ -0,0 +1,54 @@
+package lrc_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/22Fariz22/musiclab/pkg/lrc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	lyrics, err := lrc.Parse("\ufeff[ar:Muse]\r\n[ti:Uprising]\n\n[00:45.00][00:12.5]They will not <00:13.10>force us\n[00:20.123]Paranoia is in bloom\n[01:02]\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"ar": "Muse", "ti": "Uprising"}, lyrics.Tags)
+	assert.Equal(t, []lrc.Line{
+		{Time: 12500 * time.Millisecond, Text: "They will not force us"},
+		{Time: 20123 * time.Millisecond, Text: "Paranoia is in bloom"},
+		{Time: 45 * time.Second, Text: "They will not force us"},
+		{Time: 62 * time.Second, Text: ""},
+	}, lyrics.Lines)
+}
+
+func TestParse_AppliesOffset(t *testing.T) {
+	lyrics, err := lrc.Parse("[offset:+500]\n[00:00.20]first\n[00:02.00]second")
+	require.NoError(t, err)
+
+	assert.Equal(t, 500*time.Millisecond, lyrics.Offset)
+	assert.Equal(t, time.Duration(0), lyrics.Lines[0].Time)
+	assert.Equal(t, 1500*time.Millisecond, lyrics.Lines[1].Time)
+}
+
+func TestParse_ReportsEveryInvalidLine(t *testing.T) {
+	_, err := lrc.Parse("[00:01.00]ok\nplain text\n[00:75.00]bad seconds\n[offset:soon]")
+
+	var parseErrs lrc.ParseErrors
+	require.True(t, errors.As(err, &parseErrs))
+	require.Len(t, parseErrs, 3)
+	assert.Equal(t, 2, parseErrs[0].Line)
+	assert.Equal(t, 3, parseErrs[1].Line)
+	assert.Equal(t, 4, parseErrs[2].Line)
+}
+
+func TestParse_RequiresTimedLines(t *testing.T) {
+	_, err := lrc.Parse("[ar:Muse]\n")
+	assert.Error(t, err)
+}
+
+func TestFormatTime(t *testing.T) {
+	assert.Equal(t, "00:12.34", lrc.FormatTime(12345*time.Millisecond))
+	assert.Equal(t, "61:05.00", lrc.FormatTime(61*time.Minute+5*time.Second))
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// releaseDateLayouts форматы дат выхода, которые приходят от API и от пользователей
+var releaseDateLayouts = []string{
+	"2.1.2006",   // dd.mm.yyyy
+	"2006-01-02", // yyyy-mm-dd
+	"2006",       // только год, считаем 1 января
+}
+
+// ParseReleaseDate разбирает дату выхода в форматах dd.mm.yyyy, yyyy-mm-dd или yyyy
+func ParseReleaseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range releaseDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported release date format: %q", value)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReleaseDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "dd.mm.yyyy", input: "16.07.2006", want: time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)},
+		{name: "d.m.yyyy", input: "1.2.2006", want: time.Date(2006, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "iso", input: "2006-07-16", want: time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)},
+		{name: "year only", input: "2009", want: time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "surrounding spaces", input: " 2009-09-14 ", want: time.Date(2009, 9, 14, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day", input: "29.02.2020", want: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "day out of range", input: "31.02.2020", wantErr: true},
+		{name: "not a leap year", input: "29.02.2019", wantErr: true},
+		{name: "iso month out of range", input: "2020-13-01", wantErr: true},
+		{name: "month out of range", input: "01.13.2020", wantErr: true},
+		{name: "american order", input: "07/16/2006", wantErr: true},
+		{name: "short year", input: "16.07.06", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "text", input: "summer 2006", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReleaseDate(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestValidator_ReleaseDate(t *testing.T) {
+	type request struct {
+		ReleaseDate string `validate:"omitempty,release_date"`
+	}
+
+	tests := []struct {
+		input string
+		valid bool
+	}{
+		{input: "", valid: true},
+		{input: "16.07.2006", valid: true},
+		{input: "2006-07-16", valid: true},
+		{input: "2006", valid: true},
+		{input: "31.02.2020", valid: false},
+		{input: "2020-13-01", valid: false},
+		{input: "yesterday", valid: false},
+	}
+
+	v := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := v.Struct(request{ReleaseDate: tt.input})
+			assert.Equal(t, tt.valid, err == nil, "error: %v", err)
+		})
+	}
+}
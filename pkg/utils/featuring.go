@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// featuringPattern "Name feat. A", "Name (ft. A & B)", "Name [featuring A, B]"
+var featuringPattern = regexp.MustCompile(`(?i)^(.*?)\s*[(\[]?\s*\b(?:feat\.?|ft\.?|featuring)\s+([^)\]]+?)\s*[)\]]?\s*$`)
+
+// featuringSeparator разделитель приглашенных участников. "and" не разделяет, чтобы не разбить имена вроде "Florence and the Machine".
+var featuringSeparator = regexp.MustCompile(`\s*[,&]\s*`)
+
+// SplitFeaturing отделяет от имени группы или песни приглашенных участников после feat./ft./featuring.
+// Имя без приглашенных участников возвращается нормализованным и без изменений по сути.
+func SplitFeaturing(name string) (string, []string) {
+	name = NormalizeName(name)
+
+	match := featuringPattern.FindStringSubmatch(name)
+	if match == nil || match[1] == "" {
+		return name, nil
+	}
+
+	var featured []string
+	for _, artist := range featuringSeparator.Split(match[2], -1) {
+		if artist = strings.TrimSpace(artist); artist != "" {
+			featured = append(featured, artist)
+		}
+	}
+	if len(featured) == 0 {
+		return name, nil
+	}
+
+	return match[1], featured
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitFeaturing(t *testing.T) {
+	tests := []struct {
+		input    string
+		name     string
+		featured []string
+	}{
+		{"Daft Punk feat. Pharrell Williams", "Daft Punk", []string{"Pharrell Williams"}},
+		{"Get Lucky (ft. Pharrell & Nile Rodgers)", "Get Lucky", []string{"Pharrell", "Nile Rodgers"}},
+		{"Song [Featuring A, B]", "Song", []string{"A", "B"}},
+		{"Eminem FT Rihanna", "Eminem", []string{"Rihanna"}},
+		{"Florence and the Machine", "Florence and the Machine", nil},
+		{"Loft 2 Daft", "Loft 2 Daft", nil},
+		{"Little Feat", "Little Feat", nil},
+		{"feat. Nobody", "feat. Nobody", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			name, featured := SplitFeaturing(tt.input)
+			require.Equal(t, tt.name, name)
+			require.Equal(t, tt.featured, featured)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// nameReplacer приводит типографские кавычки, апострофы и тире к ASCII
+var nameReplacer = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'", "´", "'", "`", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`, "«", `"`, "»", `"`,
+	"‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-", "―", "-", "−", "-",
+)
+
+// NormalizeName приводит имя группы или песни к виду для хранения: Unicode NFC, ASCII кавычки и тире,
+// без пробелов по краям, подряд идущие пробельные символы схлопнуты в один пробел
+func NormalizeName(name string) string {
+	name = nameReplacer.Replace(norm.NFC.String(name))
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NameKey ключ имени для проверки уникальности и поиска: нормализованное имя без учета регистра.
+// "Muse" и " muse", "é" в составной и разложенной форме дают один ключ.
+func NameKey(name string) string {
+	// Caser хранит состояние, поэтому создается на каждый вызов
+	return norm.NFC.String(cases.Fold().String(NormalizeName(name)))
+}
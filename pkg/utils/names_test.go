@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"trims and collapses whitespace", "  Muse \t\n Live  ", "Muse Live"},
+		{"composes decomposed letters", "Beyonce\u0301", "Beyonc\u00e9"},
+		{"normalizes quotes", "Don’t «Stop» “Me”", `Don't "Stop" "Me"`},
+		{"normalizes dashes", "AC–DC — Live", "AC-DC - Live"},
+		{"keeps case", "MUSE", "MUSE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NormalizeName(tt.input))
+		})
+	}
+}
+
+func TestNameKey(t *testing.T) {
+	require.Equal(t, NameKey("Muse"), NameKey(" muse "))
+	require.Equal(t, NameKey("Beyonc\u00e9"), NameKey("BEYONCE\u0301"))
+	require.Equal(t, NameKey("Don't Stop"), NameKey("don’t  stop"))
+	require.Equal(t, NameKey("Straße"), NameKey("STRASSE"))
+	require.NotEqual(t, NameKey("Muse"), NameKey("Muser"))
+}
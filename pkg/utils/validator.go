@@ -10,7 +10,20 @@ import (
 var validate *validator.Validate
 
 func init() {
-	validate = validator.New()
+	validate = NewValidator()
+}
+
+// NewValidator validator с правилами проекта
+func NewValidator() *validator.Validate {
+	v := validator.New()
+
+	// release_date - дата выхода в одном из форматов ParseReleaseDate
+	_ = v.RegisterValidation("release_date", func(fl validator.FieldLevel) bool {
+		_, err := ParseReleaseDate(fl.Field().String())
+		return err == nil
+	})
+
+	return v
 }
 
 // Validate struct fields